@@ -48,8 +48,9 @@ func (e httpError) Error() string {
 	return "server responded " + e.Response.Status
 }
 
-func NewApiClient(host string) *ApiClient {
-	var agent string
+// DefaultUserAgent returns the default user agent, which includes the nx module version.
+// Returns an empty string if the build information is not available.
+func DefaultUserAgent() (agent string) {
 	bi, ok := debug.ReadBuildInfo()
 	if ok {
 		version := bi.Main.Version
@@ -58,10 +59,14 @@ func NewApiClient(host string) *ApiClient {
 		}
 		agent = "nx/" + version
 	}
+	return
+}
+
+func NewApiClient(host string) *ApiClient {
 	client := &ApiClient{
 		Http:     &http.Client{},
 		Host:     host,
-		Agent:    agent,
+		Agent:    DefaultUserAgent(),
 		CheckBan: true,
 	}
 	client.Http.Transport = &apiClientRt{
@@ -0,0 +1,28 @@
+package archive
+
+import (
+	"github.com/spf13/cobra"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	"xabbo.io/nx/gamedata/archive"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Records and queries the game data history of hotels",
+}
+
+// Dir is the archive directory.
+var Dir string
+
+func init() {
+	pf := Cmd.PersistentFlags()
+	pf.StringVar(&Dir, "dir", archive.DefaultDir(), "The archive directory")
+
+	_root.Cmd.AddCommand(Cmd)
+}
+
+// Open opens the archive directory.
+func Open() (*archive.Archive, error) {
+	return archive.Open(Dir)
+}
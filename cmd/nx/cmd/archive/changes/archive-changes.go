@@ -0,0 +1,90 @@
+package changes
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx/gamedata/archive"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/archive"
+	"xabbo.io/nx/cmd/nx/spinner"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "changes <date>",
+	Short: "Show the furni changes observed on a date (YYYY-MM-DD)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runChanges,
+}
+
+func init() {
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runChanges(cmd *cobra.Command, args []string) (err error) {
+	date, err := time.ParseInLocation(time.DateOnly, args[0], time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, must be in the format YYYY-MM-DD", args[0])
+	}
+
+	cmd.SilenceUsage = true
+
+	arc, err := _parent.Open()
+	if err != nil {
+		return
+	}
+
+	var changes []archive.FurniChanges
+	err = spinner.DoErr("Loading archive...", func() (err error) {
+		changes, err = arc.ChangesOn(_root.Host, date)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("No furni data observed on %s.\n", args[0])
+		return
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stdout)
+
+	for _, change := range changes {
+		l.AppendItem(fmt.Sprintf("%s %s", change.Entry.Time.Local().Format(time.DateTime), change.Entry.Hash))
+		l.Indent()
+		if change.Previous == nil {
+			l.AppendItem(fmt.Sprintf("First archived furni data (%d furni)", len(change.Diff.Added)))
+			l.UnIndent()
+			continue
+		}
+		for _, fi := range change.Diff.Added {
+			l.AppendItem(fmt.Sprintf("added %s [%s]", fi.Name, fi.Identifier))
+		}
+		for _, fi := range change.Diff.Removed {
+			l.AppendItem(fmt.Sprintf("removed %s [%s]", fi.Name, fi.Identifier))
+		}
+		for _, changed := range change.Diff.Changed {
+			l.AppendItem(fmt.Sprintf("changed %s [%s]", changed.After.Name, changed.Identifier))
+			l.Indent()
+			for _, field := range changed.Changes {
+				l.AppendItem(field.String())
+			}
+			l.UnIndent()
+		}
+		if change.Diff.Empty() {
+			l.AppendItem("No changes")
+		}
+		l.UnIndent()
+	}
+
+	l.Render()
+	return
+}
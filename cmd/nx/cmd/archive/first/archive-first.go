@@ -0,0 +1,50 @@
+package first
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx/gamedata/archive"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/archive"
+	"xabbo.io/nx/cmd/nx/spinner"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "first <identifier>",
+	Short: "Show when a furni first appeared in the archive",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFirst,
+}
+
+func init() {
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runFirst(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	arc, err := _parent.Open()
+	if err != nil {
+		return
+	}
+
+	var entry archive.Entry
+	err = spinner.DoErr("Searching archive...", func() (err error) {
+		entry, _, err = arc.FirstSeen(_root.Host, args[0])
+		return
+	})
+	if errors.Is(err, archive.ErrNotFound) {
+		return fmt.Errorf("%q was not found in the archive for %s", args[0], _root.Host)
+	}
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("%s %s\n", entry.Time.Local().Format(time.DateTime), entry.Hash)
+	return
+}
@@ -0,0 +1,57 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx/gamedata/archive"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/archive"
+	"xabbo.io/nx/cmd/nx/spinner"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "history <identifier>",
+	Short: "Show the revision history of a furni in the archive",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistory,
+}
+
+func init() {
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	arc, err := _parent.Open()
+	if err != nil {
+		return
+	}
+
+	var history []archive.FurniRevision
+	err = spinner.DoErr("Searching archive...", func() (err error) {
+		history, err = arc.RevisionHistory(_root.Host, args[0])
+		return
+	})
+	if errors.Is(err, archive.ErrNotFound) {
+		return fmt.Errorf("%q was not found in the archive for %s", args[0], _root.Host)
+	}
+	if err != nil {
+		return
+	}
+
+	for _, revision := range history {
+		timestamp := revision.Entry.Time.Local().Format(time.DateTime)
+		if revision.Info == nil {
+			fmt.Printf("%s removed\n", timestamp)
+		} else {
+			fmt.Printf("%s revision %d (%s)\n", timestamp, revision.Info.Revision, revision.Info.Name)
+		}
+	}
+	return
+}
@@ -0,0 +1,51 @@
+package list
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	gd "xabbo.io/nx/gamedata"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/archive"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "list [type...]",
+	Short: "List the archived game data of the hotel",
+	RunE:  runList,
+}
+
+func init() {
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runList(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	arc, err := _parent.Open()
+	if err != nil {
+		return
+	}
+
+	var types []gd.Type
+	for _, arg := range args {
+		types = append(types, gd.Type(arg))
+	}
+
+	entries, err := arc.Entries(_root.Host, types...)
+	if err != nil {
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No archived game data for %s.\n", _root.Host)
+		return
+	}
+	for _, entry := range entries {
+		fmt.Printf("%s %s %s\n", entry.Time.Local().Format(time.DateTime), entry.Type, entry.Hash)
+	}
+	return
+}
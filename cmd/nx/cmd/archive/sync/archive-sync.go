@@ -0,0 +1,86 @@
+package sync
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/gamedata/archive"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/archive"
+	"xabbo.io/nx/cmd/nx/spinner"
+	"xabbo.io/nx/cmd/nx/util"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Archive the current game data of the hotel",
+	Args:  cobra.NoArgs,
+	RunE:  runSync,
+}
+
+var opts struct {
+	types       []string
+	importCache bool
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringSliceVarP(&opts.types, "types", "t", nil,
+		fmt.Sprintf("The game data types to archive: %s", util.CommaList(gd.HashedTypes, "or")))
+	f.BoolVar(&opts.importCache, "import-cache", false, "Also archive game data found in the cache directory")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) (err error) {
+	var types []gd.Type
+	for _, t := range opts.types {
+		if !slices.Contains(gd.HashedTypes, gd.Type(t)) {
+			return fmt.Errorf("invalid game data type %q, must be one of %s",
+				t, util.CommaList(gd.HashedTypes, "or"))
+		}
+		types = append(types, gd.Type(t))
+	}
+
+	cmd.SilenceUsage = true
+
+	arc, err := _parent.Open()
+	if err != nil {
+		return
+	}
+
+	var added []archive.Entry
+	if opts.importCache {
+		err = spinner.DoErr("Importing cached game data...", func() (err error) {
+			added, err = arc.ImportCache(_root.CacheDir(), _root.Host, types...)
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	mgr := gd.NewManager(_root.Host, _root.ManagerOptions()...).(gd.HashManager)
+	err = spinner.DoErr("Syncing game data...", func() error {
+		synced, err := arc.Sync(_root.Host, mgr, types...)
+		added = append(added, synced...)
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	if len(added) == 0 {
+		fmt.Println("No new game data.")
+		return
+	}
+	for _, entry := range added {
+		fmt.Printf("%s %s %s\n", entry.Time.Local().Format(time.DateTime), entry.Type, entry.Hash)
+	}
+	return
+}
@@ -0,0 +1,16 @@
+package convert
+
+import (
+	"github.com/spf13/cobra"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Converts asset libraries between formats",
+}
+
+func init() {
+	_root.Cmd.AddCommand(Cmd)
+}
@@ -0,0 +1,120 @@
+package nitro
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"b7c.io/swfx"
+
+	"xabbo.io/nx/raw/nitro"
+	"xabbo.io/nx/res"
+
+	_parent "xabbo.io/nx/cmd/nx/cmd/convert"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "nitro <lib.swf>...",
+	Short: "Converts SWF furni libraries to Nitro archives",
+	Long: `Converts SWF furni libraries to Nitro archives.
+
+Each library is written to a .nitro file with the same name as the library,
+in the output directory if specified, otherwise alongside the SWF file.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runConvertNitro,
+}
+
+var opts struct {
+	outputDir string
+	force     bool
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.outputDir, "output", "o", "", "The directory to write Nitro archives to")
+	f.BoolVarP(&opts.force, "force", "f", false, "Overwrite existing files")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runConvertNitro(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	if opts.outputDir != "" {
+		err = os.MkdirAll(opts.outputDir, 0755)
+		if err != nil {
+			return
+		}
+	}
+
+	failed := 0
+	for _, fileName := range args {
+		outputPath, err := convertFile(fileName)
+		if err != nil {
+			cmd.PrintErrf("%s: %s\n", fileName, err)
+			failed++
+			continue
+		}
+		fmt.Println(outputPath)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to convert %d of %d libraries", failed, len(args))
+	}
+	return nil
+}
+
+func convertFile(fileName string) (outputPath string, err error) {
+	if !strings.HasSuffix(fileName, ".swf") {
+		err = fmt.Errorf("not a SWF file")
+		return
+	}
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	swf, err := swfx.ReadSwf(f)
+	if err != nil {
+		return
+	}
+
+	lib, err := res.LoadFurniLibrarySwf(swf)
+	if err != nil {
+		return
+	}
+
+	archive, err := res.ConvertFurniLibraryNitro(lib)
+	if err != nil {
+		return
+	}
+
+	outputDir := opts.outputDir
+	if outputDir == "" {
+		outputDir = filepath.Dir(fileName)
+	}
+	outputPath = filepath.Join(outputDir, lib.Name()+".nitro")
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !opts.force {
+		flag |= os.O_EXCL
+	}
+	out, err := os.OpenFile(outputPath, flag, 0644)
+	if err != nil {
+		return
+	}
+
+	err = nitro.NewWriter(out).WriteArchive(archive)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(outputPath)
+	}
+	return
+}
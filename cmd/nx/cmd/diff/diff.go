@@ -0,0 +1,16 @@
+package diff
+
+import (
+	"github.com/spf13/cobra"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compares game data",
+}
+
+func init() {
+	_root.Cmd.AddCommand(Cmd)
+}
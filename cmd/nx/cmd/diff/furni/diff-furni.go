@@ -0,0 +1,170 @@
+package furni
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/spf13/cobra"
+
+	gd "xabbo.io/nx/gamedata"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/diff"
+	"xabbo.io/nx/cmd/nx/spinner"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "furni [a b]",
+	Short: "Show the differences between two furni data",
+	Long: `Show the differences between two furni data.
+
+Each furni data may be specified as one of:
+  - the hash of a furni data in the cache for the current hotel
+  - the path to a furni data file
+  - @<hotel> for the current furni data of a hotel, e.g. @fr
+  - @ for the current furni data of the current hotel, or of the game data directory if one is specified
+
+If no furni data are specified, the two most recently cached furni data of the current hotel are compared.`,
+	Args: cobra.MatchAll(cobra.RangeArgs(0, 2), func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return fmt.Errorf("expected zero or two furni data")
+		}
+		return nil
+	}),
+	RunE: runDiffFurni,
+}
+
+var opts struct {
+	json bool
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.BoolVar(&opts.json, "json", false, "Output the differences in JSON format")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runDiffFurni(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	if len(args) == 0 {
+		var files []gd.CachedFile
+		files, err = gd.CachedFiles(_root.CacheDir(), _root.Host, gd.GameDataFurni)
+		if err != nil {
+			return
+		}
+		if len(files) < 2 {
+			return fmt.Errorf("at least two cached furni data are required for %s, found %d",
+				_root.Host, len(files))
+		}
+		args = []string{files[len(files)-2].Path, files[len(files)-1].Path}
+	}
+
+	var a, b gd.FurniData
+	err = spinner.DoErr("Loading furni data...", func() (err error) {
+		if a, err = loadFurniData(args[0]); err != nil {
+			return
+		}
+		b, err = loadFurniData(args[1])
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	diff := gd.DiffFurni(a, b)
+
+	if opts.json {
+		return json.NewEncoder(os.Stdout).Encode(diff)
+	}
+
+	if diff.Empty() {
+		fmt.Println("No differences.")
+		return
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stdout)
+
+	if len(diff.Added) > 0 {
+		l.AppendItem(fmt.Sprintf("Added (%d)", len(diff.Added)))
+		l.Indent()
+		for _, fi := range diff.Added {
+			l.AppendItem(fmt.Sprintf("%s [%s]", fi.Name, fi.Identifier))
+		}
+		l.UnIndent()
+	}
+
+	if len(diff.Removed) > 0 {
+		l.AppendItem(fmt.Sprintf("Removed (%d)", len(diff.Removed)))
+		l.Indent()
+		for _, fi := range diff.Removed {
+			l.AppendItem(fmt.Sprintf("%s [%s]", fi.Name, fi.Identifier))
+		}
+		l.UnIndent()
+	}
+
+	if len(diff.Changed) > 0 {
+		l.AppendItem(fmt.Sprintf("Changed (%d)", len(diff.Changed)))
+		l.Indent()
+		for _, change := range diff.Changed {
+			l.AppendItem(fmt.Sprintf("%s [%s]", change.After.Name, change.Identifier))
+			l.Indent()
+			for _, field := range change.Changes {
+				l.AppendItem(field.String())
+			}
+			l.UnIndent()
+		}
+		l.UnIndent()
+	}
+
+	l.Render()
+	return
+}
+
+// loadFurniData loads furni data from a hotel, game data directory, file path or cached hash.
+func loadFurniData(source string) (fd gd.FurniData, err error) {
+	if hotel, ok := strings.CutPrefix(source, "@"); ok {
+		var mgr gd.Manager
+		if hotel == "" {
+			mgr = _root.NewManager()
+		} else {
+			host, ok := _root.HostFor(hotel)
+			if !ok {
+				return nil, fmt.Errorf("unknown hotel: %q", hotel)
+			}
+			mgr = gd.NewManager(host, _root.ManagerOptions()...)
+		}
+		err = mgr.Load(gd.GameDataFurni)
+		if err != nil {
+			return
+		}
+		return mgr.Furni(), nil
+	}
+
+	filePath := source
+	if _, statErr := os.Stat(filePath); statErr != nil {
+		filePath = filepath.Join(_root.CacheDir(), _root.Host, string(gd.GameDataFurni), source)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("furni data not found: %q", source)
+		}
+		return
+	}
+
+	if strings.EqualFold(filepath.Ext(filePath), ".xml") {
+		err = fd.UnmarshalXmlBytes(data)
+	} else {
+		err = fd.UnmarshalBytes(data)
+	}
+	return
+}
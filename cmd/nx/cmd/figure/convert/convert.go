@@ -8,26 +8,48 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"xabbo.io/nx"
 	"xabbo.io/nx/cmd/nx/spinner"
 	gd "xabbo.io/nx/gamedata"
 	"xabbo.io/nx/gamedata/origins"
 
+	_root "xabbo.io/nx/cmd/nx/cmd"
 	_parent "xabbo.io/nx/cmd/nx/cmd/figure"
 )
 
 var Cmd = &cobra.Command{
 	Use:   "convert [figure]",
 	Short: "Origins figure converter",
-	Long:  "Converts Origins figure strings to its modern representation",
+	Long:  "Converts Origins figure strings to their modern representation, or modern figure strings to Origins",
 	Args:  cobra.ExactArgs(1),
 	RunE:  run,
 }
 
+var opts struct {
+	to     string
+	gender string
+}
+
 func init() {
+	f := Cmd.Flags()
+	f.StringVar(&opts.to, "to", "modern", "The figure format to convert to: modern or origins")
+	f.StringVarP(&opts.gender, "gender", "g", "", "The gender of the modern figure (M or F), when converting to origins")
+
 	_parent.Cmd.AddCommand(Cmd)
 }
 
 func run(cmd *cobra.Command, args []string) (err error) {
+	switch opts.to {
+	case "modern":
+		return runToModern(cmd, args)
+	case "origins":
+		return runToOrigins(cmd, args)
+	default:
+		return fmt.Errorf("invalid format %q, must be modern or origins", opts.to)
+	}
+}
+
+func runToModern(cmd *cobra.Command, args []string) (err error) {
 	cmd.SilenceUsage = true
 
 	originsFigure := strings.TrimSpace(args[0])
@@ -44,7 +66,7 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	spinner.Start()
 	defer spinner.Stop()
 
-	gdm := gd.NewManager("www.habbo.com")
+	gdm := _root.NewManager()
 
 	spinner.Message("Loading modern figure data...")
 	err = gdm.Load(gd.GameDataFigure)
@@ -71,6 +93,58 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	return
 }
 
+func runToOrigins(cmd *cobra.Command, args []string) (err error) {
+	var figure nx.Figure
+	err = figure.Parse(args[0])
+	if err != nil {
+		return
+	}
+
+	switch strings.ToUpper(opts.gender) {
+	case "":
+	case "M":
+		figure.Gender = nx.Male
+	case "F":
+		figure.Gender = nx.Female
+	default:
+		return fmt.Errorf("invalid gender %q, must be M or F", opts.gender)
+	}
+
+	cmd.SilenceUsage = true
+
+	spinner.Start()
+	defer spinner.Stop()
+
+	gdm := _root.NewManager()
+
+	spinner.Message("Loading modern figure data...")
+	err = gdm.Load(gd.GameDataFigure)
+	if err != nil {
+		return fmt.Errorf("failed to load modern figure data: %w", err)
+	}
+
+	spinner.Message("Loading origins figure data...")
+	ofd, err := loadOriginsFigureData()
+	if err != nil {
+		return fmt.Errorf("failed to load origins figure data: %w", err)
+	}
+
+	colorMap := origins.MakeColorMap(gdm.Figure())
+	converter := origins.NewFigureConverter(ofd, colorMap)
+
+	originsFigure, issues, err := converter.ConvertToOrigins(figure)
+	if err != nil {
+		return
+	}
+
+	spinner.Stop()
+	cmd.Printf("%s\n", originsFigure)
+	for _, issue := range issues {
+		cmd.PrintErrf("%s\n", issue.String())
+	}
+	return
+}
+
 func loadOriginsFigureData() (fd *origins.FigureData, err error) {
 	res, err := http.Get("http://origins-gamedata.habbo.com/figuredata/1")
 	if err != nil {
@@ -0,0 +1,297 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/imager"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/figure"
+	"xabbo.io/nx/cmd/nx/spinner"
+	"xabbo.io/nx/cmd/nx/util"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Show the differences between two figures",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDiff,
+}
+
+var opts struct {
+	json       bool
+	names      bool
+	outputName string
+	dir        int
+}
+
+// imageGap is the horizontal space between the avatars in the output image.
+const imageGap = 16
+
+func init() {
+	f := Cmd.Flags()
+	f.BoolVar(&opts.json, "json", false, "Output the differences in JSON format")
+	f.BoolVarP(&opts.names, "names", "n", false, "Resolve set type, clothing and color names")
+	f.StringVarP(&opts.outputName, "output", "o", "", "Render both figures side by side to the specified PNG file")
+	f.IntVarP(&opts.dir, "dir", "d", 2, "The direction of the rendered avatars (0-7)")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) (err error) {
+	var a, b nx.Figure
+	if err = a.Parse(args[0]); err != nil {
+		return
+	}
+	if err = b.Parse(args[1]); err != nil {
+		return
+	}
+
+	cmd.SilenceUsage = true
+
+	diffs := a.Diff(b)
+
+	var mgr gd.Manager
+	if opts.names || opts.outputName != "" {
+		mgr = _root.NewManager()
+	}
+
+	if opts.names {
+		err = util.LoadGameData(mgr, "Loading game data...",
+			gd.GameDataFigure, gd.GameDataFurni, gd.GameDataTexts)
+		if err != nil {
+			return
+		}
+	}
+
+	if opts.json {
+		err = json.NewEncoder(os.Stdout).Encode(namedDiffs(mgr, diffs))
+	} else if len(diffs) == 0 {
+		fmt.Println("Figures are equal.")
+	} else {
+		renderDiffs(mgr, diffs)
+	}
+	if err != nil {
+		return
+	}
+
+	if opts.outputName != "" {
+		err = renderImage(mgr, a, b)
+	}
+	return
+}
+
+// A namedDiff is a figure item difference along with the names resolved when --names is specified.
+type namedDiff struct {
+	nx.FigureItemDiff
+	TypeName   string           `json:"type_name,omitempty"`
+	BeforeName string           `json:"before_name,omitempty"`
+	AfterName  string           `json:"after_name,omitempty"`
+	Colors     []namedColorDiff `json:"colors,omitempty"`
+}
+
+// A namedColorDiff is a color difference along with the hex values of the colors.
+type namedColorDiff struct {
+	nx.FigureColorDiff
+	BeforeValue string `json:"before_value,omitempty"`
+	AfterValue  string `json:"after_value,omitempty"`
+}
+
+// namedDiffs resolves the names of each difference.
+// The returned slice is never nil, so that it is encoded as an empty JSON array.
+func namedDiffs(mgr gd.Manager, diffs []nx.FigureItemDiff) []namedDiff {
+	names := newNameResolver(mgr)
+	named := make([]namedDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		nd := namedDiff{
+			FigureItemDiff: diff,
+			TypeName:       names.typeName(diff.Type),
+			BeforeName:     names.setName(diff.Before),
+			AfterName:      names.setName(diff.After),
+		}
+		for _, color := range diff.Colors {
+			nd.Colors = append(nd.Colors, namedColorDiff{
+				FigureColorDiff: color,
+				BeforeValue:     names.colorValue(diff.Type, color.Before),
+				AfterValue:      names.colorValue(diff.Type, color.After),
+			})
+		}
+		named = append(named, nd)
+	}
+	return named
+}
+
+// A nameResolver resolves set type, clothing and color names if --names is specified.
+// Otherwise, all names resolve to an empty string.
+type nameResolver struct {
+	mgr      gd.Manager
+	clothing map[int]*gd.FurniInfo
+}
+
+func newNameResolver(mgr gd.Manager) nameResolver {
+	if !opts.names {
+		return nameResolver{}
+	}
+	return nameResolver{mgr, mgr.Furni().ClothingSets()}
+}
+
+// typeName gets the name of the set type from the external texts.
+func (r nameResolver) typeName(setType nx.FigurePartType) string {
+	if r.mgr == nil {
+		return ""
+	}
+	return r.mgr.Texts()["avatareditor.category."+string(setType)]
+}
+
+// setName gets the name of the clothing furni that provides the item's part set.
+func (r nameResolver) setName(item *nx.FigureItem) string {
+	if item == nil {
+		return ""
+	}
+	if fi, ok := r.clothing[item.Id]; ok {
+		return fi.Name
+	}
+	return ""
+}
+
+// colorValue gets the lowercase hex value of the color.
+func (r nameResolver) colorValue(setType nx.FigurePartType, colorId int) string {
+	if r.mgr == nil || colorId == 0 {
+		return ""
+	}
+	if color, ok := r.mgr.Figure().PaletteFor(setType)[colorId]; ok {
+		return strings.ToLower(color.Value)
+	}
+	return ""
+}
+
+func renderDiffs(mgr gd.Manager, diffs []nx.FigureItemDiff) {
+	names := newNameResolver(mgr)
+
+	setName := func(item *nx.FigureItem) string {
+		if item == nil {
+			return ""
+		}
+		if name := names.setName(item); name != "" {
+			return fmt.Sprintf("%d: %s", item.Id, name)
+		}
+		return fmt.Sprint(item.Id)
+	}
+
+	colorName := func(setType nx.FigurePartType, colorId int) string {
+		if colorId == 0 {
+			return "-"
+		}
+		if value := names.colorValue(setType, colorId); value != "" {
+			return fmt.Sprintf("%d (#%s)", colorId, value)
+		}
+		return fmt.Sprint(colorId)
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{"Type", "Change", "Before", "After", "Colors"})
+	for _, diff := range diffs {
+		typeName := string(diff.Type)
+		if name := names.typeName(diff.Type); name != "" {
+			typeName = fmt.Sprintf("%s (%s)", name, diff.Type)
+		}
+
+		var colors []string
+		for _, color := range diff.Colors {
+			colors = append(colors, fmt.Sprintf("#%d: %s → %s", color.Index+1,
+				colorName(diff.Type, color.Before), colorName(diff.Type, color.After)))
+		}
+
+		t.AppendRow(table.Row{
+			typeName, diff.Kind,
+			setName(diff.Before), setName(diff.After),
+			strings.Join(colors, "\n"),
+		})
+	}
+	t.Render()
+}
+
+func renderImage(mgr gd.Manager, figures ...nx.Figure) (err error) {
+	err = util.LoadGameData(mgr, "Loading game data...",
+		gd.GameDataFigure, gd.GameDataFigureMap, gd.GameDataVariables,
+		gd.GameDataAvatar, gd.GameDataPartSets, gd.GameDataGeometry)
+	if err != nil {
+		return
+	}
+
+	renderer := imager.NewAvatarImager(mgr)
+
+	var anims []imager.Animation
+	err = spinner.DoErr("Rendering figures...", func() (err error) {
+		for _, figure := range figures {
+			avatar := imager.Avatar{
+				Figure:        figure,
+				Direction:     opts.dir,
+				HeadDirection: opts.dir,
+			}
+			var parts []imager.AvatarPart
+			parts, err = renderer.AvatarParts(avatar)
+			if err != nil {
+				return
+			}
+			for _, part := range parts {
+				err = mgr.LoadFigureParts(part.LibraryName)
+				if err != nil {
+					return
+				}
+			}
+			var anim imager.Animation
+			anim, err = renderer.Compose(avatar)
+			if err != nil {
+				return
+			}
+			anims = append(anims, anim)
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	width, height := 0, 0
+	for i, anim := range anims {
+		size := anim.Bounds(0).Size()
+		if i > 0 {
+			width += imageGap
+		}
+		width += size.X
+		height = max(height, size.Y)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	x := 0
+	for _, anim := range anims {
+		bounds := anim.Bounds(0)
+		imager.DrawFrame(anim, canvas, image.Pt(x-bounds.Min.X, height-bounds.Max.Y), nil, 0, 0)
+		x += bounds.Dx() + imageGap
+	}
+
+	f, err := os.Create(opts.outputName)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	err = png.Encode(f, canvas)
+	if err == nil {
+		fmt.Printf("output: %s\n", opts.outputName)
+	}
+	return
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"strconv"
-	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/list"
 	"github.com/spf13/cobra"
@@ -85,30 +84,14 @@ func runInfo(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	err = util.LoadGameData(mgr, "Loading game data...",
 		gd.GameDataFigure, gd.GameDataFigureMap, gd.GameDataFurni, gd.GameDataTexts, gd.GameDataVariables)
 	if err != nil {
 		return err
 	}
 
-	partCountMap := make(map[int]int)
-	clothingMap := make(map[int]*gd.FurniInfo)
-	for _, f := range mgr.Furni() {
-		if f.SpecialType == nx.FurniTypeClothing {
-			parts := strings.Split(f.CustomParams, ",")
-			for _, s := range parts {
-				s = strings.TrimSpace(s)
-				if id, err := strconv.Atoi(s); err == nil {
-					c := partCountMap[id]
-					if c == 0 || len(parts) < c {
-						partCountMap[id] = len(parts)
-						clothingMap[id] = f
-					}
-				}
-			}
-		}
-	}
+	clothingMap := mgr.Furni().ClothingSets()
 
 	for _, part := range figure.Items {
 		setGroup := mgr.Figure().Sets[part.Type]
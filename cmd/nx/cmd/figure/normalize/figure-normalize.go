@@ -0,0 +1,119 @@
+package normalize
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/figure"
+	"xabbo.io/nx/cmd/nx/spinner"
+	"xabbo.io/nx/cmd/nx/util"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "normalize [figure]",
+	Short: "Repair a figure and convert it to its canonical form",
+	Args:  cobra.RangeArgs(0, 1),
+	RunE:  runNormalize,
+}
+
+var opts struct {
+	userName string
+	gender   string
+	club     int
+	quiet    bool
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.userName, "user", "u", "", "User to load figure for")
+	f.StringVarP(&opts.gender, "gender", "g", "", "The gender of the figure (M or F)")
+	f.IntVar(&opts.club, "club", 0, "The club level of the figure's owner")
+	f.BoolVarP(&opts.quiet, "quiet", "q", false, "Only output the normalized figure")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runNormalize(cmd *cobra.Command, args []string) (err error) {
+	if len(args) < 1 && opts.userName == "" {
+		return fmt.Errorf("no figure or user specified")
+	}
+
+	if len(args) > 0 && opts.userName != "" {
+		return fmt.Errorf("only one of either figure or user may be specified")
+	}
+
+	gender := nx.Unisex
+	switch strings.ToUpper(opts.gender) {
+	case "":
+	case "M":
+		gender = nx.Male
+	case "F":
+		gender = nx.Female
+	default:
+		return fmt.Errorf("invalid gender %q, must be M or F", opts.gender)
+	}
+
+	cmd.SilenceUsage = true
+
+	figureString := ""
+	if len(args) > 0 {
+		figureString = args[0]
+	} else {
+		err = spinner.DoErr("Loading user...", func() error {
+			api := nx.NewApiClient(_root.Host)
+			user, err := api.GetUserByName(opts.userName)
+			if err != nil {
+				return err
+			}
+			figureString = user.FigureString
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	var figure nx.Figure
+	err = figure.Parse(figureString)
+	if err != nil {
+		return
+	}
+	figure.Gender = gender
+
+	mgr := _root.NewManager()
+	err = util.LoadGameData(mgr, "Loading game data...", gd.GameDataFigure)
+	if err != nil {
+		return
+	}
+
+	result, changes := mgr.Figure().NormalizeFigure(figure, opts.club)
+	fmt.Println(result.String())
+
+	if opts.quiet {
+		return
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No changes.")
+		return
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stdout)
+	l.UnIndent()
+	for _, change := range changes {
+		l.AppendItem(change.String())
+	}
+	l.Render()
+
+	return
+}
@@ -0,0 +1,122 @@
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/figure"
+	"xabbo.io/nx/cmd/nx/util"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "random",
+	Short: "Generate random figures",
+	Args:  cobra.NoArgs,
+	RunE:  runRandom,
+}
+
+var opts struct {
+	gender         string
+	club           int
+	selectableOnly bool
+	include        []string
+	exclude        []string
+	chance         float64
+	colors         []int
+	harmony        string
+	seed           int64
+	count          int
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.gender, "gender", "g", "", "The gender of the figure (M or F), chosen at random if not specified")
+	f.IntVar(&opts.club, "club", 0, "The maximum club level of part sets and colors")
+	f.BoolVar(&opts.selectableOnly, "selectable-only", true, "Only use selectable part sets and colors")
+	f.StringSliceVarP(&opts.include, "include", "i", nil, "Set types to include, e.g. ha,ea")
+	f.StringSliceVarP(&opts.exclude, "exclude", "x", nil, "Set types to exclude")
+	f.Float64Var(&opts.chance, "chance", 0.5, "The probability of including each optional set type")
+	f.IntSliceVar(&opts.colors, "colors", nil, "A fixed list of color IDs to choose from")
+	f.StringVar(&opts.harmony, "harmony", "", "The color harmony: monochrome, analogous or complementary")
+	f.Int64Var(&opts.seed, "seed", 0, "The random seed, chosen at random if not specified")
+	f.IntVarP(&opts.count, "count", "n", 1, "The number of figures to generate")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runRandom(cmd *cobra.Command, args []string) (err error) {
+	gender := nx.Unisex
+	switch strings.ToUpper(opts.gender) {
+	case "":
+	case "M":
+		gender = nx.Male
+	case "F":
+		gender = nx.Female
+	default:
+		return fmt.Errorf("invalid gender %q, must be M or F", opts.gender)
+	}
+
+	harmony := gd.ColorHarmony(opts.harmony)
+	if !slices.Contains(gd.ColorHarmonies, harmony) {
+		return fmt.Errorf("invalid color harmony %q, must be one of %s",
+			opts.harmony, util.CommaList(gd.ColorHarmonies[1:], "or"))
+	}
+
+	if opts.chance < 0 || opts.chance > 1 {
+		return fmt.Errorf("invalid chance %v, must be between 0 and 1", opts.chance)
+	}
+
+	cmd.SilenceUsage = true
+
+	if !cmd.Flags().Lookup("seed").Changed {
+		opts.seed = time.Now().UnixNano()
+		fmt.Fprintf(os.Stderr, "seed: %d\n", opts.seed)
+	}
+
+	mgr := _root.NewManager()
+	err = util.LoadGameData(mgr, "Loading game data...", gd.GameDataFigure)
+	if err != nil {
+		return
+	}
+
+	figureOpts := gd.RandomFigureOptions{
+		Gender:         gender,
+		Club:           opts.club,
+		SelectableOnly: opts.selectableOnly,
+		Chance:         opts.chance,
+		Colors:         opts.colors,
+		Harmony:        harmony,
+	}
+	// A chance of zero uses the default, so use a negative chance to never include optional set types.
+	if opts.chance == 0 {
+		figureOpts.Chance = -1
+	}
+	for _, setType := range opts.include {
+		figureOpts.Include = append(figureOpts.Include, nx.FigurePartType(setType))
+	}
+	for _, setType := range opts.exclude {
+		figureOpts.Exclude = append(figureOpts.Exclude, nx.FigurePartType(setType))
+	}
+
+	rng := rand.New(rand.NewSource(opts.seed))
+	for range opts.count {
+		var figure nx.Figure
+		figure, err = mgr.Figure().RandomFigure(rng, figureOpts)
+		if err != nil {
+			return
+		}
+		fmt.Println(figure.Gender, figure.String())
+	}
+
+	return
+}
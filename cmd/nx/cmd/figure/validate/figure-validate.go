@@ -0,0 +1,112 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/figure"
+	"xabbo.io/nx/cmd/nx/spinner"
+	"xabbo.io/nx/cmd/nx/util"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "validate [figure]",
+	Short: "Validate a figure against the figure data",
+	Args:  cobra.RangeArgs(0, 1),
+	RunE:  runValidate,
+}
+
+var opts struct {
+	userName string
+	gender   string
+	club     int
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.userName, "user", "u", "", "User to load figure for")
+	f.StringVarP(&opts.gender, "gender", "g", "", "The gender of the figure (M or F)")
+	f.IntVar(&opts.club, "club", 0, "The club level of the figure's owner")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) (err error) {
+	if len(args) < 1 && opts.userName == "" {
+		return fmt.Errorf("no figure or user specified")
+	}
+
+	if len(args) > 0 && opts.userName != "" {
+		return fmt.Errorf("only one of either figure or user may be specified")
+	}
+
+	gender := nx.Unisex
+	switch strings.ToUpper(opts.gender) {
+	case "":
+	case "M":
+		gender = nx.Male
+	case "F":
+		gender = nx.Female
+	default:
+		return fmt.Errorf("invalid gender %q, must be M or F", opts.gender)
+	}
+
+	cmd.SilenceUsage = true
+
+	figureString := ""
+	if len(args) > 0 {
+		figureString = args[0]
+	} else {
+		err = spinner.DoErr("Loading user...", func() error {
+			api := nx.NewApiClient(_root.Host)
+			user, err := api.GetUserByName(opts.userName)
+			if err != nil {
+				return err
+			}
+			figureString = user.FigureString
+			return nil
+		})
+		if err != nil {
+			return
+		}
+		fmt.Println(figureString)
+	}
+
+	var figure nx.Figure
+	err = figure.Parse(figureString)
+	if err != nil {
+		return
+	}
+	figure.Gender = gender
+
+	mgr := _root.NewManager()
+	err = util.LoadGameData(mgr, "Loading game data...", gd.GameDataFigure)
+	if err != nil {
+		return
+	}
+
+	issues := mgr.Figure().ValidateFigure(figure, opts.club)
+	if len(issues) == 0 {
+		fmt.Println("Figure is valid.")
+		return
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stdout)
+	l.UnIndent()
+	for _, issue := range issues {
+		l.AppendItem(issue.Error())
+	}
+	l.Render()
+
+	return fmt.Errorf("%d issue(s) found", len(issues))
+}
@@ -0,0 +1,201 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx/raw/nitro"
+	"xabbo.io/nx/res"
+
+	"xabbo.io/nx/cmd/nx/cmd/furni"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "build <dir>",
+	Short: "Build a furni library from PNG images and a spec file",
+	Long: `Builds a furni library from the PNG images in a directory and a JSON or YAML spec file.
+
+Each image must be named after its asset, e.g. chair_64_a_0_0.png for
+size 64, layer a, direction 0, frame 0. The shadow layer is named sd.
+If no spec file is specified, furni.yaml, furni.yml or furni.json is read from the directory.
+
+Example spec:
+
+  name: chair
+  directions: [0, 2]
+  layer_count: 2
+  layers:
+    1: { z: 1, ink: ADD, alpha: 128 }
+  animations:
+    0: { layers: { 1: { frame_sequences: [[0]] } } }
+    1: { layers: { 1: { frame_repeat: 2, frame_sequences: [[0, 1, 2]] } } }
+  assets:
+    chair_64_a_0_0: { x: 32, y: 40 }
+    chair_64_a_2_0: { source: chair_64_a_0_0, flip_h: true, x: 32, y: 40 }
+
+The library is written as a .nitro archive, or as the XML documents and
+PNG images of a SWF library if the format is xml.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBuild,
+}
+
+var opts struct {
+	spec   string
+	output string
+	format string
+	check  bool
+	force  bool
+}
+
+var specFileNames = []string{"furni.yaml", "furni.yml", "furni.json"}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.spec, "spec", "s", "", "The spec file")
+	f.StringVarP(&opts.output, "output", "o", "", "The output file or directory")
+	f.StringVar(&opts.format, "format", "", "The output format (nitro or xml)")
+	f.BoolVar(&opts.check, "check", false, "Only validate the spec and images")
+	f.BoolVarP(&opts.force, "force", "f", false, "Overwrite existing files")
+
+	furni.Cmd.AddCommand(Cmd)
+}
+
+func runBuild(cmd *cobra.Command, args []string) (err error) {
+	dir := args[0]
+
+	format := opts.format
+	if format == "" {
+		format = "nitro"
+		if opts.output != "" && !strings.HasSuffix(opts.output, ".nitro") {
+			format = "xml"
+		}
+	}
+	if format != "nitro" && format != "xml" {
+		return fmt.Errorf("invalid format %q, must be nitro or xml", opts.format)
+	}
+
+	cmd.SilenceUsage = true
+
+	specPath := opts.spec
+	if specPath == "" {
+		for _, name := range specFileNames {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				specPath = filepath.Join(dir, name)
+				break
+			}
+		}
+		if specPath == "" {
+			return fmt.Errorf("no spec file found in %s", dir)
+		}
+	}
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return
+	}
+
+	var spec res.FurniSpec
+	err = spec.UnmarshalBytes(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", specPath, err)
+	}
+
+	lib, err := res.BuildFurniLibrary(spec, os.DirFS(dir))
+	if err != nil {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, e := range joined.Unwrap() {
+				cmd.PrintErrln(e)
+			}
+			return fmt.Errorf("%d error(s) found", len(joined.Unwrap()))
+		}
+		return
+	}
+
+	if opts.check {
+		fmt.Println("Furni library is valid.")
+		return
+	}
+
+	switch format {
+	case "nitro":
+		err = writeNitro(lib)
+	case "xml":
+		err = writeXml(lib)
+	}
+	return
+}
+
+func writeNitro(lib res.FurniLibrary) (err error) {
+	output := opts.output
+	if output == "" {
+		output = lib.Name() + ".nitro"
+	}
+
+	archive, err := res.ConvertFurniLibraryNitro(lib)
+	if err != nil {
+		return
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !opts.force {
+		flag |= os.O_EXCL
+	}
+	f, err := os.OpenFile(output, flag, 0644)
+	if err != nil {
+		return
+	}
+
+	err = nitro.NewWriter(f).WriteArchive(archive)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return
+	}
+
+	fmt.Println(output)
+	return
+}
+
+func writeXml(lib res.FurniLibrary) (err error) {
+	outDir := opts.output
+	if outDir == "" {
+		outDir = lib.Name()
+	}
+
+	files, err := res.ExportFurniLibraryXml(lib)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return
+	}
+
+	names := maps.Keys(files)
+	slices.Sort(names)
+	for _, name := range names {
+		filePath := filepath.Join(outDir, name)
+		if !opts.force {
+			if _, err := os.Stat(filePath); err == nil {
+				return fmt.Errorf("%s: file exists", filePath)
+			}
+		}
+		err = os.WriteFile(filePath, files[name], 0644)
+		if err != nil {
+			return
+		}
+		fmt.Println(filePath)
+	}
+	return
+}
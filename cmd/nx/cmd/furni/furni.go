@@ -35,7 +35,7 @@ func runFurni(cmd *cobra.Command, args []string) (err error) {
 		return fmt.Errorf("no options specified")
 	}
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	err = util.LoadFurni(mgr)
 	if err != nil {
 		return
@@ -41,7 +41,7 @@ func init() {
 func runInfo(cmd *cobra.Command, args []string) (err error) {
 	cmd.SilenceUsage = true
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	err = util.LoadFurni(mgr)
 	if err != nil {
 		return
@@ -35,7 +35,7 @@ func runGetFurni(cmd *cobra.Command, args []string) (err error) {
 	}
 	cmd.SilenceUsage = true
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	err = util.LoadFurni(mgr)
 	if err != nil {
 		return
@@ -6,6 +6,7 @@ import (
 	"image/png"
 	"os"
 	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -33,7 +34,10 @@ var opts struct {
 	expression string
 	userName   string
 	handItem   int
+	effect     int
+	sign       int
 	headOnly   bool
+	blink      bool
 	outputName string
 	noColor    bool
 	verbose    bool
@@ -42,16 +46,20 @@ var opts struct {
 	size       []int
 }
 
-var validFormats = []string{"png", "svg"}
+var validFormats = []string{"png", "svg", "gif", "apng"}
 
 func init() {
 	f := Cmd.Flags()
 	f.IntVarP(&opts.dir, "dir", "d", 2, "The direction of the avatar (0-7)")
 	f.IntVarP(&opts.headDir, "head-dir", "H", 2, "The direction of the avatar's head (0-7)")
-	f.StringVarP(&opts.action, "action", "a", "std", "The action of the avatar")
+	f.StringVarP(&opts.action, "action", "a", "std", "A comma-separated list of actions of the avatar, e.g. sit,wav")
 	f.StringVarP(&opts.expression, "expression", "e", "", "The expression of the avatar")
 	f.StringVarP(&opts.userName, "user", "u", "", "The name of the user to fetch a figure for")
+	f.IntVar(&opts.handItem, "hand-item", 0, "The ID of the hand item to carry or drink")
+	f.IntVar(&opts.effect, "effect", 0, "The ID of the avatar effect to apply")
+	f.IntVar(&opts.sign, "sign", 0, fmt.Sprintf("The sign to show (0-%d)", imager.MaxSign))
 	f.BoolVar(&opts.headOnly, "head-only", false, "Render head only")
+	f.BoolVar(&opts.blink, "blink", false, "Blink the avatar's eyes when animated")
 	f.StringVarP(&opts.outputName, "output", "o", "", "The name of the output file")
 	f.BoolVar(&opts.noColor, "no-color", false, "Do not color figure parts")
 	f.BoolVarP(&opts.verbose, "verbose", "v", false, "Verbose output")
@@ -88,9 +96,31 @@ func runRenderAvatar(cmd *cobra.Command, args []string) (err error) {
 
 	cmd.SilenceUsage = true
 
-	if !slices.Contains(nx.AvatarActions, nx.AvatarState(opts.action)) {
-		return fmt.Errorf("invalid action %q, must be one of %s",
-			opts.action, util.CommaList(nx.AvatarActions, "or"))
+	var actions []nx.AvatarState
+	for _, action := range strings.Split(opts.action, ",") {
+		action = strings.TrimSpace(action)
+		if action == "" {
+			continue
+		}
+		if !slices.Contains(nx.AvatarActions, nx.AvatarState(action)) {
+			return fmt.Errorf("invalid action %q, must be one of %s",
+				action, util.CommaList(nx.AvatarActions, "or"))
+		}
+		actions = append(actions, nx.AvatarState(action))
+	}
+
+	if opts.sign < 0 || opts.sign > imager.MaxSign {
+		return fmt.Errorf("invalid sign %d, must be between 0 and %d", opts.sign, imager.MaxSign)
+	}
+	// Show the sign if it is specified without the sign action.
+	if cmd.Flags().Lookup("sign").Changed && !slices.Contains(actions, nx.ActSign) {
+		actions = append(actions, nx.ActSign)
+	}
+
+	// Carry the hand item if it is not being carried or drunk.
+	if opts.handItem > 0 &&
+		!slices.Contains(actions, nx.ActCarry) && !slices.Contains(actions, nx.ActDrink) {
+		actions = append(actions, nx.ActCarry)
 	}
 
 	if opts.expression != "" && !slices.Contains(nx.AvatarExpressions, nx.AvatarState(opts.expression)) {
@@ -101,7 +131,7 @@ func runRenderAvatar(cmd *cobra.Command, args []string) (err error) {
 	vars := map[string]any{}
 	vars["dir"] = opts.dir
 	vars["hdir"] = opts.headDir
-	vars["act"] = opts.action
+	vars["act"] = strings.ReplaceAll(opts.action, ",", "+")
 	if opts.expression == "" {
 		vars["expr"] = "ntr" // Neutral
 	} else {
@@ -145,11 +175,11 @@ func runRenderAvatar(cmd *cobra.Command, args []string) (err error) {
 	})
 	fileName := opts.outputName
 	switch opts.outFormat {
-	case "png", "svg":
+	case "png", "svg", "gif", "apng":
 		fileName += "." + opts.outFormat
 	}
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	renderer := imager.NewAvatarImager(mgr)
 
 	var figure nx.Figure
@@ -158,14 +188,32 @@ func runRenderAvatar(cmd *cobra.Command, args []string) (err error) {
 		return
 	}
 
-	err = util.LoadGameData(mgr, "Loading game data...",
+	gameDataTypes := []gd.Type{
 		gd.GameDataFigure, gd.GameDataFigureMap,
-		gd.GameDataVariables, gd.GameDataAvatar)
+		gd.GameDataVariables, gd.GameDataAvatar, gd.GameDataPartSets, gd.GameDataGeometry,
+	}
+	if opts.effect > 0 {
+		gameDataTypes = append(gameDataTypes, gd.GameDataEffectMap)
+	}
+	err = util.LoadGameData(mgr, "Loading game data...", gameDataTypes...)
 	if err != nil {
 		return
 	}
 
-	parts, err := renderer.Parts(figure)
+	avatar := imager.Avatar{
+		Figure:        figure,
+		Direction:     opts.dir,
+		HeadDirection: opts.headDir,
+		Actions:       actions,
+		Expression:    nx.AvatarState(opts.expression),
+		HandItem:      opts.handItem,
+		Effect:        opts.effect,
+		Sign:          opts.sign,
+		HeadOnly:      opts.headOnly,
+		Blink:         opts.blink,
+	}
+
+	parts, err := renderer.AvatarParts(avatar)
 	if err != nil {
 		return
 	}
@@ -192,13 +240,17 @@ func runRenderAvatar(cmd *cobra.Command, args []string) (err error) {
 		return
 	}
 
-	avatar := imager.Avatar{
-		Figure:        figure,
-		Direction:     opts.dir,
-		HeadDirection: opts.headDir,
-		Actions:       []nx.AvatarState{nx.AvatarState(opts.action)},
-		Expression:    nx.AvatarState(opts.expression),
-		HeadOnly:      opts.headOnly,
+	if opts.effect > 0 {
+		effect, ok := mgr.EffectMap()[opts.effect]
+		if !ok {
+			return fmt.Errorf("effect not found: %d", opts.effect)
+		}
+		err = spinner.DoErr("Loading effect library...", func() error {
+			return mgr.LoadEffects(effect.Library)
+		})
+		if err != nil {
+			return
+		}
 	}
 
 	anim, err := renderer.Compose(avatar)
@@ -238,6 +290,12 @@ func runRenderAvatar(cmd *cobra.Command, args []string) (err error) {
 	case "svg":
 		encoder := imager.NewEncoderSVG()
 		err = encoder.EncodeFrame(f, anim, 0, 0)
+	case "gif":
+		encoder := imager.NewEncoderGIF()
+		err = encoder.EncodeAnimation(f, anim, 0, anim.TotalFrames(0))
+	case "apng":
+		encoder := imager.NewEncoderAPNG()
+		err = encoder.EncodeAnimation(f, anim, 0, anim.TotalFrames(0))
 	default:
 		return
 	}
@@ -10,19 +10,18 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/jedib0t/go-pretty/v6/list"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 
-	"b7c.io/swfx"
-
 	gd "xabbo.io/nx/gamedata"
 	"xabbo.io/nx/imager"
-	"xabbo.io/nx/raw/nitro"
 	"xabbo.io/nx/res"
 
 	_root "xabbo.io/nx/cmd/nx/cmd"
 	_parent "xabbo.io/nx/cmd/nx/cmd/imager"
 	"xabbo.io/nx/cmd/nx/spinner"
+	"xabbo.io/nx/cmd/nx/util"
 )
 
 var Cmd = &cobra.Command{
@@ -109,14 +108,14 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	spinner.Start()
 	defer spinner.Stop()
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 
 	if opts.inputFilePath != "" {
 		if len(args) > 0 {
 			return errors.New("only one of furni identifier or input file may be specified")
 		}
 
-		lib, err = loadLibraryFile(opts.inputFilePath)
+		lib, err = util.LoadFurniLibraryFile(opts.inputFilePath)
 		if err != nil {
 			return
 		}
@@ -158,6 +157,14 @@ func run(cmd *cobra.Command, args []string) (err error) {
 		}
 	}
 
+	issues, err := imager.NewFurniImager(mgr).Validate(lib.Name())
+	if err != nil {
+		return
+	}
+	if len(issues) > 0 {
+		printWarnings(issues)
+	}
+
 	vis, ok := lib.Visualizations()[opts.size]
 	if !ok {
 		err = fmt.Errorf("no visualization for size: %d", opts.size)
@@ -308,47 +315,6 @@ func run(cmd *cobra.Command, args []string) (err error) {
 	return
 }
 
-func loadLibraryFile(name string) (lib res.FurniLibrary, err error) {
-	switch {
-	case strings.HasSuffix(strings.ToLower(name), ".swf"):
-		var swf *swfx.Swf
-		swf, err = loadSwf(name)
-		if err != nil {
-			return
-		}
-		lib, err = res.LoadFurniLibrarySwf(swf)
-	case strings.HasSuffix(strings.ToLower(name), ".nitro"):
-		var archive nitro.Archive
-		archive, err = loadNitroArchive(name)
-		if err != nil {
-			return
-		}
-		lib, err = res.LoadFurniLibraryNitro(archive)
-	default:
-		err = fmt.Errorf("input file format not supported")
-	}
-	return
-}
-
-func loadSwf(filePath string) (swf *swfx.Swf, err error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return
-	}
-	defer f.Close()
-	return swfx.ReadSwf(f)
-}
-
-func loadNitroArchive(filePath string) (archive nitro.Archive, err error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return
-	}
-	defer f.Close()
-	r := nitro.NewReader(f)
-	return r.ReadArchive()
-}
-
 func saveAnimationSequence(fname string, anims []imager.Animation, seqIndex int) (err error) {
 	var encoder imager.AnimatedImageEncoder
 	switch opts.format {
@@ -440,3 +406,19 @@ func saveEncoder(output string, encoder any, anim imager.Animation, seqIndex, fr
 
 	return
 }
+
+// printWarnings prints the issues found in the furni library to stderr.
+func printWarnings(issues []res.FurniIssue) {
+	spinner.Stop()
+	defer spinner.Start()
+
+	fmt.Fprintf(os.Stderr, "warning: %d issue(s) found in furni library:\n", len(issues))
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stderr)
+	l.UnIndent()
+	for _, issue := range issues {
+		l.AppendItem(issue.Error())
+	}
+	l.Render()
+}
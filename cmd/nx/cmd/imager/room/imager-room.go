@@ -0,0 +1,202 @@
+package room
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/imager"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	_parent "xabbo.io/nx/cmd/nx/cmd/imager"
+	"xabbo.io/nx/cmd/nx/spinner"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "room [flags] [scene]",
+	Short: "Render a scene of furni and avatars",
+	Long: "Renders a scene of furni and avatars placed on an isometric grid.\n" +
+		"The scene is described by a JSON or YAML file, for example:\n\n" +
+		"  size: 64\n" +
+		"  shadows: true\n" +
+		"  room:\n" +
+		"    heightmap: |\n" +
+		"      xxxxx\n" +
+		"      x0000\n" +
+		"      00000\n" +
+		"      x0011\n" +
+		"    door_x: 0\n" +
+		"    door_y: 2\n" +
+		"    floor: \"101\"\n" +
+		"    wallpaper: \"201\"\n" +
+		"  furni:\n" +
+		"    - identifier: club_sofa\n" +
+		"      x: 0\n" +
+		"      y: 1\n" +
+		"      direction: 2\n" +
+		"  avatars:\n" +
+		"    - figure: hr-3090-42.hd-180-1.ch-3110-64-1408.lg-275-64\n" +
+		"      x: 2\n" +
+		"      y: 1\n" +
+		"      direction: 6\n" +
+		"      actions: [wav]\n\n" +
+		"A room with no furni or avatars may be rendered from a heightmap file with --heightmap.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: run,
+}
+
+var opts struct {
+	outputName string
+	format     string
+	heightmap  string
+	door       []int
+}
+
+var validFormats = []string{"png", "apng", "gif"}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.outputName, "output", "o", "", "The name of the output file")
+	f.StringVarP(&opts.format, "format", "f", "png", "Output image format. (png, apng, gif)")
+	f.StringVar(&opts.heightmap, "heightmap", "", "A heightmap file to render the room floor and walls from")
+	f.IntSliceVar(&opts.door, "door", []int{0, 0}, "The X,Y position of the door tile when using --heightmap")
+
+	_parent.Cmd.AddCommand(Cmd)
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	opts.format = strings.ToLower(opts.format)
+	if !slices.Contains(validFormats, opts.format) {
+		return fmt.Errorf("invalid format: %q", opts.format)
+	}
+
+	if len(args) == 0 && opts.heightmap == "" {
+		return fmt.Errorf("a scene or heightmap must be specified")
+	}
+	if len(opts.door) != 2 {
+		return fmt.Errorf("door must be specified as X,Y")
+	}
+
+	cmd.SilenceUsage = true
+
+	scene := imager.Scene{Size: 64}
+	if len(args) > 0 {
+		var data []byte
+		data, err = os.ReadFile(args[0])
+		if err != nil {
+			return
+		}
+		err = scene.UnmarshalBytes(data)
+		if err != nil {
+			return
+		}
+	}
+
+	if opts.heightmap != "" {
+		var data []byte
+		data, err = os.ReadFile(opts.heightmap)
+		if err != nil {
+			return
+		}
+		if scene.Room == nil {
+			scene.Room = &imager.SceneRoom{}
+		}
+		scene.Room.Heightmap = string(data)
+		scene.Room.DoorX, scene.Room.DoorY = opts.door[0], opts.door[1]
+	}
+
+	spinner.Start()
+	defer spinner.Stop()
+
+	mgr := _root.NewManager()
+
+	types := []gd.Type{gd.GameDataVariables, gd.GameDataFurni}
+	if len(scene.Avatars) > 0 {
+		types = append(types, gd.GameDataFigure, gd.GameDataFigureMap, gd.GameDataAvatar,
+			gd.GameDataPartSets, gd.GameDataGeometry)
+	}
+
+	spinner.Message("Loading game data...")
+	err = mgr.Load(types...)
+	if err != nil {
+		return
+	}
+
+	spinner.Message("Loading furni libraries...")
+	for _, furni := range scene.Furni {
+		err = mgr.LoadFurni(furni.Identifier)
+		if err != nil {
+			return
+		}
+	}
+
+	sceneImager := imager.NewSceneImager(mgr)
+
+	if len(scene.Avatars) > 0 {
+		spinner.Message("Loading figure part libraries...")
+		var libs []string
+		libs, err = sceneImager.RequiredFigureLibs(scene)
+		if err != nil {
+			return
+		}
+		for _, lib := range libs {
+			err = mgr.LoadFigureParts(lib)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if scene.Room != nil && (scene.Room.Floor != "" || scene.Room.Wallpaper != "" || scene.Room.Landscape != "") {
+		spinner.Message("Loading room content...")
+		err = mgr.LoadRoomContent()
+		if err != nil {
+			return
+		}
+	}
+
+	spinner.Message("Composing scene...")
+	anim, err := sceneImager.Compose(scene)
+	if err != nil {
+		return
+	}
+
+	fileName := opts.outputName
+	if fileName == "" {
+		source := opts.heightmap
+		if len(args) > 0 {
+			source = args[0]
+		}
+		fileName = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	}
+	if filepath.Ext(fileName) == "" {
+		fileName += "." + opts.format
+	}
+
+	f, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	spinner.Message("Rendering image...")
+	switch opts.format {
+	case "png":
+		err = imager.NewEncoderPNG().EncodeFrame(f, anim, 0, 0)
+	case "apng":
+		err = imager.NewEncoderAPNG().EncodeAnimation(f, anim, 0, anim.TotalFrames(0))
+	case "gif":
+		err = imager.NewEncoderGIF().EncodeAnimation(f, anim, 0, anim.TotalFrames(0))
+	}
+	if err != nil {
+		return
+	}
+
+	spinner.Printf("%s\n", fileName)
+	return
+}
@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/spf13/cobra"
+
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/res"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+	"xabbo.io/nx/cmd/nx/spinner"
+	"xabbo.io/nx/cmd/nx/util"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "lint <identifier|file>",
+	Short: "Checks a furni library for problems",
+	Long: `Checks a furni library for problems.
+
+The library is loaded by its identifier, or from a .swf or .nitro file.
+Reports frames referenced by animations whose assets do not exist, broken or cyclic
+asset sources, directions without assets, layers beyond the layer count,
+colors without color layers, and transitions to undefined animations.`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	_root.Cmd.AddCommand(Cmd)
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	lib, err := loadLibrary(args[0])
+	if err != nil {
+		return
+	}
+
+	issues := res.ValidateFurniLibrary(lib)
+	if len(issues) == 0 {
+		fmt.Println("Furni library is valid.")
+		return
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stdout)
+	l.UnIndent()
+	for _, issue := range issues {
+		l.AppendItem(issue.Error())
+	}
+	l.Render()
+
+	return fmt.Errorf("%d issue(s) found", len(issues))
+}
+
+func loadLibrary(arg string) (lib res.FurniLibrary, err error) {
+	lower := strings.ToLower(arg)
+	if strings.HasSuffix(lower, ".swf") || strings.HasSuffix(lower, ".nitro") {
+		return util.LoadFurniLibraryFile(arg)
+	}
+
+	identifier, _, _ := strings.Cut(arg, "*")
+
+	mgr := _root.NewManager()
+	err = util.LoadGameData(mgr, "Loading game data...", gd.GameDataVariables, gd.GameDataFurni)
+	if err != nil {
+		return
+	}
+
+	err = spinner.DoErr("Loading furni library...", func() error {
+		return mgr.LoadFurni(identifier)
+	})
+	if err != nil {
+		return
+	}
+
+	lib, ok := mgr.Library(identifier).(res.FurniLibrary)
+	if !ok {
+		err = fmt.Errorf("failed to load furni library %q", identifier)
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	root "xabbo.io/nx/cmd/nx/cmd"
+	"xabbo.io/nx/raw/nitro"
+)
+
+var opts struct {
+	output string
+	force  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "pack <dir>",
+	Short: "Packs the files in a directory into a Nitro archive",
+	Long: `Packs the files in a directory into a Nitro archive.
+
+This is the inverse of extract. Subdirectories are ignored.
+If no output file is specified, the archive is written to <dir>.nitro.`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	root.Cmd.AddCommand(Cmd)
+
+	f := Cmd.Flags()
+	f.StringVarP(&opts.output, "output", "o", "", "The output file")
+	f.BoolVarP(&opts.force, "force", "f", false, "Overwrite the output file if it exists")
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	dir := args[0]
+	output := opts.output
+	if output == "" {
+		output = strings.TrimRight(dir, `/\`) + ".nitro"
+	}
+
+	archive, err := readDir(dir)
+	if err != nil {
+		return
+	}
+	if len(archive.Files) == 0 {
+		return fmt.Errorf("no files found in %s", dir)
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !opts.force {
+		flag |= os.O_EXCL
+	}
+	f, err := os.OpenFile(output, flag, 0644)
+	if err != nil {
+		return
+	}
+
+	err = nitro.NewWriter(f).WriteArchive(archive)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return
+	}
+
+	fmt.Println(output)
+	return
+}
+
+func readDir(dir string) (archive nitro.Archive, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	archive = nitro.Archive{Files: map[string]nitro.File{}}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		var data []byte
+		data, err = os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return
+		}
+		archive.Files[entry.Name()] = nitro.File{Name: entry.Name(), Data: data}
+	}
+	return
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	gd "xabbo.io/nx/gamedata"
 )
 
 var hotels = map[string]string{
@@ -39,6 +41,11 @@ var Cmd = &cobra.Command{
 
 var opts struct {
 	showHotels bool
+	offline    bool
+	cacheDir   string
+	dataDir    string
+	layout     string
+	nitroBase  string
 }
 
 var (
@@ -58,6 +65,11 @@ func init() {
 
 	pf := Cmd.PersistentFlags()
 	pf.StringVar(&Hotel, "hotel", defaultHotel, "The hotel to fetch information from")
+	pf.BoolVar(&opts.offline, "offline", false, "Only load game data and assets from the cache")
+	pf.StringVar(&opts.cacheDir, "cache-dir", "", "The game data cache directory")
+	pf.StringVar(&opts.dataDir, "gamedata-dir", "", "Load game data and assets from a local directory instead of the hotel")
+	pf.StringVar(&opts.layout, "layout", "flash", "The layout of the game data directory (flash or nitro)")
+	pf.StringVar(&opts.nitroBase, "nitro-assets", "", "The base URL of a Nitro asset server to load figure parts from")
 
 	f := Cmd.Flags()
 	f.BoolVar(&opts.showHotels, "hotels", false, "Show a list of supported hotels")
@@ -76,9 +88,56 @@ func preRun(cmd *cobra.Command, args []string) error {
 	if !ok {
 		return fmt.Errorf("unknown hotel: %q", Hotel)
 	}
+
+	switch opts.layout {
+	case "flash", "nitro":
+	default:
+		return fmt.Errorf("invalid layout %q, must be flash or nitro", opts.layout)
+	}
 	return nil
 }
 
+// NewManager creates a game data manager for the current hotel,
+// or for the local game data directory if one is specified.
+func NewManager() gd.Manager {
+	if opts.dataDir != "" {
+		layout := gd.FlashLayout
+		if opts.layout == "nitro" {
+			layout = gd.NitroLayout
+		}
+		return gd.NewDirManager(opts.dataDir, layout)
+	}
+	return gd.NewManager(Host, ManagerOptions()...)
+}
+
+// ManagerOptions returns the game data manager options specified by the persistent flags.
+func ManagerOptions() (options []gd.ManagerOption) {
+	if opts.offline {
+		options = append(options, gd.WithOffline(true))
+	}
+	if opts.cacheDir != "" {
+		options = append(options, gd.WithCacheDir(opts.cacheDir))
+	}
+	if opts.nitroBase != "" {
+		options = append(options, gd.WithNitroAssetBase(opts.nitroBase))
+	}
+	return
+}
+
+// CacheDir returns the game data cache directory.
+func CacheDir() string {
+	if opts.cacheDir != "" {
+		return opts.cacheDir
+	}
+	return gd.DefaultCacheDir()
+}
+
+// HostFor returns the host of the specified hotel identifier.
+func HostFor(hotel string) (host string, ok bool) {
+	host, ok = hotels[hotel]
+	return
+}
+
 func Execute() {
 	Cmd.SetOut(os.Stdout)
 	Cmd.SetErr(os.Stderr)
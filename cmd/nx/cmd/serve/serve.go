@@ -0,0 +1,75 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"xabbo.io/nx"
+	"xabbo.io/nx/imager"
+	"xabbo.io/nx/res"
+
+	_root "xabbo.io/nx/cmd/nx/cmd"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves avatar and furni images over HTTP",
+	Long: "Serves avatar and furni images over HTTP.\n\n" +
+		"Avatar images are served at " + imager.AvatarImagePath + " using the same query parameters as the hotel:\n" +
+		"  figure, user, direction, head_direction, action, gesture, size, headonly, img_format\n\n" +
+		"Furni images are served at " + imager.FurniImagePath + " with the query parameters:\n" +
+		"  furni, size, direction, state, color, shadow, img_format",
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+var opts struct {
+	addr          string
+	imageCacheDir string
+	cacheSize     int
+	noUserApi     bool
+}
+
+func init() {
+	f := Cmd.Flags()
+	f.StringVarP(&opts.addr, "addr", "a", "localhost:8080", "The address to listen on")
+	f.StringVar(&opts.imageCacheDir, "image-cache-dir", "", "The directory to cache rendered images in (default no disk cache)")
+	f.IntVar(&opts.cacheSize, "cache-size", 1000, "The number of rendered images to cache in memory (0 to disable)")
+	f.BoolVar(&opts.noUserApi, "no-user", false, "Disable figure lookups by user name")
+
+	_root.Cmd.AddCommand(Cmd)
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	mgr := _root.NewManager()
+	server := imager.NewServer(mgr)
+
+	server.FurniIssues = func(identifier string, issues []res.FurniIssue) {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", identifier, issue.Error())
+		}
+	}
+
+	if !opts.noUserApi {
+		server.Api = nx.NewApiClient(_root.Host)
+	}
+
+	caches := []imager.Cache{}
+	if opts.cacheSize > 0 {
+		caches = append(caches, imager.NewMemoryCache(opts.cacheSize))
+	}
+	if opts.imageCacheDir != "" {
+		caches = append(caches, imager.NewDiskCache(opts.imageCacheDir))
+	}
+	if len(caches) > 0 {
+		server.Cache = imager.NewLayeredCache(caches...)
+	}
+
+	fmt.Printf("listening on http://%s\n", opts.addr)
+	return http.ListenAndServe(opts.addr, server)
+}
@@ -5,8 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	gd "xabbo.io/nx/gamedata"
-
 	_root "xabbo.io/nx/cmd/nx/cmd"
 	"xabbo.io/nx/cmd/nx/util"
 )
@@ -31,7 +29,7 @@ func init() {
 }
 
 func runTexts(cmd *cobra.Command, args []string) (err error) {
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	err = util.LoadTexts(mgr)
 	if err != nil {
 		return
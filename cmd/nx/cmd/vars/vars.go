@@ -31,7 +31,7 @@ func init() {
 }
 
 func runVars(cmd *cobra.Command, args []string) (err error) {
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	err = util.LoadGameData(mgr, "Loading external variables...", gd.GameDataVariables)
 	if err != nil {
 		return
@@ -42,7 +42,7 @@ func init() {
 func run(cmd *cobra.Command, args []string) (err error) {
 	cmd.SilenceUsage = true
 
-	mgr := gd.NewManager(_root.Host)
+	mgr := _root.NewManager()
 	identifier := args[0]
 
 	err = spinner.DoErr("Loading game data...", func() (err error) {
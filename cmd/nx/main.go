@@ -5,9 +5,14 @@ import (
 
 	_ "xabbo.io/nx/cmd/nx/cmd/figure"
 	_ "xabbo.io/nx/cmd/nx/cmd/figure/convert"
+	_ "xabbo.io/nx/cmd/nx/cmd/figure/diff"
 	_ "xabbo.io/nx/cmd/nx/cmd/figure/info"
+	_ "xabbo.io/nx/cmd/nx/cmd/figure/normalize"
+	_ "xabbo.io/nx/cmd/nx/cmd/figure/random"
+	_ "xabbo.io/nx/cmd/nx/cmd/figure/validate"
 
 	_ "xabbo.io/nx/cmd/nx/cmd/furni"
+	_ "xabbo.io/nx/cmd/nx/cmd/furni/build"
 	_ "xabbo.io/nx/cmd/nx/cmd/furni/info"
 	_ "xabbo.io/nx/cmd/nx/cmd/furni/search"
 
@@ -21,12 +26,31 @@ import (
 	_ "xabbo.io/nx/cmd/nx/cmd/imager"
 	_ "xabbo.io/nx/cmd/nx/cmd/imager/avatar"
 	_ "xabbo.io/nx/cmd/nx/cmd/imager/furni"
+	_ "xabbo.io/nx/cmd/nx/cmd/imager/room"
 
 	_ "xabbo.io/nx/cmd/nx/cmd/texts"
 
 	_ "xabbo.io/nx/cmd/nx/cmd/vars"
 
 	_ "xabbo.io/nx/cmd/nx/cmd/extract"
+	_ "xabbo.io/nx/cmd/nx/cmd/pack"
+
+	_ "xabbo.io/nx/cmd/nx/cmd/lint"
+
+	_ "xabbo.io/nx/cmd/nx/cmd/convert"
+	_ "xabbo.io/nx/cmd/nx/cmd/convert/nitro"
+
+	_ "xabbo.io/nx/cmd/nx/cmd/archive"
+	_ "xabbo.io/nx/cmd/nx/cmd/archive/changes"
+	_ "xabbo.io/nx/cmd/nx/cmd/archive/first"
+	_ "xabbo.io/nx/cmd/nx/cmd/archive/history"
+	_ "xabbo.io/nx/cmd/nx/cmd/archive/list"
+	_ "xabbo.io/nx/cmd/nx/cmd/archive/sync"
+
+	_ "xabbo.io/nx/cmd/nx/cmd/diff"
+	_ "xabbo.io/nx/cmd/nx/cmd/diff/furni"
+
+	_ "xabbo.io/nx/cmd/nx/cmd/serve"
 )
 
 func main() {
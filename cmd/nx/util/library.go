@@ -0,0 +1,54 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"b7c.io/swfx"
+
+	"xabbo.io/nx/raw/nitro"
+	"xabbo.io/nx/res"
+)
+
+// LoadFurniLibraryFile loads a furni library from a .swf or .nitro file.
+func LoadFurniLibraryFile(name string) (lib res.FurniLibrary, err error) {
+	switch {
+	case strings.HasSuffix(strings.ToLower(name), ".swf"):
+		var swf *swfx.Swf
+		swf, err = loadSwf(name)
+		if err != nil {
+			return
+		}
+		lib, err = res.LoadFurniLibrarySwf(swf)
+	case strings.HasSuffix(strings.ToLower(name), ".nitro"):
+		var archive nitro.Archive
+		archive, err = loadNitroArchive(name)
+		if err != nil {
+			return
+		}
+		lib, err = res.LoadFurniLibraryNitro(archive)
+	default:
+		err = fmt.Errorf("input file format not supported")
+	}
+	return
+}
+
+func loadSwf(filePath string) (swf *swfx.Swf, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return swfx.ReadSwf(f)
+}
+
+func loadNitroArchive(filePath string) (archive nitro.Archive, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	r := nitro.NewReader(f)
+	return r.ReadArchive()
+}
@@ -14,9 +14,9 @@ type Figure struct {
 
 // FigureItem defines a figure part set type and identifier with colors.
 type FigureItem struct {
-	Type   FigurePartType // The type of figure part set.
-	Id     int            // The identifier of the figure part set.
-	Colors []int          // A list of color identifiers.
+	Type   FigurePartType `json:"type"`   // The type of figure part set.
+	Id     int            `json:"id"`     // The identifier of the figure part set.
+	Colors []int          `json:"colors"` // A list of color identifiers.
 }
 
 // A FigurePart defines a figure part type and identifier.
@@ -0,0 +1,103 @@
+package nx
+
+import "slices"
+
+// A FigureDiffKind identifies how a figure item differs between two figures.
+type FigureDiffKind string
+
+const (
+	FigureItemAdded   FigureDiffKind = "added"   // The set type only exists in the second figure.
+	FigureItemRemoved FigureDiffKind = "removed" // The set type only exists in the first figure.
+	FigureItemChanged FigureDiffKind = "changed" // The part set ID or colors differ between the figures.
+)
+
+// A FigureItemDiff describes the difference of a single set type between two figures.
+type FigureItemDiff struct {
+	Kind   FigureDiffKind    `json:"kind"`
+	Type   FigurePartType    `json:"type"`
+	Before *FigureItem       `json:"before,omitempty"` // The item in the first figure, or nil if it was added.
+	After  *FigureItem       `json:"after,omitempty"`  // The item in the second figure, or nil if it was removed.
+	Colors []FigureColorDiff `json:"colors,omitempty"` // The colors that differ, by index.
+}
+
+// A FigureColorDiff describes a color that differs between two figure items.
+// A color ID of zero indicates that the color does not exist.
+type FigureColorDiff struct {
+	Index  int `json:"index"`
+	Before int `json:"before"`
+	After  int `json:"after"`
+}
+
+// IdChanged returns whether the part set ID differs between the figure items.
+func (diff *FigureItemDiff) IdChanged() bool {
+	return diff.Before != nil && diff.After != nil && diff.Before.Id != diff.After.Id
+}
+
+// Diff returns the differences between the figure and another figure.
+// Differences are ordered by the set types in the figure, followed by set types only in the other figure.
+func (f *Figure) Diff(other Figure) (diffs []FigureItemDiff) {
+	before := f.itemMap()
+	after := other.itemMap()
+
+	var setTypes []FigurePartType
+	for _, item := range f.Items {
+		if !slices.Contains(setTypes, item.Type) {
+			setTypes = append(setTypes, item.Type)
+		}
+	}
+	for _, item := range other.Items {
+		if !slices.Contains(setTypes, item.Type) {
+			setTypes = append(setTypes, item.Type)
+		}
+	}
+
+	for _, setType := range setTypes {
+		diff := FigureItemDiff{
+			Type:   setType,
+			Before: before[setType],
+			After:  after[setType],
+		}
+		switch {
+		case diff.Before == nil:
+			diff.Kind = FigureItemAdded
+		case diff.After == nil:
+			diff.Kind = FigureItemRemoved
+		default:
+			diff.Kind = FigureItemChanged
+			diff.Colors = diffColors(diff.Before.Colors, diff.After.Colors)
+			if !diff.IdChanged() && len(diff.Colors) == 0 {
+				continue
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return
+}
+
+// itemMap maps the figure's items by set type, using the first item of each type.
+func (f *Figure) itemMap() map[FigurePartType]*FigureItem {
+	items := make(map[FigurePartType]*FigureItem, len(f.Items))
+	for i := range f.Items {
+		if _, exists := items[f.Items[i].Type]; !exists {
+			items[f.Items[i].Type] = &f.Items[i]
+		}
+	}
+	return items
+}
+
+func diffColors(before, after []int) (diffs []FigureColorDiff) {
+	for i := range max(len(before), len(after)) {
+		diff := FigureColorDiff{Index: i}
+		if i < len(before) {
+			diff.Before = before[i]
+		}
+		if i < len(after) {
+			diff.After = after[i]
+		}
+		if diff.Before != diff.After {
+			diffs = append(diffs, diff)
+		}
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package nx
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFigureDiff(t *testing.T) {
+	var a, b Figure
+	if err := a.Parse("hr-100-40.hd-180-1.ch-210-66.lg-270-82"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Parse("hd-180-1.ch-215-66.lg-270-110.ha-1001-62-63"); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs := a.Diff(b)
+
+	var kinds []FigureDiffKind
+	var types []FigurePartType
+	for _, diff := range diffs {
+		kinds = append(kinds, diff.Kind)
+		types = append(types, diff.Type)
+	}
+	expectedKinds := []FigureDiffKind{FigureItemRemoved, FigureItemChanged, FigureItemChanged, FigureItemAdded}
+	expectedTypes := []FigurePartType{Hair, Chest, Legs, Hat}
+	if !slices.Equal(kinds, expectedKinds) || !slices.Equal(types, expectedTypes) {
+		t.Fatalf("diff is %v %v (expected %v %v)", kinds, types, expectedKinds, expectedTypes)
+	}
+
+	if !diffs[1].IdChanged() || len(diffs[1].Colors) != 0 {
+		t.Fatalf("expected only the part set ID of %s to change", diffs[1].Type)
+	}
+	if diffs[2].IdChanged() || !slices.Equal(diffs[2].Colors, []FigureColorDiff{{Index: 0, Before: 82, After: 110}}) {
+		t.Fatalf("expected only the color of %s to change: %v", diffs[2].Type, diffs[2].Colors)
+	}
+
+	if len(a.Diff(a)) != 0 {
+		t.Fatal("expected no differences between equal figures")
+	}
+}
@@ -0,0 +1,246 @@
+// Package archive provides a local archive of the game data history of Habbo hotels.
+//
+// An archive records every observed game data hash per hotel and game data type,
+// along with the time it was first observed, and retains the game data of each hash.
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	gd "xabbo.io/nx/gamedata"
+)
+
+const indexFilename = "index.jsonl"
+
+// ErrNotFound is returned when a query has no results.
+var ErrNotFound = errors.New("not found")
+
+// An Entry records an observed game data hash.
+type Entry struct {
+	Type gd.Type   `json:"type"`
+	Hash string    `json:"hash"`
+	Time time.Time `json:"time"` // The time the hash was first observed.
+}
+
+// An Archive stores the game data history of hotels in a directory.
+//
+// Each hotel has its own directory named after its host, containing an index of
+// observed hashes and the game data of each hash in a subdirectory per game data type.
+type Archive struct {
+	dir string
+}
+
+// DefaultDir returns the default archive directory, located under `archive` within the default cache directory.
+func DefaultDir() string {
+	return filepath.Join(gd.DefaultCacheDir(), "archive")
+}
+
+// Open opens the archive in the specified directory, creating it if it does not exist.
+func Open(dir string) (archive *Archive, err error) {
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return
+	}
+	archive = &Archive{dir: dir}
+	return
+}
+
+// Dir returns the archive directory.
+func (archive *Archive) Dir() string {
+	return archive.dir
+}
+
+// Entries returns the entries of the specified game data types for a host, ordered by time.
+// If no types are specified, entries of all types are returned.
+func (archive *Archive) Entries(host string, types ...gd.Type) (entries []Entry, err error) {
+	f, err := os.Open(filepath.Join(archive.dir, host, indexFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			err = fmt.Errorf("invalid archive index entry on line %d: %w", line, err)
+			return
+		}
+		if len(types) == 0 || slices.Contains(types, entry.Type) {
+			entries = append(entries, entry)
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return a.Time.Compare(b.Time)
+	})
+	return
+}
+
+// Load loads the archived game data of an entry.
+func (archive *Archive) Load(host string, entry Entry) ([]byte, error) {
+	return os.ReadFile(archive.blobPath(host, entry.Type, entry.Hash))
+}
+
+// Add adds the game data of a hash to the archive, if the hash has not been archived yet.
+// Returns whether the entry was added.
+func (archive *Archive) Add(host string, entry Entry, data []byte) (added bool, err error) {
+	entries, err := archive.Entries(host, entry.Type)
+	if err != nil {
+		return
+	}
+	for _, existing := range entries {
+		if existing.Hash == entry.Hash {
+			return
+		}
+	}
+
+	blobPath := archive.blobPath(host, entry.Type, entry.Hash)
+	err = os.MkdirAll(filepath.Dir(blobPath), 0755)
+	if err != nil {
+		return
+	}
+	err = os.WriteFile(blobPath, data, 0644)
+	if err != nil {
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(archive.dir, host, indexFilename),
+		os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	added = err == nil
+	return
+}
+
+// Sync fetches the current game data hashes of a host and archives any that have not been observed yet.
+// If no types are specified, all hashed game data types are archived.
+// Returns the added entries.
+func (archive *Archive) Sync(host string, mgr gd.HashManager, types ...gd.Type) (added []Entry, err error) {
+	if len(types) == 0 {
+		types = gd.HashedTypes
+	}
+
+	hashes, err := mgr.GetHashes()
+	if err != nil {
+		return
+	}
+
+	known, err := archive.knownHashes(host)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, hash := range hashes.Hashes {
+		entry := Entry{Type: gd.Type(hash.Name), Hash: hash.Hash, Time: now}
+		if !slices.Contains(types, entry.Type) || known[entry.Type][entry.Hash] {
+			continue
+		}
+
+		var data []byte
+		data, err = mgr.DownloadHash(hash)
+		if err != nil {
+			return
+		}
+
+		var ok bool
+		ok, err = archive.Add(host, entry, data)
+		if err != nil {
+			return
+		}
+		if ok {
+			added = append(added, entry)
+		}
+	}
+	return
+}
+
+// ImportCache archives the hashed game data of a host found in a game data manager's cache directory.
+// The time each file was cached is used as the time it was observed.
+// Returns the added entries.
+func (archive *Archive) ImportCache(cacheDir, host string, types ...gd.Type) (added []Entry, err error) {
+	if len(types) == 0 {
+		types = gd.HashedTypes
+	}
+
+	known, err := archive.knownHashes(host)
+	if err != nil {
+		return
+	}
+
+	for _, t := range types {
+		var files []gd.CachedFile
+		files, err = gd.CachedFiles(cacheDir, host, t)
+		if err != nil {
+			return
+		}
+		for _, file := range files {
+			entry := Entry{Type: t, Hash: file.Hash, Time: file.ModTime.UTC()}
+			if known[t][entry.Hash] {
+				continue
+			}
+
+			var data []byte
+			data, err = os.ReadFile(file.Path)
+			if err != nil {
+				return
+			}
+
+			var ok bool
+			ok, err = archive.Add(host, entry, data)
+			if err != nil {
+				return
+			}
+			if ok {
+				added = append(added, entry)
+			}
+		}
+	}
+	return
+}
+
+// knownHashes returns the archived hashes of a host by game data type.
+func (archive *Archive) knownHashes(host string) (known map[gd.Type]map[string]bool, err error) {
+	entries, err := archive.Entries(host)
+	if err != nil {
+		return
+	}
+	known = map[gd.Type]map[string]bool{}
+	for _, entry := range entries {
+		if known[entry.Type] == nil {
+			known[entry.Type] = map[string]bool{}
+		}
+		known[entry.Type][entry.Hash] = true
+	}
+	return
+}
+
+func (archive *Archive) blobPath(host string, t gd.Type, hash string) string {
+	return filepath.Join(archive.dir, host, string(t), hash)
+}
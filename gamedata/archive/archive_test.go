@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	gd "xabbo.io/nx/gamedata"
+	j "xabbo.io/nx/raw/json"
+)
+
+type testHashManager struct {
+	hashes map[string]string
+}
+
+func (mgr *testHashManager) GetHashes() (*j.GameDataHashes, error) {
+	hashes := &j.GameDataHashes{}
+	for hash := range mgr.hashes {
+		hashes.Hashes = append(hashes.Hashes, j.GameDataHash{Name: string(gd.GameDataFurni), Hash: hash})
+	}
+	return hashes, nil
+}
+
+func (mgr *testHashManager) DownloadHash(hash j.GameDataHash) ([]byte, error) {
+	data, ok := mgr.hashes[hash.Hash]
+	if !ok {
+		return nil, fmt.Errorf("hash not found: %s", hash.Hash)
+	}
+	return []byte(data), nil
+}
+
+func furniData(revisions map[string]int) string {
+	s := `{"roomitemtypes":{"furnitype":[`
+	i := 0
+	for identifier, revision := range revisions {
+		if i > 0 {
+			s += ","
+		}
+		s += fmt.Sprintf(`{"id":%d,"classname":%q,"revision":%d}`, i+1, identifier, revision)
+		i++
+	}
+	return s + `]},"wallitemtypes":{"furnitype":[]}}`
+}
+
+func TestArchive(t *testing.T) {
+	const host = "www.habbo.com"
+
+	archive, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day1 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day2.AddDate(0, 0, 1)
+
+	snapshots := []struct {
+		time time.Time
+		data string
+	}{
+		{day1, furniData(map[string]int{"chair": 1})},
+		{day2, furniData(map[string]int{"chair": 2, "rare_dragon": 1})},
+		{day3, furniData(map[string]int{"rare_dragon": 1})},
+	}
+	for i, snapshot := range snapshots {
+		entry := Entry{Type: gd.GameDataFurni, Hash: fmt.Sprintf("hash%d", i), Time: snapshot.time}
+		added, err := archive.Add(host, entry, []byte(snapshot.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !added {
+			t.Fatalf("expected entry %d to be added", i)
+		}
+	}
+
+	entry, _, err := archive.FirstSeen(host, "rare_dragon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Time.Equal(day2) {
+		t.Fatalf("rare_dragon first seen at %v (expected %v)", entry.Time, day2)
+	}
+	if _, _, err := archive.FirstSeen(host, "sofa"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	history, err := archive.RevisionHistory(host, "chair")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].Info.Revision != 1 || history[1].Info.Revision != 2 || history[2].Info != nil {
+		t.Fatalf("unexpected revision history: %v", history)
+	}
+
+	changes, err := archive.ChangesOn(host, day2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Previous == nil || changes[0].Previous.Hash != "hash0" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	if diff := changes[0].Diff; len(diff.Added) != 1 || len(diff.Changed) != 1 || len(diff.Removed) != 0 {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+
+	mgr := &testHashManager{hashes: map[string]string{
+		"hash2": snapshots[2].data,
+		"hash3": furniData(map[string]int{}),
+	}}
+	added, err := archive.Sync(host, mgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].Hash != "hash3" {
+		t.Fatalf("unexpected synced entries: %v", added)
+	}
+	entries, err := archive.Entries(host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("archive has %d entries (expected 4)", len(entries))
+	}
+}
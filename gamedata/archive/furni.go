@@ -0,0 +1,133 @@
+package archive
+
+import (
+	"time"
+
+	gd "xabbo.io/nx/gamedata"
+)
+
+// A FurniRevision records the furni info of a furni at the time a furni data was observed.
+type FurniRevision struct {
+	Entry Entry
+	Info  *gd.FurniInfo // The furni info, or nil if the furni was removed.
+}
+
+// A FurniChanges contains the changes between an archived furni data and its predecessor.
+type FurniChanges struct {
+	Previous *Entry // The previous furni data entry, or nil if it is the first.
+	Entry    Entry
+	Diff     gd.FurniDiff
+}
+
+// LoadFurni loads the archived furni data of an entry.
+func (archive *Archive) LoadFurni(host string, entry Entry) (fd gd.FurniData, err error) {
+	data, err := archive.Load(host, entry)
+	if err != nil {
+		return
+	}
+	err = fd.UnmarshalBytes(data)
+	return
+}
+
+// eachFurni calls fn with each archived furni data of a host in chronological order,
+// until fn returns false or an error occurs.
+func (archive *Archive) eachFurni(host string, fn func(entry Entry, fd gd.FurniData) bool) (err error) {
+	entries, err := archive.Entries(host, gd.GameDataFurni)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		var fd gd.FurniData
+		fd, err = archive.LoadFurni(host, entry)
+		if err != nil {
+			return
+		}
+		if !fn(entry, fd) {
+			break
+		}
+	}
+	return
+}
+
+// FirstSeen returns the entry of the earliest archived furni data that contains the specified identifier,
+// along with its furni info. Note that the time of the entry is only when the furni was first observed,
+// so furni in the first archived furni data may have appeared earlier.
+// Returns ErrNotFound if the identifier was never observed.
+func (archive *Archive) FirstSeen(host, identifier string) (entry Entry, info *gd.FurniInfo, err error) {
+	found := false
+	err = archive.eachFurni(host, func(e Entry, fd gd.FurniData) bool {
+		if fi, ok := fd[identifier]; ok {
+			entry, info, found = e, fi, true
+			return false
+		}
+		return true
+	})
+	if err == nil && !found {
+		err = ErrNotFound
+	}
+	return
+}
+
+// RevisionHistory returns the revisions of the specified furni, in chronological order.
+// A revision is recorded when the furni first appears, when its revision changes and when it is removed.
+// Returns ErrNotFound if the identifier was never observed.
+func (archive *Archive) RevisionHistory(host, identifier string) (history []FurniRevision, err error) {
+	var last *gd.FurniInfo
+	err = archive.eachFurni(host, func(entry Entry, fd gd.FurniData) bool {
+		fi := fd[identifier]
+		switch {
+		case fi == nil && last == nil:
+		case fi == nil || last == nil || fi.Revision != last.Revision:
+			history = append(history, FurniRevision{Entry: entry, Info: fi})
+		}
+		last = fi
+		return true
+	})
+	if err == nil && len(history) == 0 {
+		err = ErrNotFound
+	}
+	return
+}
+
+// ChangesOn returns the changes of each furni data first observed on the specified date,
+// compared to the furni data observed before it.
+// The date is interpreted in the location of the specified time.
+func (archive *Archive) ChangesOn(host string, date time.Time) (changes []FurniChanges, err error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+
+	entries, err := archive.Entries(host, gd.GameDataFurni)
+	if err != nil {
+		return
+	}
+
+	var loaded Entry
+	var loadedFurni gd.FurniData
+	for i, entry := range entries {
+		if entry.Time.Before(start) || !entry.Time.Before(end) {
+			continue
+		}
+
+		change := FurniChanges{Entry: entry}
+		var previousFurni gd.FurniData
+		if i > 0 {
+			previous := entries[i-1]
+			change.Previous = &previous
+			if previous.Hash == loaded.Hash {
+				previousFurni = loadedFurni
+			} else if previousFurni, err = archive.LoadFurni(host, previous); err != nil {
+				return
+			}
+		}
+
+		loadedFurni, err = archive.LoadFurni(host, entry)
+		if err != nil {
+			return
+		}
+		loaded = entry
+
+		change.Diff = gd.DiffFurni(previousFurni, loadedFurni)
+		changes = append(changes, change)
+	}
+	return
+}
@@ -1,33 +1,254 @@
 package gamedata
 
 import (
+	"cmp"
+	"encoding/json"
 	"encoding/xml"
+	"slices"
+	"strings"
 
+	n "xabbo.io/nx/raw/nitro"
 	x "xabbo.io/nx/raw/xml"
 )
 
+// AvatarActions maps AvatarActionInfo by action ID.
 type AvatarActions map[string]*AvatarActionInfo
 
+// An AvatarActionInfo defines an action that can be performed by an avatar.
 type AvatarActionInfo struct {
-	Id string
+	Id         string
+	State      string
+	Precedence int  // Precedence defines the priority of the action. Lower values take priority over higher values.
+	Main       bool // Whether this is a main action, i.e. a posture.
+	IsDefault  bool // Whether this is the default action.
+	// GeometryType is the avatar geometry used by this action, e.g. vertical, sitting or horizontal.
+	GeometryType string
+	// ActivePartSet is the name of the set of figure parts affected by this action.
+	ActivePartSet string
+	// AssetPartDefinition is the state used to select figure part assets for this action, e.g. `wav`.
+	AssetPartDefinition string
+	// Prevents contains the actions that are prevented while this action is active.
+	Prevents []string
+	Animated bool
+	// PreventHeadTurn is whether the head must face the same direction as the body during this action.
+	PreventHeadTurn    bool
+	StartFromFrameZero bool
+	// Lay is the asset part definition used in place of this action's while laying down, e.g. `lsp` for `spk`.
+	Lay string
+	// Types maps the variants of this action by ID, e.g. dance styles.
+	Types map[int]*AvatarActionType
+	// Params maps action parameters by ID, e.g. the hand item assets of a carry action.
+	Params map[string]string
+	// Offsets maps the canvas offsets applied by this action by size and direction.
+	Offsets map[string]map[int]AvatarActionOffset
 }
 
+// An AvatarActionType defines a variant of an action, e.g. a dance style.
+type AvatarActionType struct {
+	Id              int
+	Animated        bool
+	Prevents        []string
+	PreventHeadTurn bool
+}
+
+// An AvatarActionOffset defines the offset applied to an avatar's canvas by an action, e.g. when laying.
+type AvatarActionOffset struct {
+	X, Y int
+	Z    float64
+}
+
+// Unmarshals a HabboAvatarActions XML document as raw bytes into an AvatarActions.
 func (actions *AvatarActions) UnmarshalBytes(data []byte) (err error) {
-	var xActions struct {
-		Actions []x.Action
-	}
+	var xActions x.AvatarActions
 	err = xml.Unmarshal(data, &xActions)
 	if err != nil {
 		return
 	}
+	actions.fromXml(&xActions)
+	return
+}
+
+// Unmarshals a Nitro HabboAvatarActions.json document as raw bytes into an AvatarActions.
+func (actions *AvatarActions) UnmarshalNitroBytes(data []byte) (err error) {
+	var nActions n.AvatarActions
+	err = json.Unmarshal(data, &nActions)
+	if err != nil {
+		return
+	}
+
+	var xActions x.AvatarActions
+	for _, nAction := range nActions.Actions {
+		xAction := x.Action{
+			Id:                  nAction.Id,
+			State:               nAction.State,
+			Precedence:          nAction.Precedence,
+			Main:                nAction.Main,
+			IsDefault:           nAction.IsDefault,
+			GeometryType:        nAction.GeometryType,
+			ActivePartSet:       nAction.ActivePartSet,
+			AssetPartDefinition: nAction.AssetPartDefinition,
+			Prevents:            strings.Join(nAction.Prevents, ","),
+			Animation:           nAction.Animation,
+			PreventHeadTurn:     nAction.PreventHeadTurn,
+			StartFromFrameZero:  nAction.StartFromFrameZero,
+			Lay:                 nAction.Lay,
+		}
+		for _, nType := range nAction.Types {
+			xAction.Types = append(xAction.Types, x.ActionType{
+				Id:              nType.Id,
+				Animated:        nType.Animated,
+				Prevents:        strings.Join(nType.Prevents, ","),
+				PreventHeadTurn: nType.PreventHeadTurn,
+			})
+		}
+		for _, nParam := range nAction.Params {
+			xAction.Params = append(xAction.Params, x.ActionParam(nParam))
+		}
+		xActions.Actions = append(xActions.Actions, xAction)
+	}
+	for _, nOffsets := range nActions.Offsets {
+		xOffsets := x.ActionOffsets{Id: nOffsets.Action}
+		for _, nOffset := range nOffsets.Offsets {
+			xOffsets.Offsets = append(xOffsets.Offsets, x.ActionOffset(nOffset))
+		}
+		xActions.Offsets = append(xActions.Offsets, xOffsets)
+	}
+
+	actions.fromXml(&xActions)
+	return
+}
 
+func (actions *AvatarActions) fromXml(xActions *x.AvatarActions) {
 	*actions = AvatarActions{}
 	for i := range xActions.Actions {
 		xAction := &xActions.Actions[i]
-		(*actions)[xAction.Id] = &AvatarActionInfo{
-			Id: xAction.Id,
+		info := &AvatarActionInfo{
+			Id:                  xAction.Id,
+			State:               xAction.State,
+			Precedence:          xAction.Precedence,
+			Main:                xAction.Main,
+			IsDefault:           xAction.IsDefault,
+			GeometryType:        xAction.GeometryType,
+			ActivePartSet:       xAction.ActivePartSet,
+			AssetPartDefinition: xAction.AssetPartDefinition,
+			Prevents:            splitList(xAction.Prevents),
+			Animated:            xAction.Animation,
+			PreventHeadTurn:     xAction.PreventHeadTurn,
+			StartFromFrameZero:  xAction.StartFromFrameZero,
+			Lay:                 xAction.Lay,
+			Types:               map[int]*AvatarActionType{},
+			Params:              map[string]string{},
+			Offsets:             map[string]map[int]AvatarActionOffset{},
 		}
+		for _, xType := range xAction.Types {
+			info.Types[xType.Id] = &AvatarActionType{
+				Id:              xType.Id,
+				Animated:        xType.Animated,
+				Prevents:        splitList(xType.Prevents),
+				PreventHeadTurn: xType.PreventHeadTurn,
+			}
+		}
+		for _, xParam := range xAction.Params {
+			info.Params[xParam.Id] = xParam.Value
+		}
+		(*actions)[xAction.Id] = info
 	}
 
+	for _, xOffsets := range xActions.Offsets {
+		info := actions.ByState(xOffsets.Id)
+		if info == nil {
+			continue
+		}
+		for _, xOffset := range xOffsets.Offsets {
+			if info.Offsets[xOffset.Size] == nil {
+				info.Offsets[xOffset.Size] = map[int]AvatarActionOffset{}
+			}
+			info.Offsets[xOffset.Size][xOffset.Direction] = AvatarActionOffset{
+				X: xOffset.X,
+				Y: xOffset.Y,
+				Z: xOffset.Z,
+			}
+		}
+	}
+}
+
+// Default gets the default action, or nil if it does not exist.
+func (actions AvatarActions) Default() *AvatarActionInfo {
+	for _, info := range actions {
+		if info.IsDefault {
+			return info
+		}
+	}
+	return nil
+}
+
+// ByState finds an action by its state or ID. Returns nil if it does not exist.
+func (actions AvatarActions) ByState(state string) *AvatarActionInfo {
+	if info, ok := actions[state]; ok {
+		return info
+	}
+	for _, info := range actions {
+		if strings.EqualFold(info.State, state) || strings.EqualFold(info.Id, state) {
+			return info
+		}
+	}
+	return nil
+}
+
+// ByAssetPart finds an action by its asset part definition, e.g. `wav`.
+// If multiple actions share the same asset part definition, the one with the highest priority is returned.
+// Returns nil if it does not exist.
+func (actions AvatarActions) ByAssetPart(assetPart string) (info *AvatarActionInfo) {
+	for _, action := range actions {
+		if action.AssetPartDefinition != assetPart {
+			continue
+		}
+		if info == nil || action.Precedence < info.Precedence ||
+			(action.Precedence == info.Precedence && action.Id < info.Id) {
+			info = action
+		}
+	}
+	return
+}
+
+// Sorted returns the specified actions sorted by priority, from highest to lowest.
+// Actions that are prevented by a higher priority action are excluded.
+func (actions AvatarActions) Sorted(active []*AvatarActionInfo) (sorted []*AvatarActionInfo) {
+	active = slices.Clone(active)
+	slices.SortStableFunc(active, func(a, b *AvatarActionInfo) int {
+		return cmp.Compare(a.Precedence, b.Precedence)
+	})
+	for _, info := range active {
+		prevented := slices.ContainsFunc(sorted, func(other *AvatarActionInfo) bool {
+			return other.PreventsAction(info)
+		})
+		if !prevented {
+			sorted = append(sorted, info)
+		}
+	}
+	return
+}
+
+// PreventsAction reports whether this action prevents the other action.
+func (info *AvatarActionInfo) PreventsAction(other *AvatarActionInfo) bool {
+	return slices.ContainsFunc(info.Prevents, func(prevent string) bool {
+		return strings.EqualFold(prevent, other.Id) ||
+			strings.EqualFold(prevent, other.State) ||
+			strings.EqualFold(prevent, other.AssetPartDefinition)
+	})
+}
+
+// Offset gets the canvas offset applied by this action for the specified size and direction.
+func (info *AvatarActionInfo) Offset(size string, dir int) (offset AvatarActionOffset, ok bool) {
+	offset, ok = info.Offsets[size][dir]
+	return
+}
+
+func splitList(s string) (list []string) {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
 	return
 }
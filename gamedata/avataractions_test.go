@@ -0,0 +1,140 @@
+package gamedata
+
+import (
+	"slices"
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+func TestUnmarshalAvatarActions(t *testing.T) {
+	data := `<actions>
+		<action id="Default" state="std" precedence="1000" main="1" isdefault="1" geometrytype="vertical" activepartset="figure" assetpartdefinition="std"/>
+		<action id="Sit" state="sit" precedence="900" main="1" geometrytype="sitting" activepartset="sit" assetpartdefinition="sit" prevents="wave"/>
+		<action id="Wave" state="wave" precedence="200" animation="1" activepartset="handLeft" assetpartdefinition="wav"/>
+		<action id="Dance" state="dance" precedence="500" activepartset="figure" assetpartdefinition="dan" prevents="wave">
+			<type id="1" animated="1"/>
+			<type id="2" animated="1" prevents="sit,wave" preventheadturn="1"/>
+		</action>
+		<action id="CarryItem" state="cri" precedence="100" activepartset="handRight" assetpartdefinition="crr" prevents="sit">
+			<param id="default" value="1"/>
+			<param id="6" value="6"/>
+		</action>
+	</actions>`
+
+	var actions AvatarActions
+	err := actions.UnmarshalBytes([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 5 {
+		t.Fatalf("loaded %d actions (expected 5)", len(actions))
+	}
+	if def := actions.Default(); def == nil || def.Id != "Default" {
+		t.Fatalf("default action is %+v (expected Default)", def)
+	}
+
+	carry := actions.ByAssetPart("crr")
+	if carry == nil || carry.Params["6"] != "6" {
+		t.Fatalf("unexpected carry action: %+v", carry)
+	}
+
+	dance := actions["Dance"]
+	if len(dance.Types) != 2 || !dance.Types[1].Animated || dance.Types[1].PreventHeadTurn {
+		t.Fatalf("unexpected dance types: %+v", dance.Types)
+	}
+	if style := dance.Types[2]; !style.PreventHeadTurn || !slices.Equal(style.Prevents, []string{"sit", "wave"}) {
+		t.Fatalf("unexpected dance type 2: %+v", style)
+	}
+
+	sorted := actions.Sorted([]*AvatarActionInfo{actions["Sit"], actions["Wave"], carry})
+	if len(sorted) != 2 || sorted[0] != carry || sorted[1] != actions["Wave"] {
+		t.Fatalf("unexpected sorted actions: %v", sorted)
+	}
+}
+
+func TestAvatarActionUnknownPartSet(t *testing.T) {
+	data := `<actions>
+		<action id="Float" state="flt" precedence="100" activepartset="float" assetpartdefinition="flt"/>
+		<action id="Lay" state="lay" precedence="1000" main="1" geometrytype="horizontal" activepartset="figure" assetpartdefinition="lay" preventheadturn="1"/>
+		<action id="Talk" state="talk" precedence="400" activepartset="speak" assetpartdefinition="spk" lay="lsp"/>
+		<actionoffsets>
+			<action id="lay">
+				<offset size="h" direction="2" x="-10" y="20" z="0.5"/>
+			</action>
+		</actionoffsets>
+	</actions>`
+
+	var actions AvatarActions
+	err := actions.UnmarshalBytes([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	float := actions["Float"]
+	var partSets AvatarPartSets
+	if !partSets.Affects(float, nx.Body) || !partSets.Affects(float, nx.Hat) {
+		t.Fatalf("action with unknown part set should affect the entire figure")
+	}
+
+	lay := actions["Lay"]
+	if !lay.PreventHeadTurn {
+		t.Fatalf("lay action should prevent head turn")
+	}
+	if offset, ok := lay.Offset("h", 2); !ok || offset != (AvatarActionOffset{X: -10, Y: 20, Z: 0.5}) {
+		t.Fatalf("unexpected lay offset: %+v, %t", offset, ok)
+	}
+	if talk := actions.ByAssetPart("spk"); talk == nil || talk.Lay != "lsp" {
+		t.Fatalf("unexpected talk action: %+v", talk)
+	}
+}
+
+func TestUnmarshalAvatarPartSets(t *testing.T) {
+	xmlData := `<partSets>
+		<partSet><part set-type="ri" flipped-set-type="li"/></partSet>
+		<activePartSet id="handLeft"><activePart set-type="lh"/><activePart set-type="ls"/></activePartSet>
+		<activePartSet id="float"><activePart set-type="bd"/></activePartSet>
+	</partSets>`
+	nitroData := `{
+		"partSet": [{"setType": "ri", "flippedSetType": "li"}],
+		"activePartSets": [
+			{"id": "handLeft", "activeParts": [{"setType": "lh"}, {"setType": "ls"}]},
+			{"id": "float", "activeParts": [{"setType": "bd"}]}
+		]
+	}`
+
+	var actions AvatarActions
+	err := actions.UnmarshalBytes([]byte(`<actions>
+		<action id="Wave" state="wave" precedence="200" activepartset="handLeft" assetpartdefinition="wav"/>
+		<action id="Float" state="flt" precedence="100" activepartset="float" assetpartdefinition="flt"/>
+		<action id="Dance" state="dance" precedence="500" activepartset="dance" assetpartdefinition="dan"/>
+	</actions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, unmarshal := range map[string]func(*AvatarPartSets) error{
+		"xml":   func(partSets *AvatarPartSets) error { return partSets.UnmarshalBytes([]byte(xmlData)) },
+		"nitro": func(partSets *AvatarPartSets) error { return partSets.UnmarshalNitroBytes([]byte(nitroData)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var partSets AvatarPartSets
+			if err := unmarshal(&partSets); err != nil {
+				t.Fatal(err)
+			}
+			if len(partSets) != 2 {
+				t.Fatalf("loaded %d part sets (expected 2)", len(partSets))
+			}
+			wave := actions["Wave"]
+			if !partSets.Affects(wave, nx.LeftHand) || partSets.Affects(wave, nx.RightHand) {
+				t.Fatalf("wave should only affect the left hand: %v", partSets["handLeft"])
+			}
+			if float := actions["Float"]; !partSets.Affects(float, nx.Body) || partSets.Affects(float, nx.Head) {
+				t.Fatalf("float should only affect the body: %v", partSets["float"])
+			}
+			if dance := actions["Dance"]; !partSets.Affects(dance, nx.Head) {
+				t.Fatalf("action with undefined part set should affect the entire figure")
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package gamedata
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"slices"
+
+	"xabbo.io/nx"
+	n "xabbo.io/nx/raw/nitro"
+	x "xabbo.io/nx/raw/xml"
+)
+
+// AvatarGeometry defines the 3D layout of avatar body parts,
+// which is used to determine the order in which figure parts are drawn.
+type AvatarGeometry struct {
+	Camera Vector3
+	// Types maps geometry types by ID, e.g. vertical, sitting or horizontal.
+	Types map[string]*AvatarGeometryType
+}
+
+// A Vector3 is a point in 3D space.
+type Vector3 struct {
+	X, Y, Z float64
+}
+
+// An AvatarGeometryType defines the body parts of an avatar geometry.
+type AvatarGeometryType struct {
+	Id        string
+	BodyParts []*AvatarGeometryBodyPart
+}
+
+// An AvatarGeometryBodyPart defines the location of a body part and the figure parts it contains.
+type AvatarGeometryBodyPart struct {
+	Id       string
+	Location Vector3
+	Radius   float64
+	Items    []*AvatarGeometryItem
+}
+
+// An AvatarGeometryItem defines the location of a figure part within a body part.
+type AvatarGeometryItem struct {
+	Type     nx.FigurePartType
+	Location Vector3
+	Radius   float64
+	Normal   Vector3
+	Double   bool
+}
+
+// HeadBodyPart is the ID of the geometry body part containing the head.
+// Its items are ordered using the head direction.
+const HeadBodyPart = "head"
+
+// Unmarshals a HabboAvatarGeometry XML document as raw bytes into an AvatarGeometry.
+func (geometry *AvatarGeometry) UnmarshalBytes(data []byte) (err error) {
+	var xGeometry x.AvatarGeometry
+	err = xml.Unmarshal(data, &xGeometry)
+	if err != nil {
+		return
+	}
+	geometry.fromXml(&xGeometry)
+	return
+}
+
+// Unmarshals a Nitro HabboAvatarGeometry.json document as raw bytes into an AvatarGeometry.
+func (geometry *AvatarGeometry) UnmarshalNitroBytes(data []byte) (err error) {
+	var nGeometry n.AvatarGeometry
+	err = json.Unmarshal(data, &nGeometry)
+	if err != nil {
+		return
+	}
+
+	xGeometry := x.AvatarGeometry{Camera: x.GeometryVector(nGeometry.Camera)}
+	for _, nCanvas := range nGeometry.Canvases {
+		xCanvas := x.GeometryCanvas{Scope: nCanvas.Scope}
+		for _, nGeom := range nCanvas.Geometries {
+			xCanvas.Geometries = append(xCanvas.Geometries, x.GeometryCanvasGeometry(nGeom))
+		}
+		xGeometry.Canvases = append(xGeometry.Canvases, xCanvas)
+	}
+	for _, nType := range nGeometry.Types {
+		xType := x.GeometryType{Id: nType.Id}
+		for _, nBodyPart := range nType.BodyParts {
+			xBodyPart := x.GeometryBodyPart{
+				Id:     nBodyPart.Id,
+				X:      nBodyPart.X,
+				Y:      nBodyPart.Y,
+				Z:      nBodyPart.Z,
+				Radius: nBodyPart.Radius,
+			}
+			for _, nItem := range nBodyPart.Items {
+				xBodyPart.Items = append(xBodyPart.Items, x.GeometryItem(nItem))
+			}
+			xType.BodyParts = append(xType.BodyParts, xBodyPart)
+		}
+		xGeometry.Types = append(xGeometry.Types, xType)
+	}
+
+	geometry.fromXml(&xGeometry)
+	return
+}
+
+func (geometry *AvatarGeometry) fromXml(xGeometry *x.AvatarGeometry) {
+	*geometry = AvatarGeometry{
+		Camera: Vector3(xGeometry.Camera),
+		Types:  map[string]*AvatarGeometryType{},
+	}
+	for _, xType := range xGeometry.Types {
+		geometryType := &AvatarGeometryType{Id: xType.Id}
+		for _, xBodyPart := range xType.BodyParts {
+			bodyPart := &AvatarGeometryBodyPart{
+				Id:       xBodyPart.Id,
+				Location: Vector3{xBodyPart.X, xBodyPart.Y, xBodyPart.Z},
+				Radius:   xBodyPart.Radius,
+			}
+			for _, xItem := range xBodyPart.Items {
+				bodyPart.Items = append(bodyPart.Items, &AvatarGeometryItem{
+					Type:     nx.FigurePartType(xItem.Id),
+					Location: Vector3{xItem.X, xItem.Y, xItem.Z},
+					Radius:   xItem.Radius,
+					Normal:   Vector3{xItem.Nx, xItem.Ny, xItem.Nz},
+					Double:   xItem.Double,
+				})
+			}
+			geometryType.BodyParts = append(geometryType.BodyParts, bodyPart)
+		}
+		geometry.Types[geometryType.Id] = geometryType
+	}
+}
+
+// PartOrder computes the order in which figure parts are drawn, from back to front,
+// for the specified geometry type, body direction and head direction.
+// Body parts are ordered by their distance from the camera after rotating them to the body direction,
+// and the figure parts within each body part are ordered in the same way,
+// using the head direction for parts of the head.
+// Returns nil if the geometry type does not exist.
+func (geometry *AvatarGeometry) PartOrder(geometryType string, bodyDir, headDir int) (order []nx.FigurePartType) {
+	t, ok := geometry.Types[geometryType]
+	if !ok {
+		return nil
+	}
+
+	type sortable[T any] struct {
+		distance float64
+		value    T
+	}
+	byDistance := func(a, b sortable[*AvatarGeometryBodyPart]) int {
+		// Farthest from the camera is drawn first.
+		return cmp.Compare(b.distance, a.distance)
+	}
+
+	bodyParts := make([]sortable[*AvatarGeometryBodyPart], 0, len(t.BodyParts))
+	for _, bodyPart := range t.BodyParts {
+		loc := rotateY(bodyPart.Location, bodyDir)
+		bodyParts = append(bodyParts, sortable[*AvatarGeometryBodyPart]{
+			distance: geometry.distance(loc, bodyPart.Radius),
+			value:    bodyPart,
+		})
+	}
+	slices.SortStableFunc(bodyParts, byDistance)
+
+	for _, bodyPart := range bodyParts {
+		dir := bodyDir
+		if bodyPart.value.Id == HeadBodyPart {
+			dir = headDir
+		}
+		items := make([]sortable[*AvatarGeometryItem], 0, len(bodyPart.value.Items))
+		for _, item := range bodyPart.value.Items {
+			loc := rotateY(item.Location, dir)
+			items = append(items, sortable[*AvatarGeometryItem]{
+				distance: geometry.distance(loc, item.Radius),
+				value:    item,
+			})
+		}
+		slices.SortStableFunc(items, func(a, b sortable[*AvatarGeometryItem]) int {
+			return cmp.Compare(b.distance, a.distance)
+		})
+		for _, item := range items {
+			order = append(order, item.value.Type)
+		}
+	}
+	return
+}
+
+// distance computes the distance between the camera and the nearest edge of a sphere along the Z axis.
+func (geometry *AvatarGeometry) distance(loc Vector3, radius float64) float64 {
+	dz := geometry.Camera.Z - loc.Z
+	return min(math.Abs(dz-radius), math.Abs(dz+radius))
+}
+
+// rotateY rotates a point around the Y axis by 45 degrees for each direction.
+func rotateY(v Vector3, dir int) Vector3 {
+	angle := float64(dir) * math.Pi / 4
+	sin, cos := math.Sin(angle), math.Cos(angle)
+	return Vector3{
+		X: v.X*cos - v.Z*sin,
+		Y: v.Y,
+		Z: v.X*sin + v.Z*cos,
+	}
+}
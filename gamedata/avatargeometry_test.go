@@ -0,0 +1,42 @@
+package gamedata
+
+import (
+	"slices"
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+func TestAvatarGeometryPartOrder(t *testing.T) {
+	data := `<geometry>
+		<camera><x>0</x><y>0</y><z>10</z></camera>
+		<type id="vertical">
+			<bodypart id="torso" x="0" y="0" z="0" radius="0.1">
+				<item id="bd" x="0" y="0" z="0" radius="0.01"/>
+				<item id="ch" x="0" y="0" z="0" radius="0.02"/>
+			</bodypart>
+			<bodypart id="leftarm" x="-1" y="0" z="0" radius="0.1">
+				<item id="lh" x="0" y="0" z="0" radius="0.01"/>
+			</bodypart>
+			<bodypart id="rightarm" x="1" y="0" z="0" radius="0.1">
+				<item id="rh" x="0" y="0" z="0" radius="0.01"/>
+			</bodypart>
+		</type>
+	</geometry>`
+
+	var geometry AvatarGeometry
+	err := geometry.UnmarshalBytes([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Facing direction 2, the left arm is rotated away from the camera and the right arm towards it.
+	order := geometry.PartOrder("vertical", 2, 2)
+	expected := []nx.FigurePartType{nx.LeftHand, nx.Body, nx.Chest, nx.RightHand}
+	if !slices.Equal(order, expected) {
+		t.Fatalf("part order is %v (expected %v)", order, expected)
+	}
+	if geometry.PartOrder("swim", 2, 2) != nil {
+		t.Fatal("expected nil part order for unknown geometry type")
+	}
+}
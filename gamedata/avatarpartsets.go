@@ -0,0 +1,66 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+
+	"xabbo.io/nx"
+	n "xabbo.io/nx/raw/nitro"
+	x "xabbo.io/nx/raw/xml"
+)
+
+// AvatarPartSets maps the figure part types affected by each active part set by ID,
+// as defined in HabboAvatarPartSets. An action's active part set selects the figure parts it affects.
+type AvatarPartSets map[string][]nx.FigurePartType
+
+// Unmarshals a HabboAvatarPartSets XML document as raw bytes into an AvatarPartSets.
+func (partSets *AvatarPartSets) UnmarshalBytes(data []byte) (err error) {
+	var xPartSets x.AvatarPartSets
+	err = xml.Unmarshal(data, &xPartSets)
+	if err != nil {
+		return
+	}
+	partSets.fromXml(&xPartSets)
+	return
+}
+
+// Unmarshals a Nitro HabboAvatarPartSets.json document as raw bytes into an AvatarPartSets.
+func (partSets *AvatarPartSets) UnmarshalNitroBytes(data []byte) (err error) {
+	var nPartSets n.AvatarPartSets
+	err = json.Unmarshal(data, &nPartSets)
+	if err != nil {
+		return
+	}
+
+	var xPartSets x.AvatarPartSets
+	for _, nPartSet := range nPartSets.ActivePartSets {
+		xPartSet := x.ActivePartSet{Id: nPartSet.Id}
+		for _, nPart := range nPartSet.ActiveParts {
+			xPartSet.ActiveParts = append(xPartSet.ActiveParts, x.ActivePart(nPart))
+		}
+		xPartSets.ActivePartSets = append(xPartSets.ActivePartSets, xPartSet)
+	}
+
+	partSets.fromXml(&xPartSets)
+	return
+}
+
+func (partSets *AvatarPartSets) fromXml(xPartSets *x.AvatarPartSets) {
+	*partSets = AvatarPartSets{}
+	for _, xPartSet := range xPartSets.ActivePartSets {
+		partTypes := []nx.FigurePartType{}
+		for _, xPart := range xPartSet.ActiveParts {
+			partTypes = append(partTypes, nx.FigurePartType(xPart.SetType))
+		}
+		(*partSets)[xPartSet.Id] = partTypes
+	}
+}
+
+// Affects reports whether the action affects the specified figure part type.
+// If the action's active part set is not defined, e.g. because the part sets are not loaded,
+// the action affects the entire figure.
+func (partSets AvatarPartSets) Affects(info *AvatarActionInfo, partType nx.FigurePartType) bool {
+	partTypes, ok := partSets[info.ActivePartSet]
+	return !ok || slices.Contains(partTypes, partType)
+}
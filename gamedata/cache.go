@@ -0,0 +1,64 @@
+package gamedata
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// A CachedFile describes a hashed game data file in the cache directory.
+type CachedFile struct {
+	Type    Type
+	Hash    string
+	Path    string
+	ModTime time.Time // The time the file was cached.
+}
+
+// DefaultCacheDir returns the default cache directory,
+// located under `xabbo/nx` within the user's cache directory.
+func DefaultCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = ".cache"
+	}
+	return filepath.Join(cacheDir, "xabbo", "nx")
+}
+
+// CachedFiles returns the cached files of the specified game data type for a host,
+// ordered from the oldest to the most recently cached.
+func CachedFiles(cacheDir, host string, t Type) (files []CachedFile, err error) {
+	dir := filepath.Join(cacheDir, host, string(t))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		var info os.FileInfo
+		info, err = entry.Info()
+		if err != nil {
+			return
+		}
+		if info.Size() == 0 {
+			continue
+		}
+		files = append(files, CachedFile{
+			Type:    t,
+			Hash:    entry.Name(),
+			Path:    filepath.Join(dir, entry.Name()),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	slices.SortStableFunc(files, func(a, b CachedFile) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+	return
+}
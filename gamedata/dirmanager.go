@@ -0,0 +1,517 @@
+package gamedata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"b7c.io/swfx"
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx/raw/nitro"
+	"xabbo.io/nx/res"
+)
+
+// A DirLayout defines the locations of game data files and asset libraries within a directory.
+//
+// The format of each game data file is determined by its extension.
+// Flash game data files use the .xml and .txt extensions, while Nitro game data files use .json.
+// Furni and product data may also use the JSON format served by the hotel in either layout.
+// If a game data file does not exist, a file with the same name and another supported extension is loaded instead,
+// e.g. furnidata.json in place of furnidata.xml.
+// Asset libraries are loaded from a .nitro archive if one exists, otherwise from a .swf file.
+type DirLayout struct {
+	Files     map[Type]string // Maps game data types to file paths relative to the directory.
+	FurniDir  string          // The directory containing furni libraries, relative to the directory.
+	FigureDir string          // The directory containing figure part libraries, relative to the directory.
+	EffectDir string          // The directory containing effect libraries, relative to the directory.
+	// The room content library file, relative to the directory.
+	// A .nitro archive is loaded if the file has a .nitro extension, otherwise a SWF.
+	RoomContent string
+}
+
+// FlashLayout is the directory layout of a Flash client's game data and libraries.
+var FlashLayout = DirLayout{
+	Files: map[Type]string{
+		GameDataFurni:     "furnidata.xml",
+		GameDataProduct:   "productdata.txt",
+		GameDataTexts:     "external_texts.txt",
+		GameDataVariables: "external_variables.txt",
+		GameDataFigure:    "figuredata.xml",
+		GameDataFigureMap: "figuremap.xml",
+		GameDataAvatar:    habboAvatarActionsFilename,
+		GameDataPartSets:  habboAvatarPartSetsFilename,
+		GameDataGeometry:  habboAvatarGeometryFilename,
+		GameDataEffectMap: effectMapFilename,
+	},
+	FurniDir:    "furni",
+	FigureDir:   "figure",
+	EffectDir:   "effect",
+	RoomContent: "HabboRoomContent.swf",
+}
+
+// NitroLayout is the directory layout of a Nitro client's asset base.
+var NitroLayout = DirLayout{
+	Files: map[Type]string{
+		GameDataFurni:     "gamedata/FurnitureData.json",
+		GameDataProduct:   "gamedata/ProductData.json",
+		GameDataTexts:     "gamedata/ExternalTexts.json",
+		GameDataVariables: "renderer-config.json",
+		GameDataFigure:    "gamedata/FigureData.json",
+		GameDataFigureMap: "gamedata/FigureMap.json",
+		GameDataAvatar:    "gamedata/HabboAvatarActions.json",
+		GameDataPartSets:  "gamedata/HabboAvatarPartSets.json",
+		GameDataGeometry:  "gamedata/HabboAvatarGeometry.json",
+		GameDataEffectMap: "gamedata/EffectMap.json",
+	},
+	FurniDir:    "bundled/furniture",
+	FigureDir:   "bundled/figure",
+	EffectDir:   "bundled/effect",
+	RoomContent: "bundled/generic/room.nitro",
+}
+
+type dirGameDataManager struct {
+	dir    string
+	layout DirLayout
+
+	figure        *FigureData
+	figureMap     *FigureMap
+	avatarActions AvatarActions
+	partSets      AvatarPartSets
+	geometry      *AvatarGeometry
+	effectMap     EffectMap
+	furni         FurniData
+	products      ProductData
+	texts         ExternalTexts
+	variables     ExternalVariables
+	assets        res.LibraryManager
+}
+
+// NewDirManager creates a game data manager that loads game data and asset libraries
+// from a local directory with the specified layout, instead of fetching them from a hotel.
+func NewDirManager(dir string, layout DirLayout) Manager {
+	return &dirGameDataManager{
+		dir:    dir,
+		layout: layout,
+		assets: res.NewManager(),
+	}
+}
+
+func (mgr *dirGameDataManager) Library(name string) res.AssetLibrary {
+	return mgr.assets.Library(name)
+}
+
+func (mgr *dirGameDataManager) Libraries() []string {
+	return mgr.assets.Libraries()
+}
+
+func (mgr *dirGameDataManager) LibraryExists(name string) bool {
+	return mgr.assets.LibraryExists(name)
+}
+
+func (mgr *dirGameDataManager) AddLibrary(lib res.AssetLibrary) bool {
+	return mgr.assets.AddLibrary(lib)
+}
+
+func (mgr *dirGameDataManager) Figure() *FigureData {
+	return mgr.figure
+}
+
+func (mgr *dirGameDataManager) FigureMap() *FigureMap {
+	return mgr.figureMap
+}
+
+func (mgr *dirGameDataManager) AvatarActions() AvatarActions {
+	return mgr.avatarActions
+}
+
+func (mgr *dirGameDataManager) AvatarPartSets() AvatarPartSets {
+	return mgr.partSets
+}
+
+func (mgr *dirGameDataManager) AvatarGeometry() *AvatarGeometry {
+	return mgr.geometry
+}
+
+func (mgr *dirGameDataManager) EffectMap() EffectMap {
+	return mgr.effectMap
+}
+
+func (mgr *dirGameDataManager) Furni() FurniData {
+	return mgr.furni
+}
+
+func (mgr *dirGameDataManager) Products() ProductData {
+	return mgr.products
+}
+
+func (mgr *dirGameDataManager) Texts() ExternalTexts {
+	return mgr.texts
+}
+
+func (mgr *dirGameDataManager) Variables() ExternalVariables {
+	return mgr.variables
+}
+
+func (mgr *dirGameDataManager) Loaded(types ...Type) bool {
+	for _, t := range types {
+		var loaded bool
+		switch t {
+		case GameDataFurni:
+			loaded = mgr.furni != nil
+		case GameDataFigure:
+			loaded = mgr.figure != nil
+		case GameDataProduct:
+			loaded = mgr.products != nil
+		case GameDataTexts:
+			loaded = mgr.texts != nil
+		case GameDataVariables:
+			loaded = mgr.variables != nil
+		case GameDataFigureMap:
+			loaded = mgr.figureMap != nil
+		case GameDataAvatar:
+			loaded = mgr.avatarActions != nil
+		case GameDataPartSets:
+			loaded = mgr.partSets != nil
+		case GameDataGeometry:
+			loaded = mgr.geometry != nil
+		case GameDataEffectMap:
+			loaded = mgr.effectMap != nil
+		default:
+			panic(fmt.Errorf("unknown game data type %q", t))
+		}
+		if !loaded {
+			return false
+		}
+	}
+	return true
+}
+
+// Load loads the specified game data types from the directory.
+// If none are specified, all game data files in the layout that exist are loaded.
+func (mgr *dirGameDataManager) Load(types ...Type) (err error) {
+	loadAll := len(types) == 0
+	if loadAll {
+		types = maps.Keys(mgr.layout.Files)
+		slices.Sort(types)
+	}
+
+	for _, t := range types {
+		fileName, ok := mgr.layout.Files[t]
+		if !ok {
+			return fmt.Errorf("game data type %q is not defined in the directory layout", t)
+		}
+
+		var filePath string
+		var data []byte
+		filePath, data, err = mgr.readFile(fileName)
+		if err != nil {
+			if loadAll && errors.Is(err, os.ErrNotExist) {
+				err = nil
+				continue
+			}
+			return
+		}
+
+		err = mgr.decode(t, filePath, data)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", filePath, err)
+		}
+	}
+
+	return
+}
+
+// gameDataExts contains the extensions of supported game data files.
+var gameDataExts = []string{".xml", ".txt", ".json"}
+
+// readFile reads a game data file from the directory.
+// If the file does not exist, a file with the same name and another supported extension is read instead.
+func (mgr *dirGameDataManager) readFile(fileName string) (filePath string, data []byte, err error) {
+	filePath = filepath.Join(mgr.dir, fileName)
+	data, err = os.ReadFile(filePath)
+	if !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	ext := filepath.Ext(filePath)
+	for _, altExt := range gameDataExts {
+		if altExt == ext {
+			continue
+		}
+		altPath := strings.TrimSuffix(filePath, ext) + altExt
+		if altData, altErr := os.ReadFile(altPath); altErr == nil {
+			return altPath, altData, nil
+		}
+	}
+	return
+}
+
+// decode decodes game data of the specified type, using the format indicated by the file extension.
+func (mgr *dirGameDataManager) decode(t Type, filePath string, data []byte) (err error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+
+	switch {
+	case t == GameDataFurni && ext == ".json":
+		var furni FurniData
+		err = furni.UnmarshalBytes(data)
+		mgr.furni = furni
+	case t == GameDataFurni && ext == ".xml":
+		var furni FurniData
+		err = furni.UnmarshalXmlBytes(data)
+		mgr.furni = furni
+	case t == GameDataProduct && ext == ".json":
+		var products ProductData
+		err = products.UnmarshalBytes(data)
+		mgr.products = products
+	case t == GameDataProduct && ext == ".txt":
+		var products ProductData
+		err = products.UnmarshalTextBytes(data)
+		mgr.products = products
+	case t == GameDataTexts && ext == ".txt":
+		var texts ExternalTexts
+		err = texts.UnmarshalBytes(data)
+		mgr.texts = texts
+	case t == GameDataTexts && ext == ".json":
+		var texts ExternalTexts
+		err = texts.UnmarshalNitroBytes(data)
+		mgr.texts = texts
+	case t == GameDataVariables && ext == ".txt":
+		var variables ExternalVariables
+		err = variables.UnmarshalBytes(data)
+		mgr.variables = variables
+	case t == GameDataVariables && ext == ".json":
+		var variables ExternalVariables
+		err = variables.UnmarshalNitroBytes(data)
+		mgr.variables = variables
+	case t == GameDataFigure && ext == ".xml":
+		var figure FigureData
+		err = figure.UnmarshalBytes(data)
+		mgr.figure = &figure
+	case t == GameDataFigure && ext == ".json":
+		var figure FigureData
+		err = figure.UnmarshalNitroBytes(data)
+		mgr.figure = &figure
+	case t == GameDataFigureMap && ext == ".xml":
+		var figureMap FigureMap
+		err = figureMap.UnmarshalBytes(data)
+		mgr.figureMap = &figureMap
+	case t == GameDataFigureMap && ext == ".json":
+		var figureMap FigureMap
+		err = figureMap.UnmarshalNitroBytes(data)
+		mgr.figureMap = &figureMap
+	case t == GameDataAvatar && ext == ".xml":
+		var avatarActions AvatarActions
+		err = avatarActions.UnmarshalBytes(data)
+		mgr.avatarActions = avatarActions
+	case t == GameDataAvatar && ext == ".json":
+		var avatarActions AvatarActions
+		err = avatarActions.UnmarshalNitroBytes(data)
+		mgr.avatarActions = avatarActions
+	case t == GameDataPartSets && ext == ".xml":
+		var partSets AvatarPartSets
+		err = partSets.UnmarshalBytes(data)
+		mgr.partSets = partSets
+	case t == GameDataPartSets && ext == ".json":
+		var partSets AvatarPartSets
+		err = partSets.UnmarshalNitroBytes(data)
+		mgr.partSets = partSets
+	case t == GameDataGeometry && ext == ".xml":
+		var geometry AvatarGeometry
+		err = geometry.UnmarshalBytes(data)
+		mgr.geometry = &geometry
+	case t == GameDataGeometry && ext == ".json":
+		var geometry AvatarGeometry
+		err = geometry.UnmarshalNitroBytes(data)
+		mgr.geometry = &geometry
+	case t == GameDataEffectMap && ext == ".xml":
+		var effectMap EffectMap
+		err = effectMap.UnmarshalBytes(data)
+		mgr.effectMap = effectMap
+	case t == GameDataEffectMap && ext == ".json":
+		var effectMap EffectMap
+		err = effectMap.UnmarshalNitroBytes(data)
+		mgr.effectMap = effectMap
+	default:
+		err = fmt.Errorf("unsupported file format %q for game data type %q", ext, t)
+	}
+
+	return
+}
+
+// findLibrary finds the path of a library within a library directory, preferring Nitro archives.
+func (mgr *dirGameDataManager) findLibrary(libDir, name string) (filePath string, err error) {
+	for _, ext := range []string{".nitro", ".swf"} {
+		filePath = filepath.Join(mgr.dir, libDir, name+ext)
+		if _, err = os.Stat(filePath); err == nil {
+			return
+		}
+	}
+	err = fmt.Errorf("library %q %w in %s", name, ErrNotFound, filepath.Join(mgr.dir, libDir))
+	return
+}
+
+func (mgr *dirGameDataManager) LoadFurni(libraries ...string) (err error) {
+	for _, identifier := range libraries {
+		libraryName := strings.Split(identifier, "*")[0]
+
+		if mgr.assets.LibraryExists(libraryName) {
+			continue
+		}
+
+		var filePath string
+		filePath, err = mgr.findLibrary(mgr.layout.FurniDir, libraryName)
+		if err != nil {
+			return
+		}
+
+		var lib res.AssetLibrary
+		if strings.HasSuffix(filePath, ".nitro") {
+			var archive nitro.Archive
+			archive, err = readNitroFile(filePath)
+			if err != nil {
+				return
+			}
+			lib, err = res.LoadFurniLibraryNitro(archive)
+		} else {
+			var swf *swfx.Swf
+			swf, err = readSwfFile(filePath)
+			if err != nil {
+				return
+			}
+			lib, err = res.LoadFurniLibrarySwf(swf)
+		}
+		if err != nil {
+			return
+		}
+
+		mgr.assets.AddLibrary(lib)
+	}
+
+	return
+}
+
+func (mgr *dirGameDataManager) LoadFigureParts(libraries ...string) (err error) {
+	for _, libraryName := range libraries {
+		if mgr.assets.LibraryExists(libraryName) {
+			continue
+		}
+
+		var filePath string
+		filePath, err = mgr.findLibrary(mgr.layout.FigureDir, libraryName)
+		if err != nil {
+			return
+		}
+
+		var lib res.AssetLibrary
+		if strings.HasSuffix(filePath, ".nitro") {
+			var archive nitro.Archive
+			archive, err = readNitroFile(filePath)
+			if err != nil {
+				return
+			}
+			lib, err = res.LoadFigureLibraryNitro(archive)
+		} else {
+			var swf *swfx.Swf
+			swf, err = readSwfFile(filePath)
+			if err != nil {
+				return
+			}
+			lib, err = res.LoadFigureLibrarySwf(swf)
+		}
+		if err != nil {
+			return
+		}
+		mgr.assets.AddLibrary(lib)
+	}
+
+	return
+}
+
+func (mgr *dirGameDataManager) LoadEffects(libraries ...string) (err error) {
+	for _, libraryName := range libraries {
+		if mgr.assets.LibraryExists(libraryName) {
+			continue
+		}
+
+		var filePath string
+		filePath, err = mgr.findLibrary(mgr.layout.EffectDir, libraryName)
+		if err != nil {
+			return
+		}
+
+		var lib res.EffectLibrary
+		if strings.HasSuffix(filePath, ".nitro") {
+			var archive nitro.Archive
+			archive, err = readNitroFile(filePath)
+			if err != nil {
+				return
+			}
+			lib, err = res.LoadEffectLibraryNitro(archive)
+		} else {
+			var swf *swfx.Swf
+			swf, err = readSwfFile(filePath)
+			if err != nil {
+				return
+			}
+			lib, err = res.LoadEffectLibrarySwf(swf)
+		}
+		if err != nil {
+			return
+		}
+		mgr.assets.AddLibrary(lib)
+	}
+
+	return
+}
+
+func (mgr *dirGameDataManager) LoadRoomContent() (err error) {
+	if mgr.assets.LibraryExists(res.RoomContentLibrary) {
+		return
+	}
+	if mgr.layout.RoomContent == "" {
+		return errors.New("room content library is not defined in the directory layout")
+	}
+
+	filePath := filepath.Join(mgr.dir, mgr.layout.RoomContent)
+	var lib res.RoomLibrary
+	if strings.HasSuffix(filePath, ".nitro") {
+		var archive nitro.Archive
+		archive, err = readNitroFile(filePath)
+		if err != nil {
+			return
+		}
+		lib, err = res.LoadRoomLibraryNitro(archive)
+	} else {
+		var swf *swfx.Swf
+		swf, err = readSwfFile(filePath)
+		if err != nil {
+			return
+		}
+		lib, err = res.LoadRoomLibrarySwf(swf)
+	}
+	if err != nil {
+		return
+	}
+	mgr.assets.AddLibrary(lib)
+	return
+}
+
+func readSwfFile(filePath string) (swf *swfx.Swf, err error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	return swfx.ReadSwf(bytes.NewReader(data))
+}
+
+func readNitroFile(filePath string) (archive nitro.Archive, err error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+	return nitro.NewReader(bytes.NewReader(data)).ReadArchive()
+}
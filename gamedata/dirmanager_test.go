@@ -0,0 +1,107 @@
+package gamedata
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+func TestDirManager(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "furnidata.json"), []byte(testFurniData), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "external_texts.txt"), []byte("furni_chair_name=Chair\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewDirManager(dir, FlashLayout)
+	if err := mgr.Load(); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if !mgr.Loaded(GameDataFurni, GameDataTexts) {
+		t.Fatal("furni data and texts should be loaded")
+	}
+	if mgr.Loaded(GameDataFigure) {
+		t.Fatal("figure data should not be loaded")
+	}
+	if fi, ok := mgr.Furni()["chair"]; !ok || fi.Name != "Chair" {
+		t.Fatalf("unexpected furni info: %+v", fi)
+	}
+	if text := mgr.Texts()["furni_chair_name"]; text != "Chair" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+
+	if err := mgr.Load(GameDataFigure); err == nil {
+		t.Fatal("loading missing figure data should fail")
+	}
+	if err := mgr.LoadFurni("chair"); err == nil {
+		t.Fatal("loading missing furni library should fail")
+	}
+}
+
+const testFurniDataXml = `<?xml version="1.0" encoding="UTF-8"?>
+<furnidata>
+	<roomitemtypes>
+		<furnitype id="13" classname="shelves_norja">
+			<revision>61856</revision>
+			<defaultdir>0</defaultdir>
+			<xdim>1</xdim>
+			<ydim>1</ydim>
+			<partcolors><color>#ffffff</color><color>#F7EBBC</color></partcolors>
+			<name>Beige Bookcase</name>
+			<description>For nic naks and books.</description>
+			<adurl/>
+			<offerid>5</offerid>
+			<buyout>1</buyout>
+			<bc>0</bc>
+			<customparams/>
+			<specialtype>1</specialtype>
+			<canstandon>0</canstandon>
+			<cansiton>0</cansiton>
+			<canlayon>0</canlayon>
+			<furniline>iced</furniline>
+		</furnitype>
+	</roomitemtypes>
+	<wallitemtypes>
+		<furnitype id="4001" classname="poster">
+			<revision>45508</revision>
+			<name>Poster</name>
+		</furnitype>
+	</wallitemtypes>
+</furnidata>`
+
+func TestDirManagerFlashFormats(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "furnidata.xml"), []byte(testFurniDataXml), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "productdata.txt"), []byte(
+		`[["shelves_norja","Beige Bookcase","For nic naks and books."],["poster","Poster",""]]`+"\n"+
+			`[["a0 pet0","Dog","A dog."]]`+"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewDirManager(dir, FlashLayout)
+	if err := mgr.Load(GameDataFurni, GameDataProduct); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+
+	shelves, ok := mgr.Furni()["shelves_norja"]
+	if !ok || shelves.Kind != 13 || shelves.Type != nx.ItemFloor || shelves.Revision != 61856 ||
+		!shelves.Buyout || shelves.Line != "iced" || len(shelves.PartColors) != 2 {
+		t.Fatalf("unexpected furni info: %+v", shelves)
+	}
+	if poster, ok := mgr.Furni()["poster"]; !ok || poster.Type != nx.ItemWall {
+		t.Fatalf("unexpected furni info: %+v", poster)
+	}
+	if len(mgr.Products()) != 3 || mgr.Products()["a0 pet0"].Name != "Dog" {
+		t.Fatalf("unexpected product data: %v", mgr.Products())
+	}
+}
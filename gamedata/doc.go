@@ -2,11 +2,14 @@
 //   - Figure Data
 //   - Figure Map
 //   - Avatar Actions
+//   - Avatar Geometry
+//   - Effect Map
 //   - Furni Data
 //   - Product Data
 //   - External Texts
 //   - External Variables
 //
 // It also provides a web-based gamedata.Manager implementation
-// for dynamically fetching and caching assets.
+// for dynamically fetching and caching assets, and a directory-based
+// implementation for loading game data and assets from a local Flash or Nitro layout.
 package gamedata
@@ -0,0 +1,59 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	n "xabbo.io/nx/raw/nitro"
+	x "xabbo.io/nx/raw/xml"
+)
+
+// An EffectMap maps avatar effects by ID.
+type EffectMap map[int]*EffectInfo
+
+// An EffectInfo defines the library containing an avatar effect.
+type EffectInfo struct {
+	Id       int
+	Library  string // The name of the effect library.
+	Type     string // The type of effect, e.g. `fx` or `dance`.
+	Revision int
+}
+
+// Unmarshals an XML document as raw bytes into an EffectMap.
+func (em *EffectMap) UnmarshalBytes(data []byte) (err error) {
+	var xEffectMap x.EffectMap
+	err = xml.Unmarshal(data, &xEffectMap)
+	if err != nil {
+		return
+	}
+	em.fromXml(&xEffectMap)
+	return
+}
+
+// Unmarshals a Nitro EffectMap.json document as raw bytes into an EffectMap.
+func (em *EffectMap) UnmarshalNitroBytes(data []byte) (err error) {
+	var nEffectMap n.EffectMap
+	err = json.Unmarshal(data, &nEffectMap)
+	if err != nil {
+		return
+	}
+
+	var xEffectMap x.EffectMap
+	for _, nEffect := range nEffectMap.Effects {
+		xEffectMap.Effects = append(xEffectMap.Effects, x.EffectMapEffect(nEffect))
+	}
+	em.fromXml(&xEffectMap)
+	return
+}
+
+func (em *EffectMap) fromXml(xEffectMap *x.EffectMap) {
+	*em = EffectMap{}
+	for _, xEffect := range xEffectMap.Effects {
+		(*em)[xEffect.Id] = &EffectInfo{
+			Id:       xEffect.Id,
+			Library:  xEffect.Lib,
+			Type:     xEffect.Type,
+			Revision: xEffect.Revision,
+		}
+	}
+}
@@ -3,9 +3,12 @@ package gamedata
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"path"
 	"strings"
+
+	n "xabbo.io/nx/raw/nitro"
 )
 
 // ExternalVariables defines dynamic variables loaded by the client.
@@ -26,6 +29,64 @@ func (vars *ExternalVariables) UnmarshalBytes(data []byte) (err error) {
 	return
 }
 
+// Unmarshals a Nitro ExternalTexts.json or UITexts.json document as raw bytes into an ExternalTexts.
+func (texts *ExternalTexts) UnmarshalNitroBytes(data []byte) (err error) {
+	var nTexts n.Texts
+	err = json.Unmarshal(data, &nTexts)
+	if err != nil {
+		return
+	}
+	*texts = ExternalTexts(nTexts)
+	return
+}
+
+// Unmarshals a Nitro renderer-config.json document as raw bytes into an ExternalVariables.
+// Placeholders in the form ${key} are replaced with the value of the referenced key.
+func (vars *ExternalVariables) UnmarshalNitroBytes(data []byte) (err error) {
+	var config n.RendererConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return
+	}
+
+	m := config.Strings()
+	for key, value := range m {
+		m[key] = interpolate(m, value, 0)
+	}
+	*vars = ExternalVariables(m)
+	return
+}
+
+// interpolate replaces ${key} placeholders in s with values from m.
+// Placeholders referencing unknown keys are left as is.
+func interpolate(m map[string]string, s string, depth int) string {
+	// Guard against cyclic references.
+	if depth >= 8 {
+		return s
+	}
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		sb.WriteString(s[:start])
+		if value, ok := m[s[start+2:end]]; ok {
+			sb.WriteString(interpolate(m, value, depth+1))
+		} else {
+			sb.WriteString(s[start : end+1])
+		}
+		s = s[end+1:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
 // Gets the client version from the external variables,
 // or returns an error if the key is not found.
 func (vars *ExternalVariables) ClientVersion() (version string, err error) {
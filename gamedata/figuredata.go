@@ -1,9 +1,11 @@
 package gamedata
 
 import (
+	"encoding/json"
 	"encoding/xml"
 
 	"xabbo.io/nx"
+	n "xabbo.io/nx/raw/nitro"
 	x "xabbo.io/nx/raw/xml"
 )
 
@@ -14,7 +16,37 @@ type FigureData struct {
 	Palettes map[int]FigureColorPaletteMap
 	// SetPalettes maps figure part types to color palette IDs.
 	SetPalettes map[nx.FigurePartType]int
-	Sets        map[nx.FigurePartType]FigurePartSetMap
+	// SetTypes maps figure part types to information about the set type.
+	SetTypes map[nx.FigurePartType]*FigureSetTypeInfo
+	Sets     map[nx.FigurePartType]FigurePartSetMap
+}
+
+// A FigureSetTypeInfo contains information about a figure part set type.
+type FigureSetTypeInfo struct {
+	Type      nx.FigurePartType
+	PaletteId int
+	// Whether the set type is mandatory for each gender, for non-club (0) and club (1) members.
+	MandatoryM0 bool
+	MandatoryF0 bool
+	MandatoryM1 bool
+	MandatoryF1 bool
+}
+
+// Mandatory returns whether the set type must be worn by the specified gender and club level.
+// If the gender is unisex, the set type is mandatory only if it is mandatory for both genders.
+func (info *FigureSetTypeInfo) Mandatory(gender nx.Gender, club int) bool {
+	male, female := info.MandatoryM0, info.MandatoryF0
+	if club > 0 {
+		male, female = info.MandatoryM1, info.MandatoryF1
+	}
+	switch gender {
+	case nx.Male:
+		return male
+	case nx.Female:
+		return female
+	default:
+		return male && female
+	}
 }
 
 // A FigureColorPaletteMap maps FigurePartColorInfo by ID.
@@ -60,9 +92,71 @@ func (fd *FigureData) UnmarshalBytes(data []byte) (err error) {
 		return
 	}
 
+	fd.fromXml(xFigureData)
+	return
+}
+
+// Unmarshals a Nitro FigureData.json document as raw bytes into a FigureData.
+func (fd *FigureData) UnmarshalNitroBytes(data []byte) (err error) {
+	var nFigureData n.FigureData
+	err = json.Unmarshal(data, &nFigureData)
+	if err != nil {
+		return
+	}
+
+	xFigureData := &x.FigureData{}
+	for _, nPalette := range nFigureData.Palettes {
+		palette := x.FigurePalette{Id: nPalette.Id}
+		for _, c := range nPalette.Colors {
+			palette.Colors = append(palette.Colors, x.FigureColor{
+				Id:         c.Id,
+				Index:      c.Index,
+				Club:       c.Club,
+				Selectable: c.Selectable,
+				Value:      c.HexCode,
+			})
+		}
+		xFigureData.Palettes = append(xFigureData.Palettes, palette)
+	}
+	for _, nSetType := range nFigureData.SetTypes {
+		setType := x.FigurePartSets{
+			Type:      nSetType.Type,
+			PaletteId: nSetType.PaletteId,
+			MandM0:    nSetType.MandM0,
+			MandF0:    nSetType.MandF0,
+			MandM1:    nSetType.MandM1,
+			MandF1:    nSetType.MandF1,
+		}
+		for _, nSet := range nSetType.Sets {
+			set := x.FigurePartSet{
+				Id:            nSet.Id,
+				Gender:        nSet.Gender,
+				Club:          nSet.Club,
+				Colorable:     nSet.Colorable,
+				Selectable:    nSet.Selectable,
+				Preselectable: nSet.Preselectable,
+				Sellable:      nSet.Sellable,
+			}
+			for _, p := range nSet.Parts {
+				set.Parts = append(set.Parts, x.FigurePart(p))
+			}
+			for _, layer := range nSet.HiddenLayers {
+				set.HiddenLayers = append(set.HiddenLayers, x.FigureLayer(layer))
+			}
+			setType.Sets = append(setType.Sets, set)
+		}
+		xFigureData.Sets = append(xFigureData.Sets, setType)
+	}
+
+	fd.fromXml(xFigureData)
+	return
+}
+
+func (fd *FigureData) fromXml(xFigureData *x.FigureData) {
 	*fd = FigureData{}
 	fd.Palettes = map[int]FigureColorPaletteMap{}
 	fd.SetPalettes = map[nx.FigurePartType]int{}
+	fd.SetTypes = map[nx.FigurePartType]*FigureSetTypeInfo{}
 	fd.Sets = map[nx.FigurePartType]FigurePartSetMap{}
 
 	for _, p := range xFigureData.Palettes {
@@ -80,7 +174,14 @@ func (fd *FigureData) UnmarshalBytes(data []byte) (err error) {
 		setMap := FigurePartSetMap{}
 		for i := range xSetType.Sets {
 			xSet := &xSetType.Sets[i]
-			partSet := FigurePartSetInfo{}
+			partSet := FigurePartSetInfo{
+				Id:            xSet.Id,
+				Gender:        xSet.Gender,
+				Club:          xSet.Club,
+				Colorable:     xSet.Colorable,
+				Selectable:    xSet.Selectable,
+				Preselectable: xSet.Preselectable,
+			}
 			for i := range xSet.Parts {
 				xPart := &xSet.Parts[i]
 				part := FigurePartInfo{
@@ -102,7 +203,13 @@ func (fd *FigureData) UnmarshalBytes(data []byte) (err error) {
 
 		fd.Sets[partSetType] = setMap
 		fd.SetPalettes[partSetType] = xSetType.PaletteId
+		fd.SetTypes[partSetType] = &FigureSetTypeInfo{
+			Type:        partSetType,
+			PaletteId:   xSetType.PaletteId,
+			MandatoryM0: xSetType.MandM0,
+			MandatoryF0: xSetType.MandF0,
+			MandatoryM1: xSetType.MandM1,
+			MandatoryF1: xSetType.MandF1,
+		}
 	}
-
-	return
 }
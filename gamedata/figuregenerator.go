@@ -0,0 +1,213 @@
+package gamedata
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx"
+)
+
+// A ColorHarmony defines how colors are chosen in relation to each other when generating a figure.
+type ColorHarmony string
+
+const (
+	HarmonyNone          ColorHarmony = ""              // Colors are chosen independently.
+	HarmonyMonochrome    ColorHarmony = "monochrome"    // Colors share a single hue.
+	HarmonyAnalogous     ColorHarmony = "analogous"     // Colors have adjacent hues.
+	HarmonyComplementary ColorHarmony = "complementary" // Colors have a hue or its opposite.
+)
+
+// ColorHarmonies contains all color harmonies.
+var ColorHarmonies = []ColorHarmony{
+	HarmonyNone, HarmonyMonochrome, HarmonyAnalogous, HarmonyComplementary,
+}
+
+// RandomFigureOptions defines the constraints used when generating a random figure.
+type RandomFigureOptions struct {
+	// The gender of the figure. If unisex, a gender is chosen at random.
+	Gender nx.Gender
+	// The maximum club level of part sets and colors.
+	Club int
+	// Whether to only use selectable part sets and colors.
+	SelectableOnly bool
+	// Set types that must be included in addition to the mandatory set types.
+	Include []nx.FigurePartType
+	// Set types that must not be included. Mandatory set types cannot be excluded.
+	Exclude []nx.FigurePartType
+	// The probability of including each optional set type.
+	// If zero, 0.5 is used. If negative, optional set types are never included.
+	Chance float64
+	// A fixed list of color IDs to choose from. Colors are chosen from the full palette
+	// if none of these exist in a set type's palette.
+	Colors []int
+	// The color harmony used to choose colors.
+	Harmony ColorHarmony
+}
+
+// RandomFigure generates a random valid figure using the specified random source and constraints.
+// The same source seed and options always produce the same figure.
+func (fd *FigureData) RandomFigure(rng *rand.Rand, opts RandomFigureOptions) (fig nx.Figure, err error) {
+	if !slices.Contains(ColorHarmonies, opts.Harmony) {
+		err = fmt.Errorf("unknown color harmony: %q", opts.Harmony)
+		return
+	}
+	for _, setType := range opts.Include {
+		if _, ok := fd.Sets[setType]; !ok {
+			err = fmt.Errorf("unknown set type: %q", setType)
+			return
+		}
+	}
+
+	fig.Gender = opts.Gender
+	if fig.Gender != nx.Male && fig.Gender != nx.Female {
+		fig.Gender = []nx.Gender{nx.Male, nx.Female}[rng.Intn(2)]
+	}
+
+	chance := opts.Chance
+	if chance == 0 {
+		chance = 0.5
+	}
+	baseHue := rng.Float64() * 360
+
+	setTypes := maps.Keys(fd.Sets)
+	slices.Sort(setTypes)
+	for _, setType := range setTypes {
+		mandatory := false
+		if info, ok := fd.SetTypes[setType]; ok {
+			mandatory = info.Mandatory(fig.Gender, opts.Club)
+		}
+		included := slices.Contains(opts.Include, setType)
+		if !mandatory && !included {
+			if slices.Contains(opts.Exclude, setType) || rng.Float64() >= chance {
+				continue
+			}
+		}
+
+		// Colorable part sets cannot be chosen if no colors are available for the set type.
+		colors := fd.colorCandidates(setType, opts, baseHue)
+		var candidates []*FigurePartSetInfo
+		for _, set := range fd.Sets[setType] {
+			if set.Club <= opts.Club && genderMatches(set.Gender, fig.Gender) &&
+				(set.Selectable || !opts.SelectableOnly) &&
+				(len(colors) > 0 || set.ColorCount() == 0) {
+				candidates = append(candidates, set)
+			}
+		}
+		if len(candidates) == 0 {
+			if mandatory || included {
+				err = fmt.Errorf("no part sets available for set type %q", setType)
+				return
+			}
+			continue
+		}
+		slices.SortFunc(candidates, func(a, b *FigurePartSetInfo) int { return a.Id - b.Id })
+		set := candidates[rng.Intn(len(candidates))]
+
+		item := nx.FigureItem{Type: setType, Id: set.Id}
+		for range set.ColorCount() {
+			item.Colors = append(item.Colors, colors[rng.Intn(len(colors))])
+		}
+		fig.Items = append(fig.Items, item)
+	}
+
+	return
+}
+
+// colorCandidates returns the sorted IDs of the colors that may be chosen for the set type.
+func (fd *FigureData) colorCandidates(setType nx.FigurePartType, opts RandomFigureOptions, baseHue float64) []int {
+	var available, fixed, harmonious []int
+	for _, color := range fd.PaletteFor(setType) {
+		if color.Club > opts.Club || (opts.SelectableOnly && !color.Selectable) {
+			continue
+		}
+		available = append(available, color.Id)
+		if len(opts.Colors) > 0 && !slices.Contains(opts.Colors, color.Id) {
+			continue
+		}
+		fixed = append(fixed, color.Id)
+		if colorInHarmony(color.Value, baseHue, opts.Harmony) {
+			harmonious = append(harmonious, color.Id)
+		}
+	}
+
+	candidates := harmonious
+	if len(candidates) == 0 {
+		candidates = fixed
+	}
+	if len(candidates) == 0 {
+		candidates = available
+	}
+	slices.Sort(candidates)
+	return candidates
+}
+
+// harmonyTolerance is the maximum hue distance in degrees for a color to be in harmony.
+const harmonyTolerance = 30
+
+// colorInHarmony returns whether the hex color value is in harmony with the base hue.
+// Neutral colors are in harmony with any hue.
+func colorInHarmony(value string, baseHue float64, harmony ColorHarmony) bool {
+	if harmony == HarmonyNone {
+		return true
+	}
+	hue, saturation, ok := hexHueSaturation(value)
+	if !ok {
+		return false
+	}
+	if saturation < 0.15 {
+		return true
+	}
+	switch harmony {
+	case HarmonyMonochrome:
+		return hueDistance(hue, baseHue) <= harmonyTolerance/2
+	case HarmonyAnalogous:
+		return hueDistance(hue, baseHue) <= harmonyTolerance*2
+	case HarmonyComplementary:
+		return hueDistance(hue, baseHue) <= harmonyTolerance ||
+			hueDistance(hue, baseHue+180) <= harmonyTolerance
+	}
+	return false
+}
+
+// hexHueSaturation returns the HSV hue in degrees and saturation of a hex color value.
+func hexHueSaturation(value string) (hue, saturation float64, ok bool) {
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if err != nil || len(value) != 6 {
+		return
+	}
+	r := float64(rgb>>16&0xff) / 255
+	g := float64(rgb>>8&0xff) / 255
+	b := float64(rgb&0xff) / 255
+
+	hi, lo := max(r, g, b), min(r, g, b)
+	delta := hi - lo
+	if hi > 0 {
+		saturation = delta / hi
+	}
+	switch {
+	case delta == 0:
+		hue = 0
+	case hi == r:
+		hue = 60 * math.Mod((g-b)/delta, 6)
+	case hi == g:
+		hue = 60 * ((b-r)/delta + 2)
+	default:
+		hue = 60 * ((r-g)/delta + 4)
+	}
+	if hue < 0 {
+		hue += 360
+	}
+	ok = true
+	return
+}
+
+// hueDistance returns the angular distance between two hues in degrees.
+func hueDistance(a, b float64) float64 {
+	d := math.Abs(math.Mod(a-b, 360))
+	return min(d, 360-d)
+}
@@ -0,0 +1,66 @@
+package gamedata
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+func TestRandomFigure(t *testing.T) {
+	var fd FigureData
+	err := fd.UnmarshalBytes([]byte(testFigureData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := RandomFigureOptions{
+		Gender:         nx.Female,
+		SelectableOnly: true,
+		Include:        []nx.FigurePartType{nx.Chest},
+	}
+	for seed := range int64(20) {
+		fig, err := fd.RandomFigure(rand.New(rand.NewSource(seed)), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issues := fd.ValidateFigure(fig, 0); len(issues) > 0 {
+			t.Fatalf("seed %d: generated invalid figure %s: %v", seed, fig.String(), issues)
+		}
+		if fig.String() != "ch-210-1-1.hd-600-1" {
+			t.Fatalf("seed %d: generated unexpected figure %s", seed, fig.String())
+		}
+	}
+
+	opts.Include = []nx.FigurePartType{nx.Hair}
+	if _, err := fd.RandomFigure(rand.New(rand.NewSource(0)), opts); err == nil {
+		t.Fatal("expected error for unknown included set type")
+	}
+}
+
+func TestRandomFigureNoColors(t *testing.T) {
+	var fd FigureData
+	err := fd.UnmarshalBytes([]byte(strings.Replace(testFigureData, `<color id="1" index="1" club="0"`, `<color id="1" index="1" club="2"`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the non-colorable chest set can be chosen when no colors are available.
+	opts := RandomFigureOptions{Gender: nx.Female, Club: 1, Include: []nx.FigurePartType{nx.Chest}}
+	_, err = fd.RandomFigure(rand.New(rand.NewSource(0)), opts)
+	if err == nil || !strings.Contains(err.Error(), `"hd"`) {
+		t.Fatalf("expected error for colorable mandatory set type without colors, got %v", err)
+	}
+
+	opts.Club = 2
+	fig, err := fd.RandomFigure(rand.New(rand.NewSource(0)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range fig.Items {
+		if item.Type == nx.Head && len(item.Colors) != 1 {
+			t.Fatalf("head has %d colors (expected 1)", len(item.Colors))
+		}
+	}
+}
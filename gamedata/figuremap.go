@@ -1,10 +1,12 @@
 package gamedata
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"strconv"
 
 	"xabbo.io/nx"
+	n "xabbo.io/nx/raw/nitro"
 	x "xabbo.io/nx/raw/xml"
 )
 
@@ -29,6 +31,35 @@ func (fm *FigureMap) UnmarshalBytes(data []byte) (err error) {
 		return
 	}
 
+	fm.fromXml(&xfm)
+	return
+}
+
+// Unmarshals a Nitro FigureMap.json document as raw bytes into a FigureMap.
+func (fm *FigureMap) UnmarshalNitroBytes(data []byte) (err error) {
+	var nfm n.FigureMap
+	err = json.Unmarshal(data, &nfm)
+	if err != nil {
+		return
+	}
+
+	var xfm x.FigureMap
+	for _, nlib := range nfm.Libraries {
+		xlib := x.FigureMapLib{
+			Id:       nlib.Id,
+			Revision: nlib.Revision,
+		}
+		for _, npart := range nlib.Parts {
+			xlib.Parts = append(xlib.Parts, x.FigureMapPart(npart))
+		}
+		xfm.Libraries = append(xfm.Libraries, xlib)
+	}
+
+	fm.fromXml(&xfm)
+	return
+}
+
+func (fm *FigureMap) fromXml(xfm *x.FigureMap) {
 	*fm = FigureMap{
 		Libs:  make(map[string]*FigureMapLib),
 		Parts: make(map[nx.FigurePart]*FigureMapLib),
@@ -57,6 +88,4 @@ func (fm *FigureMap) UnmarshalBytes(data []byte) (err error) {
 			}
 		}
 	}
-
-	return
 }
@@ -0,0 +1,203 @@
+package gamedata
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx"
+)
+
+// A FigureChangeKind identifies the kind of change made when normalizing a figure.
+type FigureChangeKind string
+
+const (
+	FigureChangeRemoved FigureChangeKind = "removed" // The item was removed.
+	FigureChangeAdded   FigureChangeKind = "added"   // The item was added.
+	FigureChangeColors  FigureChangeKind = "colors"  // The item's colors were changed.
+)
+
+// A FigureChange describes a change made to a figure item during normalization.
+type FigureChange struct {
+	Kind   FigureChangeKind
+	Type   nx.FigurePartType
+	Before string          // The original item, or empty if the item was added.
+	After  string          // The resulting item, or empty if the item was removed.
+	Reason FigureIssueKind // The issue that caused the change.
+}
+
+// String formats the change as a human-readable description.
+func (change FigureChange) String() string {
+	switch change.Kind {
+	case FigureChangeRemoved:
+		return fmt.Sprintf("removed %s (%s)", change.Before, change.Reason)
+	case FigureChangeAdded:
+		return fmt.Sprintf("added %s (%s)", change.After, change.Reason)
+	default:
+		return fmt.Sprintf("changed %s to %s (%s)", change.Before, change.After, change.Reason)
+	}
+}
+
+// NormalizeFigure returns the closest valid figure to the specified figure, along with the changes made.
+//
+// Duplicate set types, unknown set types and part sets, and part sets not valid for the figure's gender are removed.
+// Colors not in the set type's palette are replaced with the palette's default color,
+// colors are added or removed to match the number of colors used by the part set,
+// and missing mandatory set types are substituted with a default part set.
+// The resulting items are sorted by set type so that equal figures have equal string representations.
+// Non-selectable and club part sets are not changed.
+func (fd *FigureData) NormalizeFigure(fig nx.Figure, club int) (result nx.Figure, changes []FigureChange) {
+	result.Gender = fig.Gender
+	seen := map[nx.FigurePartType]bool{}
+
+	for _, item := range fig.Items {
+		remove := func(reason FigureIssueKind) {
+			changes = append(changes, FigureChange{
+				Kind:   FigureChangeRemoved,
+				Type:   item.Type,
+				Before: item.String(),
+				Reason: reason,
+			})
+		}
+
+		if seen[item.Type] {
+			remove(FigureIssueDuplicateType)
+			continue
+		}
+		set, ok := fd.Sets[item.Type][item.Id]
+		if !ok {
+			if _, ok := fd.Sets[item.Type]; ok {
+				remove(FigureIssueUnknownSet)
+			} else {
+				remove(FigureIssueUnknownType)
+			}
+			continue
+		}
+		if !genderMatches(set.Gender, fig.Gender) {
+			remove(FigureIssueGender)
+			continue
+		}
+		seen[item.Type] = true
+
+		normalized := nx.FigureItem{Type: item.Type, Id: item.Id}
+		normalized.Colors, ok = fd.normalizeColors(item.Type, set, item.Colors, club)
+		if !ok {
+			reason := FigureIssueColorCount
+			if len(item.Colors) == set.ColorCount() {
+				reason = FigureIssueUnknownColor
+			}
+			changes = append(changes, FigureChange{
+				Kind:   FigureChangeColors,
+				Type:   item.Type,
+				Before: item.String(),
+				After:  normalized.String(),
+				Reason: reason,
+			})
+		}
+		result.Items = append(result.Items, normalized)
+	}
+
+	setTypes := maps.Keys(fd.SetTypes)
+	slices.Sort(setTypes)
+	for _, setType := range setTypes {
+		if seen[setType] || !fd.SetTypes[setType].Mandatory(fig.Gender, club) {
+			continue
+		}
+		set := fd.DefaultSet(setType, fig.Gender, club)
+		if set == nil {
+			continue
+		}
+		item := nx.FigureItem{Type: setType, Id: set.Id}
+		item.Colors, _ = fd.normalizeColors(setType, set, nil, club)
+		result.Items = append(result.Items, item)
+		changes = append(changes, FigureChange{
+			Kind:   FigureChangeAdded,
+			Type:   setType,
+			After:  item.String(),
+			Reason: FigureIssueMissingType,
+		})
+	}
+
+	slices.SortFunc(result.Items, func(a, b nx.FigureItem) int {
+		return strings.Compare(string(a.Type), string(b.Type))
+	})
+
+	return
+}
+
+// normalizeColors returns the colors for the part set with unknown colors replaced
+// and the number of colors matched to the part set. ok is false if the colors were changed.
+func (fd *FigureData) normalizeColors(setType nx.FigurePartType, set *FigurePartSetInfo, colors []int, club int) (normalized []int, ok bool) {
+	n := set.ColorCount()
+	if n == 0 {
+		return nil, len(colors) == 0
+	}
+
+	ok = len(colors) == n
+	palette := fd.PaletteFor(setType)
+	defaultColor := DefaultColor(palette, club)
+	for i := range n {
+		color := defaultColor
+		if i < len(colors) {
+			if _, exists := palette[colors[i]]; exists {
+				color = colors[i]
+			} else {
+				ok = false
+			}
+		} else if i > 0 {
+			// Use the primary color for missing colors.
+			color = normalized[0]
+		}
+		normalized = append(normalized, color)
+	}
+	return
+}
+
+// DefaultSet returns the default part set of the specified type for the gender and club level.
+// Selectable part sets are preferred, followed by preselectable part sets, then the lowest ID.
+// Returns nil if no part set is available.
+func (fd *FigureData) DefaultSet(setType nx.FigurePartType, gender nx.Gender, club int) (set *FigurePartSetInfo) {
+	for _, candidate := range fd.Sets[setType] {
+		if candidate.Club > club || !genderMatches(candidate.Gender, gender) {
+			continue
+		}
+		if set == nil || defaultSetLess(candidate, set) {
+			set = candidate
+		}
+	}
+	return
+}
+
+func defaultSetLess(a, b *FigurePartSetInfo) bool {
+	if a.Selectable != b.Selectable {
+		return a.Selectable
+	}
+	if a.Preselectable != b.Preselectable {
+		return a.Preselectable
+	}
+	return a.Id < b.Id
+}
+
+// DefaultColor returns the ID of the default color in the palette for the club level.
+// This is the selectable color with the lowest index available to the club level.
+// Returns 0 if the palette is empty.
+func DefaultColor(palette FigureColorPaletteMap, club int) (colorId int) {
+	var best *FigurePartColorInfo
+	for _, color := range palette {
+		if color.Club > club {
+			continue
+		}
+		if best == nil ||
+			(color.Selectable && !best.Selectable) ||
+			(color.Selectable == best.Selectable &&
+				(color.Index < best.Index || (color.Index == best.Index && color.Id < best.Id))) {
+			best = color
+		}
+	}
+	if best != nil {
+		colorId = best.Id
+	}
+	return
+}
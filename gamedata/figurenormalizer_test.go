@@ -0,0 +1,43 @@
+package gamedata
+
+import (
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+func TestNormalizeFigure(t *testing.T) {
+	var fd FigureData
+	err := fd.UnmarshalBytes([]byte(testFigureData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		figure   string
+		gender   nx.Gender
+		expected string
+		changes  int
+	}{
+		{"hd-180-1.ch-210-1-1", nx.Male, "ch-210-1-1.hd-180-1", 0},
+		{"ch-210-1-1.hd-180-1.hd-600-1", nx.Male, "ch-210-1-1.hd-180-1", 1},
+		{"ch-999-1.hd-180-7", nx.Male, "hd-180-1", 2},
+		{"ch-210-2", nx.Female, "ch-210-2-2.hd-600-1", 2},
+		{"ch-3000-1.hd-600-1", nx.Male, "ch-3000.hd-180-1", 3},
+	}
+
+	for _, test := range tests {
+		var fig nx.Figure
+		err := fig.Parse(test.figure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fig.Gender = test.gender
+
+		result, changes := fd.NormalizeFigure(fig, 0)
+		if result.String() != test.expected || len(changes) != test.changes {
+			t.Errorf("%s (%s): normalized to %s with %d change(s) (expected %s with %d)",
+				test.figure, test.gender, result.String(), len(changes), test.expected, test.changes)
+		}
+	}
+}
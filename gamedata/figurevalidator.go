@@ -0,0 +1,163 @@
+package gamedata
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx"
+)
+
+// A FigureIssueKind identifies the kind of problem found when validating a figure.
+type FigureIssueKind string
+
+const (
+	FigureIssueUnknownType   FigureIssueKind = "unknown-type"   // The set type does not exist.
+	FigureIssueUnknownSet    FigureIssueKind = "unknown-set"    // The part set does not exist.
+	FigureIssueDuplicateType FigureIssueKind = "duplicate-type" // The set type is specified more than once.
+	FigureIssueGender        FigureIssueKind = "gender"         // The part set is not valid for the figure's gender.
+	FigureIssueNotSelectable FigureIssueKind = "not-selectable" // The part set is not selectable.
+	FigureIssueClub          FigureIssueKind = "club"           // The part set or color requires a club membership.
+	FigureIssueUnknownColor  FigureIssueKind = "unknown-color"  // The color is not in the set type's palette.
+	FigureIssueColorCount    FigureIssueKind = "color-count"    // The number of colors does not match the part set.
+	FigureIssueMissingType   FigureIssueKind = "missing-type"   // A mandatory set type is missing.
+)
+
+// A FigureIssue describes a problem with a figure item.
+type FigureIssue struct {
+	Kind  FigureIssueKind
+	Type  nx.FigurePartType // The set type of the figure item.
+	Id    int               // The part set ID of the figure item.
+	Color int               // The color ID, for color issues.
+	Club  int               // The required club level, for club issues.
+	// The expected and actual number of colors, for color count issues.
+	ExpectedColors, ActualColors int
+}
+
+// Error implements error.
+func (issue FigureIssue) Error() string {
+	switch issue.Kind {
+	case FigureIssueUnknownType:
+		return fmt.Sprintf("unknown set type %q", issue.Type)
+	case FigureIssueUnknownSet:
+		return fmt.Sprintf("unknown part set %s-%d", issue.Type, issue.Id)
+	case FigureIssueDuplicateType:
+		return fmt.Sprintf("duplicate set type %q", issue.Type)
+	case FigureIssueGender:
+		return fmt.Sprintf("part set %s-%d is not valid for the figure's gender", issue.Type, issue.Id)
+	case FigureIssueNotSelectable:
+		return fmt.Sprintf("part set %s-%d is not selectable", issue.Type, issue.Id)
+	case FigureIssueClub:
+		if issue.Color != 0 {
+			return fmt.Sprintf("color %d of part set %s-%d requires club level %d",
+				issue.Color, issue.Type, issue.Id, issue.Club)
+		}
+		return fmt.Sprintf("part set %s-%d requires club level %d", issue.Type, issue.Id, issue.Club)
+	case FigureIssueUnknownColor:
+		return fmt.Sprintf("color %d of part set %s-%d is not in the palette", issue.Color, issue.Type, issue.Id)
+	case FigureIssueColorCount:
+		return fmt.Sprintf("part set %s-%d has %d color(s) (expected %d)",
+			issue.Type, issue.Id, issue.ActualColors, issue.ExpectedColors)
+	case FigureIssueMissingType:
+		return fmt.Sprintf("missing mandatory set type %q", issue.Type)
+	default:
+		return fmt.Sprintf("%s: %s-%d", issue.Kind, issue.Type, issue.Id)
+	}
+}
+
+// ColorCount returns the number of colors used by the part set.
+func (set *FigurePartSetInfo) ColorCount() (n int) {
+	if !set.Colorable {
+		return 0
+	}
+	for _, part := range set.Parts {
+		n = max(n, part.ColorIndex)
+	}
+	return max(n, 1)
+}
+
+// ValidateFigure checks the figure against the figure data and returns any issues found.
+// Gender checks are only performed if the figure's gender is male or female.
+// Part sets and colors that require a club level greater than club are reported.
+func (fd *FigureData) ValidateFigure(fig nx.Figure, club int) (issues []FigureIssue) {
+	seen := map[nx.FigurePartType]bool{}
+
+	for _, item := range fig.Items {
+		if seen[item.Type] {
+			issues = append(issues, FigureIssue{Kind: FigureIssueDuplicateType, Type: item.Type, Id: item.Id})
+			continue
+		}
+		seen[item.Type] = true
+
+		sets, ok := fd.Sets[item.Type]
+		if !ok {
+			issues = append(issues, FigureIssue{Kind: FigureIssueUnknownType, Type: item.Type, Id: item.Id})
+			continue
+		}
+		set, ok := sets[item.Id]
+		if !ok {
+			issues = append(issues, FigureIssue{Kind: FigureIssueUnknownSet, Type: item.Type, Id: item.Id})
+			continue
+		}
+
+		issue := FigureIssue{Type: item.Type, Id: item.Id}
+		if !genderMatches(set.Gender, fig.Gender) {
+			issue.Kind = FigureIssueGender
+			issues = append(issues, issue)
+		}
+		if !set.Selectable {
+			issue.Kind = FigureIssueNotSelectable
+			issues = append(issues, issue)
+		}
+		if set.Club > club {
+			issue.Kind = FigureIssueClub
+			issue.Club = set.Club
+			issues = append(issues, issue)
+			issue.Club = 0
+		}
+
+		if expected := set.ColorCount(); set.Colorable && len(item.Colors) != expected {
+			issue.Kind = FigureIssueColorCount
+			issue.ExpectedColors = expected
+			issue.ActualColors = len(item.Colors)
+			issues = append(issues, issue)
+			issue.ExpectedColors, issue.ActualColors = 0, 0
+		}
+
+		palette := fd.PaletteFor(item.Type)
+		for _, colorId := range item.Colors {
+			issue.Color = colorId
+			color, ok := palette[colorId]
+			if !ok {
+				issue.Kind = FigureIssueUnknownColor
+				issues = append(issues, issue)
+			} else if color.Club > club {
+				issue.Kind = FigureIssueClub
+				issue.Club = color.Club
+				issues = append(issues, issue)
+				issue.Club = 0
+			}
+		}
+	}
+
+	setTypes := maps.Keys(fd.SetTypes)
+	slices.Sort(setTypes)
+	for _, setType := range setTypes {
+		if !seen[setType] && fd.SetTypes[setType].Mandatory(fig.Gender, club) {
+			issues = append(issues, FigureIssue{Kind: FigureIssueMissingType, Type: setType})
+		}
+	}
+
+	return
+}
+
+// genderMatches returns whether a part set with the specified gender may be worn by the figure gender.
+func genderMatches(setGender string, figureGender nx.Gender) bool {
+	switch figureGender {
+	case nx.Male, nx.Female:
+		return setGender == "" || nx.Gender(setGender) == nx.Unisex || nx.Gender(setGender) == figureGender
+	default:
+		return true
+	}
+}
@@ -0,0 +1,80 @@
+package gamedata
+
+import (
+	"slices"
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+const testFigureData = `<figuredata>
+	<colors>
+		<palette id="1">
+			<color id="1" index="1" club="0" selectable="1">FFCB98</color>
+			<color id="2" index="2" club="2" selectable="1">F4AC54</color>
+		</palette>
+	</colors>
+	<sets>
+		<settype type="hd" paletteid="1" mand_m_0="1" mand_f_0="1" mand_m_1="1" mand_f_1="1">
+			<set id="180" gender="M" club="0" colorable="1" selectable="1">
+				<part id="1" type="hd" colorable="1" index="0" colorindex="1"/>
+			</set>
+			<set id="600" gender="F" club="0" colorable="1" selectable="1">
+				<part id="1" type="hd" colorable="1" index="0" colorindex="1"/>
+			</set>
+		</settype>
+		<settype type="ch" paletteid="1" mand_m_0="0" mand_f_0="0" mand_m_1="0" mand_f_1="0">
+			<set id="210" gender="U" club="0" colorable="1" selectable="1">
+				<part id="1" type="ch" colorable="1" index="0" colorindex="1"/>
+				<part id="1" type="ls" colorable="1" index="0" colorindex="2"/>
+			</set>
+			<set id="3000" gender="U" club="1" colorable="0" selectable="0">
+				<part id="1" type="ch" colorable="0" index="0" colorindex="0"/>
+			</set>
+		</settype>
+	</sets>
+</figuredata>`
+
+func TestValidateFigure(t *testing.T) {
+	var fd FigureData
+	err := fd.UnmarshalBytes([]byte(testFigureData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		figure string
+		gender nx.Gender
+		club   int
+		kinds  []FigureIssueKind
+	}{
+		{"hd-180-1.ch-210-1-1", nx.Male, 0, nil},
+		{"hd-180-1.ch-210-1-1", nx.Female, 0, []FigureIssueKind{FigureIssueGender}},
+		{"ch-210-1-1", nx.Male, 0, []FigureIssueKind{FigureIssueMissingType}},
+		{"hd-999-1", nx.Unisex, 0, []FigureIssueKind{FigureIssueUnknownSet}},
+		{"hd-180-1.hr-100", nx.Unisex, 0, []FigureIssueKind{FigureIssueUnknownType}},
+		{"hd-180-1.ch-3000", nx.Unisex, 0, []FigureIssueKind{FigureIssueNotSelectable, FigureIssueClub}},
+		{"hd-180-1.ch-3000", nx.Unisex, 1, []FigureIssueKind{FigureIssueNotSelectable}},
+		{"hd-180-1.ch-210-1", nx.Unisex, 0, []FigureIssueKind{FigureIssueColorCount}},
+		{"hd-180-2.ch-210-1-3", nx.Unisex, 0, []FigureIssueKind{FigureIssueClub, FigureIssueUnknownColor}},
+		{"hd-180-1.hd-600-1", nx.Unisex, 0, []FigureIssueKind{FigureIssueDuplicateType}},
+	}
+
+	for _, test := range tests {
+		var fig nx.Figure
+		err := fig.Parse(test.figure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fig.Gender = test.gender
+
+		var kinds []FigureIssueKind
+		for _, issue := range fd.ValidateFigure(fig, test.club) {
+			kinds = append(kinds, issue.Kind)
+		}
+		if !slices.Equal(kinds, test.kinds) {
+			t.Errorf("%s (%s, club %d): issues are %v (expected %v)",
+				test.figure, test.gender, test.club, kinds, test.kinds)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package gamedata
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
 
 	"xabbo.io/nx"
 	j "xabbo.io/nx/raw/json"
+	x "xabbo.io/nx/raw/xml"
 )
 
 // FurniData maps furniture info by identifier.
@@ -45,7 +49,34 @@ type FurniInfo struct {
 	CanLayOn        bool         `json:"canlayon"`
 }
 
+// ClothingSets maps figure part set IDs to the clothing furni that unlocks them.
+// If multiple clothing furni unlock a part set, the one that unlocks the fewest part sets is used.
+func (fd FurniData) ClothingSets() map[int]*FurniInfo {
+	partCountMap := map[int]int{}
+	clothingMap := map[int]*FurniInfo{}
+	for _, f := range fd {
+		if f.SpecialType != nx.FurniTypeClothing {
+			continue
+		}
+		parts := strings.Split(f.CustomParams, ",")
+		for _, s := range parts {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				continue
+			}
+			c := partCountMap[id]
+			if c == 0 || len(parts) < c ||
+				(len(parts) == c && f.Identifier < clothingMap[id].Identifier) {
+				partCountMap[id] = len(parts)
+				clothingMap[id] = f
+			}
+		}
+	}
+	return clothingMap
+}
+
 // Unmarshals a JSON document as raw bytes into a FurniData.
+// Nitro FurnitureData.json documents use the same format.
 func (fd *FurniData) UnmarshalBytes(data []byte) (err error) {
 	jFurniData := j.FurniData{}
 	err = json.Unmarshal(data, &jFurniData)
@@ -66,6 +97,54 @@ func (fd *FurniData) UnmarshalBytes(data []byte) (err error) {
 	return
 }
 
+// Unmarshals a Flash furnidata.xml document as raw bytes into a FurniData.
+func (fd *FurniData) UnmarshalXmlBytes(data []byte) (err error) {
+	var xFurniData x.FurniData
+	err = xml.Unmarshal(data, &xFurniData)
+	if err != nil {
+		return
+	}
+
+	*fd = FurniData{}
+	for i := range xFurniData.FloorItems {
+		xFurniInfo := &xFurniData.FloorItems[i]
+		(*fd)[xFurniInfo.Identifier] = fromXmlFurniInfo(nx.ItemFloor, xFurniInfo)
+	}
+	for i := range xFurniData.WallItems {
+		xFurniInfo := &xFurniData.WallItems[i]
+		(*fd)[xFurniInfo.Identifier] = fromXmlFurniInfo(nx.ItemWall, xFurniInfo)
+	}
+
+	return
+}
+
+func fromXmlFurniInfo(furniType nx.ItemType, xfi *x.FurniInfo) *FurniInfo {
+	return &FurniInfo{
+		Type:            furniType,
+		Kind:            xfi.Id,
+		Identifier:      xfi.Identifier,
+		Revision:        xfi.Revision,
+		Name:            xfi.Name,
+		Description:     xfi.Description,
+		Category:        xfi.Category,
+		Environment:     xfi.Environment,
+		Line:            xfi.Line,
+		DefaultDir:      xfi.DefaultDir,
+		XDim:            xfi.XDim,
+		YDim:            xfi.YDim,
+		PartColors:      xfi.PartColors,
+		OfferId:         xfi.OfferId,
+		Buyout:          xfi.Buyout,
+		BC:              xfi.BC,
+		ExcludedDynamic: xfi.ExcludedDynamic,
+		CustomParams:    xfi.CustomParams,
+		SpecialType:     nx.FurniType(xfi.SpecialType),
+		CanStandOn:      xfi.CanStandOn,
+		CanSitOn:        xfi.CanSitOn,
+		CanLayOn:        xfi.CanLayOn,
+	}
+}
+
 func fromJsonFurniInfo(furniType nx.ItemType, jfi *j.FurniInfo) *FurniInfo {
 	return &FurniInfo{
 		Type:            furniType,
@@ -0,0 +1,97 @@
+package gamedata
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// FurniDiff contains the differences between two furni data.
+type FurniDiff struct {
+	Added   []*FurniInfo    `json:"added"`   // Furni that only exist in the second furni data.
+	Removed []*FurniInfo    `json:"removed"` // Furni that only exist in the first furni data.
+	Changed []FurniInfoDiff `json:"changed"` // Furni that exist in both furni data with different information.
+}
+
+// FurniInfoDiff contains the differences of a single furni between two furni data.
+type FurniInfoDiff struct {
+	Identifier string             `json:"identifier"`
+	Before     *FurniInfo         `json:"-"`
+	After      *FurniInfo         `json:"-"`
+	Changes    []FurniFieldChange `json:"changes"`
+}
+
+// FurniFieldChange describes a change to a single field of a furni info.
+type FurniFieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// String formats the change as a human-readable description.
+func (change FurniFieldChange) String() string {
+	return fmt.Sprintf("%s: %v → %v", change.Field, change.Before, change.After)
+}
+
+// Empty returns whether there are no differences.
+func (diff *FurniDiff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
+
+// DiffFurni returns the differences between two furni data.
+// Furni are matched by identifier and ordered by identifier.
+// The revision, name, description, line, category, dimensions and custom parameters are compared.
+func DiffFurni(a, b FurniData) (diff FurniDiff) {
+	identifiers := maps.Keys(a)
+	for identifier := range b {
+		if _, ok := a[identifier]; !ok {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	slices.Sort(identifiers)
+
+	for _, identifier := range identifiers {
+		before, inA := a[identifier]
+		after, inB := b[identifier]
+		switch {
+		case !inA:
+			diff.Added = append(diff.Added, after)
+		case !inB:
+			diff.Removed = append(diff.Removed, before)
+		default:
+			if changes := diffFurniInfo(before, after); len(changes) > 0 {
+				diff.Changed = append(diff.Changed, FurniInfoDiff{
+					Identifier: identifier,
+					Before:     before,
+					After:      after,
+					Changes:    changes,
+				})
+			}
+		}
+	}
+
+	return
+}
+
+func diffFurniInfo(a, b *FurniInfo) (changes []FurniFieldChange) {
+	compare := func(field string, before, after any) {
+		if before != after {
+			changes = append(changes, FurniFieldChange{field, before, after})
+		}
+	}
+
+	compare("revision", a.Revision, b.Revision)
+	compare("name", a.Name, b.Name)
+	compare("description", a.Description, b.Description)
+	compare("line", a.Line, b.Line)
+	compare("category", a.Category, b.Category)
+	compare("dimensions", furniDimensions(a), furniDimensions(b))
+	compare("customparams", strings.TrimSpace(a.CustomParams), strings.TrimSpace(b.CustomParams))
+	return
+}
+
+func furniDimensions(fi *FurniInfo) string {
+	return fmt.Sprintf("%dx%d", fi.XDim, fi.YDim)
+}
@@ -0,0 +1,45 @@
+package gamedata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffFurni(t *testing.T) {
+	a := FurniData{
+		"chair":  {Identifier: "chair", Revision: 1, Name: "Chair", XDim: 1, YDim: 1},
+		"table":  {Identifier: "table", Revision: 1, Name: "Table", XDim: 2, YDim: 2},
+		"lamp":   {Identifier: "lamp", Revision: 3, Name: "Lamp"},
+		"poster": {Identifier: "poster", Revision: 1, Name: "Poster"},
+	}
+	b := FurniData{
+		"chair": {Identifier: "chair", Revision: 1, Name: "Chair", XDim: 1, YDim: 1},
+		"table": {Identifier: "table", Revision: 2, Name: "Big Table", XDim: 2, YDim: 3},
+		"lamp":  {Identifier: "lamp", Revision: 3, Name: "Lamp"},
+		"sofa":  {Identifier: "sofa", Revision: 1, Name: "Sofa"},
+	}
+
+	diff := DiffFurni(a, b)
+	if len(diff.Added) != 1 || diff.Added[0].Identifier != "sofa" {
+		t.Fatalf("unexpected added furni: %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Identifier != "poster" {
+		t.Fatalf("unexpected removed furni: %v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Identifier != "table" {
+		t.Fatalf("unexpected changed furni: %v", diff.Changed)
+	}
+
+	var fields []string
+	for _, change := range diff.Changed[0].Changes {
+		fields = append(fields, change.Field)
+	}
+	expected := []string{"revision", "name", "dimensions"}
+	if !slices.Equal(fields, expected) {
+		t.Fatalf("changed fields are %v (expected %v)", fields, expected)
+	}
+
+	if diff := DiffFurni(a, a); !diff.Empty() {
+		t.Fatal("expected no differences between equal furni data")
+	}
+}
@@ -1,8 +1,10 @@
 package gamedata
 
 import (
+	"errors"
 	"reflect"
 
+	j "xabbo.io/nx/raw/json"
 	"xabbo.io/nx/res"
 )
 
@@ -18,11 +20,20 @@ const (
 	GameDataFigure    Type = "figurepartlist"
 	GameDataFigureMap Type = "figuremap"
 	GameDataAvatar    Type = "HabboAvatarActions"
-
-	keyFlashClientUrl          = "flash.client.url"
-	habboAvatarActionsFilename = "HabboAvatarActions.xml"
+	GameDataEffectMap Type = "effectmap"
+	GameDataGeometry  Type = "HabboAvatarGeometry"
+	GameDataPartSets  Type = "HabboAvatarPartSets"
+
+	keyFlashClientUrl           = "flash.client.url"
+	habboAvatarActionsFilename  = "HabboAvatarActions.xml"
+	effectMapFilename           = "effectmap.xml"
+	habboAvatarGeometryFilename = "HabboAvatarGeometry.xml"
+	habboAvatarPartSetsFilename = "HabboAvatarPartSets.xml"
 )
 
+// ErrNotFound is returned when a requested library or game data entry does not exist.
+var ErrNotFound = errors.New("not found")
+
 // A Manager provides an interface to manage game data.
 type Manager interface {
 	FigureManager
@@ -30,13 +41,28 @@ type Manager interface {
 	ProductManager
 	TextManager
 	VariableManager
+	RoomLibraryManager
 	// Loads the specified game data types.
-	// If none are specified, all game data types are loaded.
+	// If none are specified, the default game data types are loaded.
+	// The avatar part sets, geometry and effect map are only loaded when requested.
 	Load(types ...Type) error
 	// Gets whether all of the specified game data types are loaded.
 	Loaded(types ...Type) bool
 }
 
+// A HashManager provides an interface to fetch game data hashes and hashed game data.
+type HashManager interface {
+	// Gets the current game data hashes.
+	GetHashes() (*j.GameDataHashes, error)
+	// Downloads the game data for the specified hash.
+	DownloadHash(hash j.GameDataHash) ([]byte, error)
+}
+
+// HashedTypes contains the game data types that are versioned by hash.
+var HashedTypes = []Type{
+	GameDataFurni, GameDataFigure, GameDataProduct, GameDataTexts, GameDataVariables,
+}
+
 // Map of hashed game data types.
 var hashTypeMap = map[Type]reflect.Type{
 	GameDataFurni:     reflect.TypeOf((*FurniData)(nil)).Elem(),
@@ -63,17 +89,22 @@ type FurniManager interface {
 	FurniLibraryManager
 }
 
-// A FigureDataManager provides an interface to get figure data, figure map and avatar actions.
+// A FigureDataManager provides an interface to get figure data, figure map, avatar actions,
+// avatar part sets, avatar geometry and effect map.
 type FigureDataManager interface {
-	Figure() *FigureData          // Gets the figure data.
-	FigureMap() *FigureMap        // Gets the figure map.
-	AvatarActions() AvatarActions // Gets the avatar actions.
+	Figure() *FigureData             // Gets the figure data.
+	FigureMap() *FigureMap           // Gets the figure map.
+	AvatarActions() AvatarActions    // Gets the avatar actions.
+	AvatarPartSets() AvatarPartSets  // Gets the avatar part sets.
+	AvatarGeometry() *AvatarGeometry // Gets the avatar geometry.
+	EffectMap() EffectMap            // Gets the effect map.
 }
 
-// A FigureLibraryManager provides an interface to manage figure part libraries.
+// A FigureLibraryManager provides an interface to manage figure part and effect libraries.
 type FigureLibraryManager interface {
 	res.LibraryManager
 	LoadFigureParts(libraries ...string) error
+	LoadEffects(libraries ...string) error // Loads the specified effect libraries by name.
 }
 
 // A FigureManager provides an interface to manage figure data and libraries.
@@ -82,6 +113,14 @@ type FigureManager interface {
 	FigureLibraryManager
 }
 
+// A RoomLibraryManager provides an interface to manage the room content library.
+type RoomLibraryManager interface {
+	res.LibraryManager
+	// Loads the room content library, which contains the floor, wall and landscape textures.
+	// Once loaded, it is available as the res.RoomContentLibrary library.
+	LoadRoomContent() error
+}
+
 // A ProductManager provides an interface to get product data.
 type ProductManager interface {
 	Products() ProductData // Gets the products data.
@@ -0,0 +1,298 @@
+package gamedata
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"xabbo.io/nx"
+	"xabbo.io/nx/raw/nitro"
+	"xabbo.io/nx/res"
+)
+
+const testNitroFigureData = `{
+	"palettes": [{"id": 1, "colors": [
+		{"id": 1, "index": 1, "club": 0, "selectable": true, "hexCode": "FFCB98"},
+		{"id": 2, "index": 2, "club": 2, "selectable": true, "hexCode": "F4AC54"}
+	]}],
+	"setTypes": [
+		{"type": "hd", "paletteId": 1, "mandatory_m_0": true, "mandatory_f_0": true, "mandatory_m_1": true, "mandatory_f_1": true, "sets": [
+			{"id": 180, "gender": "M", "club": 0, "colorable": true, "selectable": true, "parts": [
+				{"id": 1, "type": "hd", "colorable": true, "index": 0, "colorindex": 1}
+			]},
+			{"id": 600, "gender": "F", "club": 0, "colorable": true, "selectable": true, "parts": [
+				{"id": 1, "type": "hd", "colorable": true, "index": 0, "colorindex": 1}
+			]}
+		]},
+		{"type": "ch", "paletteId": 1, "mandatory_m_0": false, "mandatory_f_0": false, "mandatory_m_1": false, "mandatory_f_1": false, "sets": [
+			{"id": 210, "gender": "U", "club": 0, "colorable": true, "selectable": true, "parts": [
+				{"id": 1, "type": "ch", "colorable": true, "index": 0, "colorindex": 1},
+				{"id": 1, "type": "ls", "colorable": true, "index": 0, "colorindex": 2}
+			]},
+			{"id": 3000, "gender": "U", "club": 1, "colorable": false, "selectable": false, "parts": [
+				{"id": 1, "type": "ch", "colorable": false, "index": 0, "colorindex": 0}
+			]}
+		]}
+	]
+}`
+
+func TestFigureDataNitro(t *testing.T) {
+	var xfd, nfd FigureData
+	if err := xfd.UnmarshalBytes([]byte(testFigureData)); err != nil {
+		t.Fatal(err)
+	}
+	if err := nfd.UnmarshalNitroBytes([]byte(testNitroFigureData)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(xfd, nfd) {
+		t.Fatalf("nitro figure data does not match xml figure data")
+	}
+}
+
+func TestFigureMapNitro(t *testing.T) {
+	var fm FigureMap
+	err := fm.UnmarshalNitroBytes([]byte(`{"libraries":[
+		{"id": "hh_human_shirt", "revision": 1, "parts": [{"id": 210, "type": "ch"}, {"id": "1", "type": "ls"}]},
+		{"id": "hh_human_fx", "revision": 2, "parts": [{"id": "dance.1", "type": "fx"}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lib := fm.Parts[nx.FigurePart{Type: "ch", Id: 210}]
+	if lib == nil || lib.Name != "hh_human_shirt" {
+		t.Fatalf("expected part ch-210 to map to hh_human_shirt, got %+v", lib)
+	}
+	if lib := fm.Parts[nx.FigurePart{Type: "ls", Id: 1}]; lib == nil {
+		t.Fatalf("expected part ls-1 to be mapped")
+	}
+	if lib := fm.Libs["hh_human_fx"]; lib == nil || lib.Revision != 2 {
+		t.Fatalf("unexpected library: %+v", lib)
+	}
+}
+
+func TestExternalsNitro(t *testing.T) {
+	var texts ExternalTexts
+	err := texts.UnmarshalNitroBytes([]byte(`{"furni_chair_name": "Chair", "widget.count": 3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if texts["furni_chair_name"] != "Chair" || texts["widget.count"] != "3" {
+		t.Fatalf("unexpected texts: %v", texts)
+	}
+
+	var vars ExternalVariables
+	err = vars.UnmarshalNitroBytes([]byte(`{
+		"asset.url": "https://assets.test",
+		"furni.asset.url": "${asset.url}/bundled/furniture/%libname%.nitro",
+		"external.texts.url": ["${asset.url}/ExternalTexts.json", "${asset.url}/UITexts.json"],
+		"system.animation.enabled": true,
+		"missing": "${unknown.key}"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ExternalVariables{
+		"asset.url":                "https://assets.test",
+		"furni.asset.url":          "https://assets.test/bundled/furniture/%libname%.nitro",
+		"external.texts.url":       "https://assets.test/ExternalTexts.json,https://assets.test/UITexts.json",
+		"system.animation.enabled": "true",
+		"missing":                  "${unknown.key}",
+	}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Fatalf("expected %v, got %v", expected, vars)
+	}
+}
+
+const testXmlAvatarData = `<actions>
+	<action id="Default" state="std" precedence="1000" main="1" isdefault="1" geometrytype="vertical" activepartset="figure" assetpartdefinition="std"/>
+	<action id="Lay" state="lay" precedence="1000" main="1" geometrytype="horizontal" activepartset="figure" assetpartdefinition="lay" prevents="wave,sit" preventheadturn="1"/>
+	<action id="Dance" state="dance" precedence="500" activepartset="figure" assetpartdefinition="dan">
+		<type id="1" animated="1"/>
+		<type id="2" animated="1" prevents="wave" preventheadturn="1"/>
+	</action>
+	<action id="CarryItem" state="cri" precedence="100" activepartset="handRight" assetpartdefinition="crr">
+		<param id="default" value="1"/>
+	</action>
+	<actionoffsets>
+		<action id="lay">
+			<offset size="h" direction="2" x="-10" y="20" z="0.5"/>
+		</action>
+	</actionoffsets>
+</actions>`
+
+const testNitroAvatarData = `{
+	"actions": [
+		{"id": "Default", "state": "std", "precedence": 1000, "main": true, "isDefault": true, "geometryType": "vertical", "activePartSet": "figure", "assetPartDefinition": "std"},
+		{"id": "Lay", "state": "lay", "precedence": 1000, "main": true, "geometryType": "horizontal", "activePartSet": "figure", "assetPartDefinition": "lay", "prevents": ["wave", "sit"], "preventHeadTurn": true},
+		{"id": "Dance", "state": "dance", "precedence": 500, "activePartSet": "figure", "assetPartDefinition": "dan", "types": [
+			{"id": 1, "animated": true},
+			{"id": 2, "animated": true, "prevents": ["wave"], "preventHeadTurn": true}
+		]},
+		{"id": "CarryItem", "state": "cri", "precedence": 100, "activePartSet": "handRight", "assetPartDefinition": "crr", "params": [{"id": "default", "value": "1"}]}
+	],
+	"actionOffsets": [
+		{"action": "lay", "offsets": [{"size": "h", "direction": 2, "x": -10, "y": 20, "z": 0.5}]}
+	]
+}`
+
+const testXmlGeometry = `<geometry>
+	<camera><x>0</x><y>0</y><z>10</z></camera>
+	<type id="vertical">
+		<bodypart id="head" x="0" y="0" z="0" radius="0.2">
+			<item id="hd" x="0" y="0" z="0" radius="0.05" nx="0" ny="0" nz="-1" double="1"/>
+		</bodypart>
+	</type>
+</geometry>`
+
+const testNitroGeometry = `{
+	"camera": {"x": 0, "y": 0, "z": 10},
+	"types": [{"id": "vertical", "bodyParts": [{"id": "head", "x": 0, "y": 0, "z": 0, "radius": 0.2, "items": [
+		{"id": "hd", "x": 0, "y": 0, "z": 0, "radius": 0.05, "nx": 0, "ny": 0, "nz": -1, "double": true}
+	]}]}]
+}`
+
+func TestAvatarDataNitro(t *testing.T) {
+	var xActions, nActions AvatarActions
+	if err := xActions.UnmarshalBytes([]byte(testXmlAvatarData)); err != nil {
+		t.Fatal(err)
+	}
+	if err := nActions.UnmarshalNitroBytes([]byte(testNitroAvatarData)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(xActions, nActions) {
+		t.Fatalf("nitro avatar actions do not match xml avatar actions")
+	}
+	if dance := nActions["Dance"]; len(dance.Types) != 2 || !dance.Types[2].PreventHeadTurn {
+		t.Fatalf("unexpected dance types: %+v", dance.Types)
+	}
+
+	var xGeometry, nGeometry AvatarGeometry
+	if err := xGeometry.UnmarshalBytes([]byte(testXmlGeometry)); err != nil {
+		t.Fatal(err)
+	}
+	if err := nGeometry.UnmarshalNitroBytes([]byte(testNitroGeometry)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(xGeometry, nGeometry) {
+		t.Fatalf("nitro avatar geometry does not match xml avatar geometry")
+	}
+
+	var xEffects, nEffects EffectMap
+	if err := xEffects.UnmarshalBytes([]byte(`<map><effect id="1" lib="Dance1" type="dance" revision="3"/></map>`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := nEffects.UnmarshalNitroBytes([]byte(`{"effects": [{"id": "1", "lib": "Dance1", "type": "dance", "revision": 3}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(xEffects, nEffects) || nEffects[1] == nil {
+		t.Fatalf("nitro effect map does not match xml effect map")
+	}
+}
+
+func TestDirManagerNitroAvatarData(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"HabboAvatarActions.json":  testNitroAvatarData,
+		"HabboAvatarGeometry.json": testNitroGeometry,
+		"EffectMap.json":           `{"effects": [{"id": "1", "lib": "Dance1", "type": "dance", "revision": 3}]}`,
+	}
+	if err := os.Mkdir(filepath.Join(dir, "gamedata"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, "gamedata", name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mgr := NewDirManager(dir, NitroLayout)
+	if err := mgr.Load(GameDataAvatar, GameDataGeometry, GameDataEffectMap); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if lay := mgr.AvatarActions()["Lay"]; lay == nil || !lay.PreventHeadTurn {
+		t.Fatalf("unexpected lay action: %+v", lay)
+	}
+	if order := mgr.AvatarGeometry().PartOrder("vertical", 2, 2); len(order) != 1 || order[0] != nx.Head {
+		t.Fatalf("unexpected part order: %v", order)
+	}
+	if effect := mgr.EffectMap()[1]; effect == nil || effect.Library != "Dance1" {
+		t.Fatalf("unexpected effect: %+v", effect)
+	}
+}
+
+func TestDirManagerNitroEffect(t *testing.T) {
+	sheet := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	sheet.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	var sheetBuf bytes.Buffer
+	if err := png.Encode(&sheetBuf, sheet); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := `{
+		"name": "Dance1",
+		"type": "effect",
+		"assets": {"h_std_fx1_1_2_0": {"x": -5, "y": 10}},
+		"animations": {"Dance.1": {
+			"sprites": [{"id": "fx1", "member": "fx1", "directions": 1, "ink": 33, "directionList": [{"id": 2, "dx": 1, "dz": -1}]}],
+			"adds": [{"id": "fx1", "align": "bottom", "base": "torso"}],
+			"removes": [{"id": "ri"}],
+			"shadow": {"id": "fx1"},
+			"direction": {"offset": 2},
+			"frames": [{"repeats": 2, "bodyparts": [{"id": "torso", "action": "dan", "frame": 1}], "fxs": [{"id": "fx1", "frame": 1, "dd": 1}]}]
+		}},
+		"spritesheet": {
+			"frames": {"Dance1_h_std_fx1_1_2_0.png": {"frame": {"x": 0, "y": 0, "w": 2, "h": 2}}},
+			"meta": {"image": "Dance1.png", "scale": "1"}
+		}
+	}`
+	archive := nitro.Archive{Files: map[string]nitro.File{
+		"Dance1.json": {Name: "Dance1.json", Data: []byte(metadata)},
+		"Dance1.png":  {Name: "Dance1.png", Data: sheetBuf.Bytes()},
+	}}
+
+	dir := t.TempDir()
+	effectDir := filepath.Join(dir, NitroLayout.EffectDir)
+	if err := os.MkdirAll(effectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := nitro.NewWriter(&buf).WriteArchive(archive); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(effectDir, "Dance1.nitro"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewDirManager(dir, NitroLayout)
+	if err := mgr.LoadEffects("Dance1"); err != nil {
+		t.Fatalf("failed to load effect: %s", err)
+	}
+	lib, ok := mgr.Library("Dance1").(res.EffectLibrary)
+	if !ok {
+		t.Fatal("effect library not loaded")
+	}
+	if asset, err := lib.Asset("h_std_fx1_1_2_0"); err != nil || asset.Image == nil || asset.Offset != image.Pt(-5, 10) {
+		t.Fatalf("unexpected asset: %+v, %v", asset, err)
+	}
+
+	anim := lib.Animation()
+	if len(anim.Sprites) != 1 || !anim.Sprites[0].Directional || anim.Sprites[0].Offsets[2] != (res.EffectSpriteOffset{Dx: 1, Dz: -1}) {
+		t.Fatalf("unexpected sprites: %+v", anim.Sprites)
+	}
+	if len(anim.Adds) != 1 || anim.Adds[0].Base != "torso" || len(anim.Removes) != 1 || anim.Shadow != "fx1" {
+		t.Fatalf("unexpected effect animation: %+v", anim)
+	}
+	if anim.DirectionOffset != 2 || len(anim.Frames) != 1 || anim.Frames[0].Repeats != 2 {
+		t.Fatalf("unexpected effect frames: %+v", anim)
+	}
+	if part := anim.Frames[0].Fx["fx1"]; part.Frame != 1 || part.DirectionOffset != 1 {
+		t.Fatalf("unexpected effect frame part: %+v", part)
+	}
+}
@@ -0,0 +1,81 @@
+package gamedata
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrOffline is returned when a resource is not cached while the manager is in offline mode.
+var ErrOffline = errors.New("resource not available in offline mode")
+
+const (
+	defaultHashesTTL = time.Hour * 4
+	defaultBlobTTL   = time.Hour * 24 * 365
+)
+
+// A ManagerOption configures a game data manager created by NewManager.
+type ManagerOption func(*webGameDataManager)
+
+// WithCacheDir sets the directory used to cache game data and assets.
+func WithCacheDir(dir string) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.cacheDir = dir
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to fetch game data and assets.
+func WithHTTPClient(client *http.Client) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.client = client
+	}
+}
+
+// WithTransport sets the HTTP transport used to fetch game data and assets.
+// The HTTP client is copied if one was provided with WithHTTPClient.
+func WithTransport(transport http.RoundTripper) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.transport = transport
+	}
+}
+
+// WithUserAgent sets the user agent sent with each request.
+// If empty, the User-Agent header is not modified.
+func WithUserAgent(agent string) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.userAgent = agent
+	}
+}
+
+// WithHashesTTL sets the duration for which the game data hashes are cached before being fetched again.
+func WithHashesTTL(ttl time.Duration) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.hashesTTL = ttl
+	}
+}
+
+// WithBlobTTL sets the duration for which hashed game data are cached before being fetched again.
+// A value of zero caches hashed game data indefinitely.
+func WithBlobTTL(ttl time.Duration) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.blobTTL = ttl
+	}
+}
+
+// WithOffline sets whether the manager is in offline mode.
+// In offline mode, game data and assets are only loaded from the cache regardless of their age,
+// and an error wrapping ErrOffline is returned if they are not cached.
+func WithOffline(offline bool) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.offline = offline
+	}
+}
+
+// WithNitroAssetBase sets the base URL of a Nitro asset server to load figure part libraries from.
+// Figure part libraries are then fetched from `bundled/figure/<library>.nitro` under the base URL
+// instead of from the Flash client.
+func WithNitroAssetBase(baseUrl string) ManagerOption {
+	return func(mgr *webGameDataManager) {
+		mgr.nitroAssetBase = baseUrl
+	}
+}
@@ -0,0 +1,220 @@
+package origins
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"xabbo.io/nx"
+)
+
+// A ConversionIssue describes a figure item that could not be exactly represented when converting a figure.
+type ConversionIssue struct {
+	// The item that could not be represented.
+	// Its ID is zero if a mandatory part type was missing from the figure.
+	Item nx.FigureItem
+	// The ID of the part set used instead of the item's part set,
+	// or zero if the item was not converted.
+	SubstituteId int
+}
+
+// String formats the conversion issue as a human-readable description.
+func (issue ConversionIssue) String() string {
+	switch {
+	case issue.Item.Id == 0 && issue.SubstituteId == 0:
+		return fmt.Sprintf("mandatory part type %s cannot be represented", issue.Item.Type)
+	case issue.Item.Id == 0:
+		return fmt.Sprintf("mandatory part type %s is missing and was substituted with part set %d",
+			issue.Item.Type, issue.SubstituteId)
+	case issue.SubstituteId == 0:
+		return fmt.Sprintf("%s cannot be represented", issue.Item.String())
+	}
+	return fmt.Sprintf("%s was substituted with part set %d", issue.Item.String(), issue.SubstituteId)
+}
+
+// mandatoryTypes contains the part types that every origins figure must have.
+var mandatoryTypes = []nx.FigurePartType{nx.Hair, nx.Head, nx.Chest, nx.Legs, nx.Shoes}
+
+// ConvertToOrigins converts a modern `nx.Figure` to its nearest origins figure string.
+//
+// Items are converted to the origins part set with the same type and ID for the figure's gender,
+// and colors are mapped to the part set's color with the nearest value.
+// Items of an optional part type without an origins part set are not converted.
+// Items of a mandatory part type without an origins part set are substituted with the part set
+// of that type with the color nearest to the item's color, and missing mandatory part types
+// are substituted with the first part set of that type.
+// Hats that are represented by the origins hair part set are omitted.
+// Items that could not be exactly represented are returned as issues.
+func (fc *FigureConverter) ConvertToOrigins(figure nx.Figure) (originsFigure string, issues []ConversionIssue, err error) {
+	genderSets := fc.genderSets(figure)
+
+	var hairId int
+	for _, item := range figure.Items {
+		if item.Type == nx.Hair {
+			hairId = item.Id
+		}
+	}
+
+	var sb strings.Builder
+	write := func(set FigurePartSet, colorIndex int) error {
+		if set.Id < 0 || set.Id > 999 {
+			return fmt.Errorf("origins part set ID out of range: %d", set.Id)
+		}
+		if len(set.Colors) == 0 || len(set.Colors) > 99 {
+			return fmt.Errorf("origins part set %d has an invalid number of colors: %d", set.Id, len(set.Colors))
+		}
+		fmt.Fprintf(&sb, "%03d%02d", set.Id, colorIndex+1)
+		return nil
+	}
+
+	converted := map[nx.FigurePartType]bool{}
+	for _, item := range figure.Items {
+		if item.Type == nx.Hat && hairToHatMap[hairId] == item.Id {
+			continue
+		}
+
+		sets := genderSets[item.Type]
+		colorId := -1
+		if len(item.Colors) > 0 {
+			colorId = item.Colors[0]
+		}
+
+		set, ok := findSet(sets, item.Id)
+		if !ok {
+			if !slices.Contains(mandatoryTypes, item.Type) || len(sets) == 0 {
+				issues = append(issues, ConversionIssue{Item: item})
+				continue
+			}
+			set = fc.nearestColorSet(item.Type, colorId, sets)
+			issues = append(issues, ConversionIssue{Item: item, SubstituteId: set.Id})
+		}
+
+		colorIndex := 0
+		if colorId >= 0 {
+			colorIndex, _ = fc.nearestColorIndex(item.Type, colorId, set.Colors)
+		}
+		if err = write(set, colorIndex); err != nil {
+			return
+		}
+		converted[item.Type] = true
+	}
+
+	for _, partType := range mandatoryTypes {
+		if converted[partType] {
+			continue
+		}
+		missing := nx.FigureItem{Type: partType}
+		sets := genderSets[partType]
+		if len(sets) == 0 {
+			issues = append(issues, ConversionIssue{Item: missing})
+			continue
+		}
+		set := slices.MinFunc(sets, func(a, b FigurePartSet) int { return a.Id - b.Id })
+		if err = write(set, 0); err != nil {
+			return
+		}
+		issues = append(issues, ConversionIssue{Item: missing, SubstituteId: set.Id})
+	}
+
+	originsFigure = sb.String()
+	return
+}
+
+// genderSets returns the origins part sets for the figure's gender.
+// If the figure is unisex, the gender is inferred from the figure's part sets.
+func (fc *FigureConverter) genderSets(figure nx.Figure) map[nx.FigurePartType]FigurePartSets {
+	switch figure.Gender {
+	case nx.Male:
+		return fc.figureData.M
+	case nx.Female:
+		return fc.figureData.F
+	}
+	for _, item := range figure.Items {
+		for _, set := range fc.figureData.F[item.Type] {
+			if set.Id == item.Id {
+				return fc.figureData.F
+			}
+		}
+	}
+	return fc.figureData.M
+}
+
+// findSet finds the part set with the specified ID.
+func findSet(sets FigurePartSets, id int) (set FigurePartSet, ok bool) {
+	i := slices.IndexFunc(sets, func(set FigurePartSet) bool { return set.Id == id })
+	if i < 0 {
+		return
+	}
+	return sets[i], true
+}
+
+// nearestColorSet returns the part set with the color nearest to the modern color ID of the specified part type.
+// If multiple part sets are equally near, the one with the lowest ID is returned.
+func (fc *FigureConverter) nearestColorSet(partType nx.FigurePartType, colorId int, sets FigurePartSets) (nearest FigurePartSet) {
+	best := -1
+	for _, set := range sets {
+		_, distance := fc.nearestColorIndex(partType, colorId, set.Colors)
+		if best < 0 || distance < best || (distance == best && set.Id < nearest.Id) {
+			nearest, best = set, distance
+		}
+	}
+	return
+}
+
+// nearestColorIndex returns the index of the color in colors that is nearest
+// to the modern color ID of the specified part type, along with its squared distance.
+// The distance is math.MaxInt if none of the colors are valid.
+// If the modern color is unknown, the first color is returned with a distance of zero.
+func (fc *FigureConverter) nearestColorIndex(partType nx.FigurePartType, colorId int, colors []string) (index, best int) {
+	var value string
+	for hex, id := range fc.colorMap[partType] {
+		if id == colorId {
+			value = hex
+			break
+		}
+	}
+
+	target, ok := parseHexColor(value)
+	if !ok {
+		return 0, 0
+	}
+
+	best = -1
+	for i, color := range colors {
+		if strings.EqualFold(color, value) {
+			return i, 0
+		}
+		rgb, ok := parseHexColor(color)
+		if !ok {
+			continue
+		}
+		distance := 0
+		for j := range rgb {
+			d := rgb[j] - target[j]
+			distance += d * d
+		}
+		if best < 0 || distance < best {
+			index, best = i, distance
+		}
+	}
+	if best < 0 {
+		best = math.MaxInt
+	}
+	return
+}
+
+func parseHexColor(s string) (rgb [3]int, ok bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return
+	}
+	rgb = [3]int{int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff)}
+	ok = true
+	return
+}
@@ -0,0 +1,75 @@
+package origins
+
+import (
+	"testing"
+
+	"xabbo.io/nx"
+)
+
+func TestConvertToOrigins(t *testing.T) {
+	figureData := &FigureData{
+		M: map[nx.FigurePartType]FigurePartSets{
+			nx.Hair: {
+				{Id: 120, Colors: []string{"FFFFFF", "000000"}},
+				{Id: 180, Colors: []string{"000000"}},
+			},
+			nx.Head: {
+				{Id: 180, Colors: []string{"FFCB98", "E3AE7D"}},
+			},
+			nx.Chest: {{Id: 215, Colors: []string{"FFFFFF"}}},
+			nx.Legs:  {{Id: 270, Colors: []string{"FFFFFF"}}},
+			nx.Shoes: {{Id: 290, Colors: []string{"FFFFFF"}}},
+		},
+		F: map[nx.FigurePartType]FigurePartSets{
+			nx.Hair: {{Id: 525, Colors: []string{"FFFFFF"}}},
+		},
+	}
+	colorMap := ColorMap{
+		nx.Hair: {"ffffff": 1, "101010": 2},
+		nx.Head: {"ffcb98": 3, "e3ae7d": 4},
+	}
+	converter := NewFigureConverter(figureData, colorMap)
+
+	var figure nx.Figure
+	err := figure.Parse("hr-120-2.ha-1001-2.hd-180-4.ch-210-66.lg-270-82.sh-290-80.ca-1801-62")
+	if err != nil {
+		t.Fatal(err)
+	}
+	figure.Gender = nx.Male
+
+	originsFigure, issues, err := converter.ConvertToOrigins(figure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "1200218002215012700129001"; originsFigure != expected {
+		t.Fatalf("converted figure is %q (expected %q)", originsFigure, expected)
+	}
+	if len(issues) != 2 ||
+		issues[0].Item.Type != nx.Chest || issues[0].SubstituteId != 215 ||
+		issues[1].Item.Type != nx.ChestAcc || issues[1].SubstituteId != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+
+	// The female hair set does not exist for male figures, so it is substituted
+	// with the male hair set that has the nearest color, and missing mandatory parts are added.
+	err = figure.Parse("hr-525-1.hd-180-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	figure.Gender = nx.Male
+	originsFigure, issues, err = converter.ConvertToOrigins(figure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "1200118001215012700129001"; originsFigure != expected {
+		t.Fatalf("converted figure is %q (expected %q)", originsFigure, expected)
+	}
+	if len(issues) != 4 || issues[0].Item.Id != 525 || issues[0].SubstituteId != 120 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	for _, issue := range issues[1:] {
+		if issue.Item.Id != 0 || issue.SubstituteId == 0 {
+			t.Fatalf("expected missing mandatory part substitution: %v", issue)
+		}
+	}
+}
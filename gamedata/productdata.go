@@ -1,6 +1,7 @@
 package gamedata
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -18,6 +19,7 @@ type ProductInfo struct {
 }
 
 // Unmarshals a JSON document as raw bytes into a ProductData.
+// Nitro ProductData.json documents use the same format.
 func (pd *ProductData) UnmarshalBytes(data []byte) (err error) {
 	var jpd j.ProductDataContainer
 	err = json.Unmarshal(data, &jpd)
@@ -39,3 +41,37 @@ func (pd *ProductData) UnmarshalBytes(data []byte) (err error) {
 
 	return
 }
+
+// Unmarshals a Flash productdata.txt document as raw bytes into a ProductData.
+// Each line of the document contains a list of products, where each product is a list of its code, name and description.
+func (pd *ProductData) UnmarshalTextBytes(data []byte) (err error) {
+	*pd = ProductData{}
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var products [][]string
+		err = json.Unmarshal(line, &products)
+		if err != nil {
+			return
+		}
+		for _, product := range products {
+			if len(product) == 0 {
+				continue
+			}
+			info := &ProductInfo{Code: product[0]}
+			if len(product) > 1 {
+				info.Name = product[1]
+			}
+			if len(product) > 2 {
+				info.Description = product[2]
+			}
+			if _, exist := (*pd)[info.Code]; exist {
+				return fmt.Errorf("duplicate product code: %q", info.Code)
+			}
+			(*pd)[info.Code] = info
+		}
+	}
+	return
+}
@@ -17,19 +17,31 @@ import (
 
 	"b7c.io/swfx"
 	"golang.org/x/sync/errgroup"
+
+	"xabbo.io/nx"
 	j "xabbo.io/nx/raw/json"
+	"xabbo.io/nx/raw/nitro"
 	"xabbo.io/nx/res"
 )
 
 type webGameDataManager struct {
-	client   *http.Client
-	host     string
-	cacheDir string
-	hashes   map[Type]string
+	client         *http.Client
+	transport      http.RoundTripper
+	userAgent      string
+	host           string
+	cacheDir       string
+	hashesTTL      time.Duration
+	blobTTL        time.Duration
+	offline        bool
+	hashes         map[Type]string
+	nitroAssetBase string
 
 	figure        *FigureData
 	figureMap     *FigureMap
 	avatarActions AvatarActions
+	partSets      AvatarPartSets
+	geometry      *AvatarGeometry
+	effectMap     EffectMap
 	furni         FurniData
 	products      ProductData
 	texts         ExternalTexts
@@ -62,21 +74,30 @@ type bytesUnmarshaler interface {
 
 // Creates a new web-based game data manager.
 // The provided manager fetches assets from the web and caches assets to disk.
-// The cache directory is located under `xabbo/nx` within the user's cache directory.
-func NewManager(host string) Manager {
-	cacheDir, err := os.UserCacheDir()
-	if err != nil {
-		cacheDir = ".cache"
+// By default, the cache directory is located under `xabbo/nx` within the user's cache directory,
+// requests are sent with the nx user agent, game data hashes are refetched every 4 hours,
+// and hashed game data are refetched after a year.
+func NewManager(host string, options ...ManagerOption) Manager {
+	mgr := &webGameDataManager{
+		client:      &http.Client{},
+		userAgent:   nx.DefaultUserAgent(),
+		host:        host,
+		hashes:      make(map[Type]string),
+		cacheDir:    DefaultCacheDir(),
+		hashesTTL:   defaultHashesTTL,
+		blobTTL:     defaultBlobTTL,
+		assets:      res.NewManager(),
+		lastFetched: make(map[Type]time.Time),
 	}
-	cacheDir = filepath.Join(cacheDir, "xabbo", "nx")
-
-	return &webGameDataManager{
-		client:   &http.Client{},
-		host:     host,
-		hashes:   make(map[Type]string),
-		cacheDir: cacheDir,
-		assets:   res.NewManager(),
+	for _, configure := range options {
+		configure(mgr)
 	}
+	if mgr.transport != nil {
+		client := *mgr.client
+		client.Transport = mgr.transport
+		mgr.client = &client
+	}
+	return mgr
 }
 
 func (mgr *webGameDataManager) Figure() *FigureData {
@@ -91,6 +112,18 @@ func (mgr *webGameDataManager) AvatarActions() AvatarActions {
 	return mgr.avatarActions
 }
 
+func (mgr *webGameDataManager) AvatarPartSets() AvatarPartSets {
+	return mgr.partSets
+}
+
+func (mgr *webGameDataManager) AvatarGeometry() *AvatarGeometry {
+	return mgr.geometry
+}
+
+func (mgr *webGameDataManager) EffectMap() EffectMap {
+	return mgr.effectMap
+}
+
 func (mgr *webGameDataManager) Furni() FurniData {
 	return mgr.furni
 }
@@ -134,6 +167,22 @@ func (mgr *webGameDataManager) Loaded(types ...Type) bool {
 			if mgr.figureMap == nil {
 				return false
 			}
+		case GameDataAvatar:
+			if mgr.avatarActions == nil {
+				return false
+			}
+		case GameDataPartSets:
+			if mgr.partSets == nil {
+				return false
+			}
+		case GameDataGeometry:
+			if mgr.geometry == nil {
+				return false
+			}
+		case GameDataEffectMap:
+			if mgr.effectMap == nil {
+				return false
+			}
 		default:
 			panic(fmt.Errorf("unknown game data type %q", t))
 		}
@@ -142,9 +191,11 @@ func (mgr *webGameDataManager) Loaded(types ...Type) bool {
 }
 
 func (mgr *webGameDataManager) Load(types ...Type) (err error) {
-	err = os.MkdirAll(mgr.cacheDir, 0755)
-	if err != nil {
-		return
+	if !mgr.offline {
+		err = os.MkdirAll(mgr.cacheDir, 0755)
+		if err != nil {
+			return
+		}
 	}
 
 	hashes, err := mgr.GetHashes()
@@ -259,6 +310,75 @@ func (mgr *webGameDataManager) Load(types ...Type) (err error) {
 		mgr.avatarActions = avatarActions
 	}
 
+	if slices.Contains(types, GameDataPartSets) {
+		clientUrl, exist := mgr.variables[keyFlashClientUrl]
+		if !exist {
+			err = fmt.Errorf("unable to load avatar part sets - failed to retrieve %s from external variables", keyFlashClientUrl)
+			return
+		}
+		version := path.Base(clientUrl)
+		filePath := filepath.Join(mgr.cacheDir, mgr.host, string(GameDataPartSets), version)
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, clientUrl+habboAvatarPartSetsFilename, 0)
+		if err != nil {
+			return
+		}
+
+		var partSets AvatarPartSets
+		err = partSets.UnmarshalBytes(data)
+		if err != nil {
+			return
+		}
+		mgr.partSets = partSets
+	}
+
+	if slices.Contains(types, GameDataGeometry) {
+		clientUrl, exist := mgr.variables[keyFlashClientUrl]
+		if !exist {
+			err = fmt.Errorf("unable to load avatar geometry - failed to retrieve %s from external variables", keyFlashClientUrl)
+			return
+		}
+		version := path.Base(clientUrl)
+		filePath := filepath.Join(mgr.cacheDir, mgr.host, string(GameDataGeometry), version)
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, clientUrl+habboAvatarGeometryFilename, 0)
+		if err != nil {
+			return
+		}
+
+		var geometry AvatarGeometry
+		err = geometry.UnmarshalBytes(data)
+		if err != nil {
+			return
+		}
+		mgr.geometry = &geometry
+	}
+
+	if slices.Contains(types, GameDataEffectMap) {
+		clientUrl, exist := mgr.variables[keyFlashClientUrl]
+		if !exist {
+			err = fmt.Errorf("unable to load effect map - failed to retrieve %s from external variables", keyFlashClientUrl)
+			return
+		}
+		version := path.Base(clientUrl)
+		filePath := filepath.Join(mgr.cacheDir, mgr.host, string(GameDataEffectMap), version)
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, clientUrl+effectMapFilename, 0)
+		if err != nil {
+			return
+		}
+
+		var effectMap EffectMap
+		err = effectMap.UnmarshalBytes(data)
+		if err != nil {
+			return
+		}
+		mgr.effectMap = effectMap
+	}
+
 	return
 }
 
@@ -282,7 +402,7 @@ func (mgr *webGameDataManager) LoadFurni(libraries ...string) (err error) {
 	for _, identifier := range libraries {
 		fi, ok := mgr.furni[identifier]
 		if !ok {
-			err = fmt.Errorf("failed to find furni info for %q", identifier)
+			err = fmt.Errorf("failed to find furni info for %q: %w", identifier, ErrNotFound)
 			return
 		}
 
@@ -319,6 +439,10 @@ func (mgr *webGameDataManager) LoadFurni(libraries ...string) (err error) {
 }
 
 func (mgr *webGameDataManager) LoadFigureParts(libraries ...string) (err error) {
+	if mgr.nitroAssetBase != "" {
+		return mgr.loadFigurePartsNitro(libraries...)
+	}
+
 	if mgr.variables == nil {
 		err = fmt.Errorf("variables not loaded")
 		return
@@ -361,10 +485,145 @@ func (mgr *webGameDataManager) LoadFigureParts(libraries ...string) (err error)
 	return
 }
 
+// loadFigurePartsNitro loads figure part libraries from the Nitro asset base.
+func (mgr *webGameDataManager) loadFigurePartsNitro(libraries ...string) (err error) {
+	baseUrl := strings.TrimSuffix(mgr.nitroAssetBase, "/")
+
+	for _, libraryName := range libraries {
+		if mgr.assets.LibraryExists(libraryName) {
+			continue
+		}
+
+		filePath := filepath.Join(mgr.cacheDir, "nitro", "figure", libraryName+".nitro")
+		libraryUrl := baseUrl + "/bundled/figure/" + libraryName + ".nitro"
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, libraryUrl, 0)
+		if err != nil {
+			return
+		}
+
+		var archive nitro.Archive
+		archive, err = nitro.NewReader(bytes.NewReader(data)).ReadArchive()
+		if err != nil {
+			return
+		}
+
+		var lib res.AssetLibrary
+		lib, err = res.LoadFigureLibraryNitro(archive)
+		if err != nil {
+			return
+		}
+		mgr.assets.AddLibrary(lib)
+	}
+
+	return
+}
+
+func (mgr *webGameDataManager) LoadEffects(libraries ...string) (err error) {
+	if mgr.variables == nil {
+		err = fmt.Errorf("variables not loaded")
+		return
+	}
+
+	clientUrl, ok := mgr.variables[keyFlashClientUrl]
+	if !ok {
+		err = fmt.Errorf("failed to find client url in external variables")
+		return
+	}
+
+	for _, libraryName := range libraries {
+		if mgr.assets.LibraryExists(libraryName) {
+			continue
+		}
+
+		filePath := filepath.Join(mgr.cacheDir, "swf", "effect", libraryName+".swf")
+		libraryUrl := clientUrl + libraryName + ".swf"
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, libraryUrl, 0)
+		if err != nil {
+			return
+		}
+
+		var swf *swfx.Swf
+		swf, err = swfx.ReadSwf(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		var lib res.EffectLibrary
+		lib, err = res.LoadEffectLibrarySwf(swf)
+		if err != nil {
+			return
+		}
+		mgr.assets.AddLibrary(lib)
+	}
+
+	return
+}
+
+// LoadRoomContent loads the room content library.
+// The Nitro room library is loaded from the Nitro asset base if one is configured,
+// otherwise HabboRoomContent.swf is loaded from the Flash client URL.
+func (mgr *webGameDataManager) LoadRoomContent() (err error) {
+	if mgr.assets.LibraryExists(res.RoomContentLibrary) {
+		return
+	}
+
+	var lib res.RoomLibrary
+	if mgr.nitroAssetBase != "" {
+		baseUrl := strings.TrimSuffix(mgr.nitroAssetBase, "/")
+		filePath := filepath.Join(mgr.cacheDir, "nitro", "generic", "room.nitro")
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, baseUrl+"/bundled/generic/room.nitro", 0)
+		if err != nil {
+			return
+		}
+
+		var archive nitro.Archive
+		archive, err = nitro.NewReader(bytes.NewReader(data)).ReadArchive()
+		if err != nil {
+			return
+		}
+		lib, err = res.LoadRoomLibraryNitro(archive)
+	} else {
+		if mgr.variables == nil {
+			return fmt.Errorf("variables not loaded")
+		}
+		clientUrl, ok := mgr.variables[keyFlashClientUrl]
+		if !ok {
+			return fmt.Errorf("failed to find client url in external variables")
+		}
+
+		filePath := filepath.Join(mgr.cacheDir, "swf", "HabboRoomContent.swf")
+
+		var data []byte
+		data, err = mgr.fetchOrGetCached(filePath, clientUrl+"HabboRoomContent.swf", 0)
+		if err != nil {
+			return
+		}
+
+		var swf *swfx.Swf
+		swf, err = swfx.ReadSwf(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		lib, err = res.LoadRoomLibrarySwf(swf)
+	}
+	if err != nil {
+		return
+	}
+
+	mgr.assets.AddLibrary(lib)
+	return
+}
+
 func (mgr *webGameDataManager) GetHashes() (hashes *j.GameDataHashes, err error) {
 	if mgr.currentHashes != nil {
 		if lastFetched, ok := mgr.lastFetched[GameDataHashes]; ok {
-			if time.Since(lastFetched).Hours() < 4 {
+			if mgr.offline || time.Since(lastFetched) < mgr.hashesTTL {
 				hashes = mgr.currentHashes
 				return
 			}
@@ -373,12 +632,13 @@ func (mgr *webGameDataManager) GetHashes() (hashes *j.GameDataHashes, err error)
 	data, err := mgr.fetchOrGetCached(
 		filepath.Join(mgr.cacheDir, mgr.host, "hashes.json"),
 		"https://"+mgr.host+"/gamedata/hashes2",
-		time.Hour*4,
+		mgr.hashesTTL,
 	)
 	if err == nil {
 		err = json.Unmarshal(data, &hashes)
 		if err == nil {
 			mgr.currentHashes = hashes
+			mgr.lastFetched[GameDataHashes] = time.Now()
 		}
 	}
 	return
@@ -388,12 +648,20 @@ func (mgr *webGameDataManager) DownloadHash(hash j.GameDataHash) (data []byte, e
 	data, err = mgr.fetchOrGetCached(
 		filepath.Join(mgr.cacheDir, mgr.host, hash.Name, hash.Hash),
 		hash.Url+"/"+hash.Hash,
-		time.Hour*24*365,
+		mgr.blobTTL,
 	)
 	return
 }
 
 func (mgr *webGameDataManager) fetchOrGetCached(filePath string, url string, refetchThreshold time.Duration) (data []byte, err error) {
+	if mgr.offline {
+		data, err = os.ReadFile(filePath)
+		if (err == nil && len(data) == 0) || os.IsNotExist(err) {
+			err = fmt.Errorf("%w: %s is not cached at %s", ErrOffline, url, filePath)
+		}
+		return
+	}
+
 	dir := filepath.Dir(filePath)
 
 	err = os.MkdirAll(dir, 0755)
@@ -432,14 +700,26 @@ func (mgr *webGameDataManager) fetchOrGetCached(filePath string, url string, ref
 			return
 		}
 
-		var res *http.Response
+		var req *http.Request
+		req, err = http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+		if mgr.userAgent != "" {
+			req.Header.Set("User-Agent", mgr.userAgent)
+		}
 
-		res, err = mgr.client.Get(url)
+		var res *http.Response
+		res, err = mgr.client.Do(req)
 		if err != nil {
 			return
 		}
 		defer res.Body.Close()
 
+		if res.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %s", ErrNotFound, url)
+			return
+		}
 		if res.StatusCode != http.StatusOK {
 			err = fmt.Errorf("server responded %s", res.Status)
 			return
@@ -0,0 +1,127 @@
+package gamedata
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testHost      = "www.habbo.test"
+	testFurniData = `{"roomitemtypes":{"furnitype":[{"id":1,"classname":"chair","revision":1,"name":"Chair"}]},"wallitemtypes":{"furnitype":[]}}`
+	testHashes    = `{"hashes":[{"name":"furnidata","url":"https://www.habbo.test/gamedata/furnidata_json/1","hash":"abc"}]}`
+)
+
+type testTransport struct {
+	agents   []string
+	requests int
+}
+
+func (rt *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests++
+	rt.agents = append(rt.agents, req.Header.Get("User-Agent"))
+	body := testFurniData
+	if strings.HasSuffix(req.URL.Path, "/hashes2") {
+		body = testHashes
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestManagerOptions(t *testing.T) {
+	cacheDir := t.TempDir()
+	transport := &testTransport{}
+
+	mgr := NewManager(testHost,
+		WithCacheDir(cacheDir),
+		WithTransport(transport),
+		WithUserAgent("nx-test"),
+	)
+	err := mgr.Load(GameDataFurni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 2 {
+		t.Fatalf("made %d requests (expected 2)", transport.requests)
+	}
+	for _, agent := range transport.agents {
+		if agent != "nx-test" {
+			t.Fatalf("user agent is %q (expected %q)", agent, "nx-test")
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, testHost, string(GameDataFurni), "abc")); err != nil {
+		t.Fatalf("furni data was not cached: %v", err)
+	}
+
+	// Offline mode loads from the cache only.
+	offline := NewManager(testHost, WithCacheDir(cacheDir), WithOffline(true),
+		WithTransport(transport), WithHashesTTL(0))
+	err = offline.Load(GameDataFurni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 2 {
+		t.Fatal("offline manager made a request")
+	}
+	if _, ok := offline.Furni()["chair"]; !ok {
+		t.Fatal("furni data not loaded from cache")
+	}
+
+	empty := NewManager(testHost, WithCacheDir(t.TempDir()), WithOffline(true))
+	err = empty.Load(GameDataFurni)
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+}
+
+type funcTransport func(req *http.Request) (*http.Response, error)
+
+func (rt funcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req)
+}
+
+func TestManagerLoadDefaultTypes(t *testing.T) {
+	hashes := `{"hashes":[{"name":"external_variables","url":"https://www.habbo.test/gamedata/external_variables/1","hash":"abc"}]}`
+	var paths []string
+	transport := funcTransport(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		body := ""
+		switch path := req.URL.Path; {
+		case strings.HasSuffix(path, "/hashes2"):
+			body = hashes
+		case strings.HasSuffix(path, "/external_variables/1/abc"):
+			body = "flash.client.url=https://images.habbo.test/gordon/flash-1/\n"
+		case strings.HasSuffix(path, "/figuremap.xml"):
+			body = "<map></map>"
+		case strings.HasSuffix(path, "/"+habboAvatarActionsFilename):
+			body = "<actions></actions>"
+		default:
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found",
+				Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK",
+			Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})
+
+	mgr := NewManager(testHost, WithCacheDir(t.TempDir()), WithTransport(transport))
+	if err := mgr.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !mgr.Loaded(GameDataVariables, GameDataFigureMap, GameDataAvatar) {
+		t.Fatal("default game data types should be loaded")
+	}
+	if mgr.Loaded(GameDataGeometry) || mgr.Loaded(GameDataEffectMap) || mgr.Loaded(GameDataPartSets) {
+		t.Fatal("geometry, effect map and part sets should only be loaded when requested")
+	}
+	if len(paths) != 4 {
+		t.Fatalf("made requests to %v (expected 4 requests)", paths)
+	}
+}
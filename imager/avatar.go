@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
 	"slices"
 	"strconv"
 
@@ -43,12 +44,16 @@ type Avatar struct {
 	nx.Figure
 	Direction     int
 	HeadDirection int
-	Actions       []nx.AvatarState
-	Expression    nx.AvatarState
-	HandItem      int
-	Effect        int
-	Sign          int
-	HeadOnly      bool
+	// Actions contains the actions performed by the avatar, e.g. sit and wave.
+	// Each figure part takes the asset of the highest precedence action that affects it.
+	// If empty, the default action is used.
+	Actions    []nx.AvatarState
+	Expression nx.AvatarState
+	HandItem   int
+	Effect     int
+	Sign       int
+	HeadOnly   bool
+	Blink      bool // Blink configures whether the eyes of the avatar blink when animated.
 }
 
 var bodyLayers = []nx.FigurePartType{
@@ -123,6 +128,11 @@ func flipDir(dir int) int {
 	return (6 - dir) % 8
 }
 
+// rotateDir rotates a direction by the specified offset.
+func rotateDir(dir, offset int) int {
+	return ((dir+offset)%8 + 8) % 8
+}
+
 type avatarImager struct {
 	mgr gd.Manager
 }
@@ -235,6 +245,84 @@ func (imgr avatarImager) Parts(fig nx.Figure) (parts []AvatarPart, err error) {
 	return
 }
 
+// handItemLibrary is the name of the figure library containing hand item and sign assets.
+const handItemLibrary = "hh_human_item"
+
+// MaxSign is the highest sign ID that can be shown by an avatar.
+const MaxSign = 17
+
+// AvatarParts converts the specified avatar into individual parts,
+// including the figure parts, the avatar's hand item and sign, if it has them.
+func (imgr avatarImager) AvatarParts(avatar Avatar) (parts []AvatarPart, err error) {
+	parts, err = imgr.Parts(avatar.Figure)
+	if err != nil {
+		return
+	}
+	if part, ok := imgr.handItemPart(avatar); ok {
+		parts = append(parts, part)
+	}
+	if part, ok := imgr.signPart(avatar); ok {
+		parts = append(parts, part)
+	}
+	return
+}
+
+// handItemPart gets the right hand item part of the avatar.
+// Returns false if the avatar has no hand item, or is not carrying or drinking.
+func (imgr avatarImager) handItemPart(avatar Avatar) (part AvatarPart, ok bool) {
+	if avatar.HandItem <= 0 {
+		return
+	}
+	// The item is resolved through the params of the action that is holding it,
+	// as the carry and drink actions may map the same item to different assets.
+	var action nx.AvatarState
+	switch {
+	case slices.Contains(avatar.Actions, nx.ActDrink):
+		action = nx.ActDrink
+	case slices.Contains(avatar.Actions, nx.ActCarry):
+		action = nx.ActCarry
+	default:
+		return
+	}
+	return imgr.itemPart(action, nx.RightHandItem, avatar.HandItem), true
+}
+
+// signPart gets the sign part held in the left hand of the avatar.
+// Returns false if the avatar is not showing a sign.
+func (imgr avatarImager) signPart(avatar Avatar) (part AvatarPart, ok bool) {
+	if !slices.Contains(avatar.Actions, nx.ActSign) || avatar.Sign < 0 || avatar.Sign > MaxSign {
+		return
+	}
+	return imgr.itemPart(nx.ActSign, nx.LeftHandItem, avatar.Sign), true
+}
+
+// itemPart creates a hand item part of the specified type.
+// The parameters of the action may map item IDs to a different asset ID.
+func (imgr avatarImager) itemPart(action nx.AvatarState, partType nx.FigurePartType, itemId int) AvatarPart {
+	if info := imgr.mgr.AvatarActions().ByAssetPart(string(action)); info != nil {
+		if param, exists := info.Params[strconv.Itoa(itemId)]; exists {
+			if id, err := strconv.Atoi(param); err == nil {
+				itemId = id
+			}
+		}
+	}
+
+	part := AvatarPart{
+		LibraryName: handItemLibrary,
+		SetType:     partType,
+		SetId:       itemId,
+		Type:        partType,
+		Id:          itemId,
+		Color:       color.White,
+	}
+	if figureMap := imgr.mgr.FigureMap(); figureMap != nil {
+		if lib, exists := figureMap.Parts[nx.FigurePart{Type: partType, Id: itemId}]; exists {
+			part.LibraryName = lib.Name
+		}
+	}
+	return part
+}
+
 // Finds the required figure part libraries given the specified Figure.
 func (imgr avatarImager) RequiredLibs(fig nx.Figure) (libs []string, err error) {
 	figureData := imgr.mgr.Figure()
@@ -275,22 +363,31 @@ func (imgr avatarImager) RequiredLibs(fig nx.Figure) (libs []string, err error)
 
 // Compose composes an avatar into an animation.
 func (imgr avatarImager) Compose(avatar Avatar) (anim Animation, err error) {
-	parts, err := imgr.Parts(avatar.Figure)
+	effectLib, err := imgr.effectLibrary(avatar)
 	if err != nil {
 		return
 	}
+	var effect *res.EffectAnimation
+	if effectLib != nil {
+		effect = effectLib.Animation()
+		// The effect may turn the avatar to face another direction.
+		avatar.Direction = rotateDir(avatar.Direction, effect.DirectionOffset)
+		avatar.HeadDirection = rotateDir(avatar.HeadDirection, effect.DirectionOffset)
+	}
 
-	// Choose a layer ordering based on figure direction.
-	var ordering map[nx.FigurePartType]int
-	switch avatar.Direction {
-	case 0, 1, 2, 4, 5, 6:
-		ordering = mapPrecedence(sliceJoin(layerOrderSide...))
-	case 3:
-		ordering = mapPrecedence(sliceJoin(layerOrderDown...))
-	case 7:
-		ordering = mapPrecedence(sliceJoin(layerOrderUp...))
+	actions := imgr.activeActions(avatar)
+	if slices.ContainsFunc(actions, func(action *gd.AvatarActionInfo) bool { return action.PreventHeadTurn }) {
+		avatar.HeadDirection = avatar.Direction
+	}
+	actionOffset := avatarActionOffset(actions, avatar.Direction)
+
+	parts, err := imgr.AvatarParts(avatar)
+	if err != nil {
+		return
 	}
 
+	ordering := imgr.layerOrdering(avatar)
+
 	// Groups parts by part type.
 	partMap := map[nx.FigurePartType][]AvatarPart{}
 	for _, part := range parts {
@@ -310,12 +407,16 @@ func (imgr avatarImager) Compose(avatar Avatar) (anim Animation, err error) {
 	// The flipped right arm must be removed and replaced with a flipped left arm asset.
 	// The left arm asset must also be moved to the right arm layer so that it is ordered correctly.
 
-	type partExtra struct {
+	type partFrame struct {
 		Spec   FigureAssetSpec
 		Asset  *res.Asset
-		Order  int
 		Offset image.Point
-		FlipH  bool
+	}
+	type partExtra struct {
+		Frames   []partFrame
+		Sequence res.FrameSequence
+		Order    int
+		FlipH    bool
 	}
 	partExtraData := map[nx.FigurePart]partExtra{}
 
@@ -326,6 +427,10 @@ func (imgr avatarImager) Compose(avatar Avatar) (anim Animation, err error) {
 			part.Hidden = true
 		}
 
+		if effect != nil && effectRemovesPart(effect, part.Type) {
+			part.Hidden = true
+		}
+
 		if part.Hidden {
 			continue
 		}
@@ -350,29 +455,47 @@ func (imgr avatarImager) Compose(avatar Avatar) (anim Animation, err error) {
 			continue
 		}
 
-		var asset *res.Asset
-		asset, err = lib.Asset(spec.String())
-		if err != nil {
-			return
+		specs, sequence := imgr.resolveFrames(lib, avatar, *part, *spec)
+		var effectOffsets []image.Point
+		if effect != nil {
+			if bodyPart, ok := effectBodyPart(part.Type); ok {
+				specs, effectOffsets, sequence = applyEffectFrames(lib, effect, bodyPart, specs, sequence, flipPart)
+			}
 		}
 
-		offset := asset.Offset
-		if flipPart {
-			offset.X = offset.X*-1 + asset.Image.Bounds().Dx() - 64
-			if !flipAvatar && isHead {
-				offset.X -= 3
-			}
-		} else if flipAvatar && isHead {
-			offset.X += 3
+		extra := partExtra{
+			Sequence: sequence,
+			Order:    ordering[part.Type],
+			FlipH:    flipPart,
 		}
+		for i, spec := range specs {
+			var asset *res.Asset
+			asset, err = lib.Asset(spec.String())
+			if err != nil {
+				return
+			}
+
+			offset := asset.Offset
+			if flipPart {
+				offset.X = offset.X*-1 + asset.SourceImage().Bounds().Dx() - 64
+				if !flipAvatar && isHead {
+					offset.X -= 3
+				}
+			} else if flipAvatar && isHead {
+				offset.X += 3
+			}
+			if i < len(effectOffsets) {
+				offset = offset.Sub(effectOffset(effectOffsets[i], flipAvatar))
+			}
+			offset = offset.Sub(actionOffset)
 
-		partExtraData[nx.FigurePart{Type: part.Type, Id: part.Id}] = partExtra{
-			Asset:  asset,
-			Spec:   *spec,
-			Order:  ordering[part.Type],
-			Offset: offset,
-			FlipH:  flipPart,
+			extra.Frames = append(extra.Frames, partFrame{
+				Spec:   spec,
+				Asset:  asset,
+				Offset: offset,
+			})
 		}
+		partExtraData[nx.FigurePart{Type: part.Type, Id: part.Id}] = extra
 	}
 
 	slices.SortFunc(parts, func(a, b AvatarPart) int {
@@ -386,70 +509,201 @@ func (imgr avatarImager) Compose(avatar Avatar) (anim Animation, err error) {
 	})
 
 	// Convert parts into sprites
-	anim = Animation{
-		Layers: map[int]AnimationLayer{},
-	}
-
-	layerId := 0
+	var partTypes []nx.FigurePartType
+	var partLayers []AnimationLayer
 	for _, part := range parts {
 		if part.Hidden {
 			continue
 		}
 		extra := partExtraData[nx.FigurePart{Type: part.Type, Id: part.Id}]
 
-		anim.Layers[layerId] = AnimationLayer{
-			Frames: map[int]Frame{
-				0: {
-					Sprite{
-						Asset:  extra.Asset,
-						Offset: extra.Offset,
-						Color:  part.Color,
-						FlipH:  extra.FlipH,
-						Alpha:  255,
-					},
+		layer := AnimationLayer{
+			Frames: map[int]Frame{},
+		}
+		for frameId, frame := range extra.Frames {
+			layer.Frames[frameId] = Frame{
+				Sprite{
+					Asset:  frame.Asset,
+					Offset: frame.Offset,
+					Color:  part.Color,
+					FlipH:  extra.FlipH,
+					Alpha:  255,
 				},
-			},
+			}
+		}
+		if len(extra.Sequence) > 1 {
+			layer.Sequences = []res.FrameSequence{extra.Sequence}
+		}
+		partTypes = append(partTypes, part.Type)
+		partLayers = append(partLayers, layer)
+	}
+
+	layers := partLayers
+	if effect != nil {
+		var sprites []effectLayer
+		sprites, err = composeEffectSprites(effectLib, avatar)
+		if err != nil {
+			return
+		}
+		layers = arrangeEffectLayers(effect, partTypes, partLayers, sprites)
+	}
+
+	anim = Animation{
+		Layers: map[int]AnimationLayer{},
+	}
+	for layerId, layer := range layers {
+		anim.Layers[layerId] = layer
+	}
+
+	return
+}
+
+// layerOrdering gets the draw order of each figure part type for the avatar.
+// If the avatar geometry is loaded, the order is computed from the geometry type of the avatar's main action
+// and its body and head directions. Otherwise, a fixed ordering is chosen based on the body direction.
+func (imgr avatarImager) layerOrdering(avatar Avatar) map[nx.FigurePartType]int {
+	if geometry := imgr.mgr.AvatarGeometry(); geometry != nil {
+		geometryType := defaultGeometryType
+		for _, action := range imgr.activeActions(avatar) {
+			if action.Main && action.GeometryType != "" {
+				geometryType = action.GeometryType
+				break
+			}
+		}
+		// Mirrored directions use the flipped assets of the opposite direction,
+		// so they are ordered as if facing that direction.
+		bodyDir, headDir := avatar.Direction, avatar.HeadDirection
+		if isMirrored(bodyDir) {
+			bodyDir = flipDir(bodyDir)
+		}
+		if isMirrored(headDir) {
+			headDir = flipDir(headDir)
+		}
+		order := geometry.PartOrder(geometryType, bodyDir, headDir)
+		if order == nil {
+			order = geometry.PartOrder(defaultGeometryType, bodyDir, headDir)
+		}
+		if order != nil {
+			return mapPrecedence(order)
+		}
+	}
+
+	switch avatar.Direction {
+	case 3:
+		return mapPrecedence(sliceJoin(layerOrderDown...))
+	case 7:
+		return mapPrecedence(sliceJoin(layerOrderUp...))
+	default:
+		return mapPrecedence(sliceJoin(layerOrderSide...))
+	}
+}
+
+// avatarScale is the avatar scale of the action offsets applied when composing an avatar.
+const avatarScale = "h"
+
+// avatarActionOffset gets the canvas offset of the highest priority action that defines one for the specified direction,
+// e.g. the offset of the avatar while laying down.
+func avatarActionOffset(actions []*gd.AvatarActionInfo, dir int) image.Point {
+	for _, action := range actions {
+		if offset, ok := action.Offset(avatarScale, dir); ok {
+			return image.Pt(offset.X, offset.Y)
+		}
+	}
+	return image.Point{}
+}
+
+// defaultGeometryType is the avatar geometry type used when no main action defines one.
+const defaultGeometryType = "vertical"
+
+// maxAvatarFrames is the maximum number of animation frames of a single figure part asset.
+const maxAvatarFrames = 16
+
+// blinkSequenceLength is the number of frames in an eye blink sequence.
+// The eyes are closed during the last frame of the sequence.
+const blinkSequenceLength = 8
+
+// resolveFrames resolves the animation frames of a figure part, given the asset resolved for its first frame.
+// It returns the asset specs of each frame, along with the sequence of indexes into the specs to animate.
+func (imgr avatarImager) resolveFrames(lib res.AssetLibrary, avatar Avatar, part AvatarPart, spec FigureAssetSpec) (specs []FigureAssetSpec, sequence res.FrameSequence) {
+	// Walking and waving assets have a frame for each step in the animation,
+	// e.g. the walk cycle of the body has 4 frames, and the waving arm has 2.
+	specs = []FigureAssetSpec{spec}
+	for frame := 1; frame < maxAvatarFrames; frame++ {
+		next := spec
+		next.Frame = frame
+		if !lib.AssetExists(next.String()) {
+			break
+		}
+		specs = append(specs, next)
+	}
+
+	// The mouth alternates between the speaking and neutral assets
+	// if there is only a single speaking frame.
+	if len(specs) == 1 && (spec.State == nx.ExprSpeak || spec.State == nx.ExprSpeakLay) {
+		neutral := avatar
+		neutral.Expression = ""
+		if alt := imgr.ResolveAsset(lib, neutral, part); alt != nil && *alt != spec {
+			specs = append(specs, *alt)
+		}
+	}
+
+	for i := range specs {
+		sequence = append(sequence, i)
+	}
+
+	// The eyes close for a single frame at the end of the blink sequence.
+	if avatar.Blink && len(specs) == 1 && (part.Type == nx.Eyes || part.Type == nx.Face) {
+		switch avatar.Expression {
+		case "", nx.ExprNeutral, nx.ExprSpeak, nx.ExprSmile:
+			blink := spec
+			blink.State = nx.ExprSleep
+			if lib.AssetExists(blink.String()) {
+				specs = append(specs, blink)
+				sequence = make(res.FrameSequence, blinkSequenceLength)
+				sequence[blinkSequenceLength-1] = 1
+			}
 		}
-		layerId++
 	}
 
 	return
 }
 
+// ResolveAsset resolves the asset of a figure part for the first frame of the avatar's animation.
+// The states to try are selected from the avatar's expression and its actions in order of precedence,
+// followed by the default action. Only actions that affect the part's type are considered.
+// Returns nil if no asset exists for the part.
 func (r *avatarImager) ResolveAsset(lib res.AssetLibrary, avatar Avatar, part AvatarPart) *FigureAssetSpec {
 	direction := avatar.Direction
 	if part.Type.IsHead() {
 		direction = avatar.HeadDirection
 	}
-	action := avatar.Actions[0]
-	expression := avatar.Expression
 
 	directions := []int{direction}
 	if isMirrored(direction) {
 		directions = append(directions, flipDir(direction))
 	}
 
-	states := []nx.AvatarState{}
+	actions := r.activeActions(avatar)
 
-	if part.Type.IsHead() {
+	states := []nx.AvatarState{}
+	if part.Type.IsHead() && avatar.Expression != "" {
+		expression := avatar.Expression
+		// Expressions have separate assets while laying down.
+		if isLaying(actions) {
+			expression = r.layExpression(expression)
+		}
 		states = append(states, expression)
-		if action == nx.ActLay {
-			states = append(states, nx.ActLay)
-		} else {
-			states = append(states, nx.ActStand)
+	}
+	partSets := r.mgr.AvatarPartSets()
+	for _, action := range actions {
+		if partSets.Affects(action, part.Type) {
+			states = append(states, nx.AvatarState(action.AssetPartDefinition))
 		}
+	}
+	if defaultAction := r.mgr.AvatarActions().Default(); defaultAction != nil {
+		states = append(states, nx.AvatarState(defaultAction.AssetPartDefinition))
 	} else {
-		states = append(states, action)
-		switch action {
-		case nx.ActWalk, nx.ActWave, nx.ActSit, nx.ActDrink:
-			states = append(states, nx.ActStand)
-		case nx.ActRespect, nx.ActCarry:
-			states = append(states, nx.ActWave, nx.ActStand)
-		case nx.ActBlowKiss:
-			states = append(states, nx.ActDrink, nx.ActStand)
-		case nx.ActSign:
-			states = append(states, nx.ActWave, nx.ActStand)
-		}
+		states = append(states, nx.ActStand)
 	}
 
 	for _, d := range directions {
@@ -466,6 +720,62 @@ func (r *avatarImager) ResolveAsset(lib res.AssetLibrary, avatar Avatar, part Av
 	return nil
 }
 
+// layExpression gets the equivalent of an expression while laying down.
+// The lay state of the expression's action definition is used if it exists,
+// otherwise it falls back to the known lay expressions.
+func (r *avatarImager) layExpression(expression nx.AvatarState) nx.AvatarState {
+	if info := r.mgr.AvatarActions().ByAssetPart(string(expression)); info != nil && info.Lay != "" {
+		return nx.AvatarState(info.Lay)
+	}
+	if layExpression, ok := layExpressions[expression]; ok {
+		return layExpression
+	}
+	return expression
+}
+
+// layExpressions maps expressions to their equivalent while laying down.
+var layExpressions = map[nx.AvatarState]nx.AvatarState{
+	nx.ExprSpeak:     nx.ExprSpeakLay,
+	nx.ExprSleep:     nx.ExprSleepLay,
+	nx.ExprSad:       nx.ExprSadLay,
+	nx.ExprSmile:     nx.ExprSmileLay,
+	nx.ExprAngry:     nx.ExprAngryLay,
+	nx.ExprSurprised: nx.ExprSurprisedLay,
+}
+
+// isLaying reports whether the highest priority main action uses the horizontal geometry.
+func isLaying(actions []*gd.AvatarActionInfo) bool {
+	for _, action := range actions {
+		if action.Main || action.AssetPartDefinition == string(nx.ActLay) {
+			return action.GeometryType == "horizontal" || action.AssetPartDefinition == string(nx.ActLay)
+		}
+	}
+	return false
+}
+
+// activeActions gets the definitions of the avatar's actions sorted by precedence,
+// excluding any actions that are prevented by another action.
+// Actions without a definition in the avatar actions are assumed to affect the entire figure,
+// and take the lowest priority in the order they are specified.
+func (r *avatarImager) activeActions(avatar Avatar) []*gd.AvatarActionInfo {
+	actions := r.mgr.AvatarActions()
+	active := make([]*gd.AvatarActionInfo, 0, len(avatar.Actions))
+	for i, state := range avatar.Actions {
+		info := actions.ByAssetPart(string(state))
+		if info == nil {
+			info = &gd.AvatarActionInfo{
+				Id:                  string(state),
+				State:               string(state),
+				Precedence:          math.MaxInt - len(avatar.Actions) + i,
+				ActivePartSet:       "figure",
+				AssetPartDefinition: string(state),
+			}
+		}
+		active = append(active, info)
+	}
+	return actions.Sorted(active)
+}
+
 type FigureAssetSpec struct {
 	State nx.AvatarState
 	Type  nx.FigurePartType
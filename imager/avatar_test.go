@@ -0,0 +1,50 @@
+package imager
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+)
+
+const testAvatarActions = `<actions>
+	<action id="Default" state="std" precedence="1000" main="1" isdefault="1" geometrytype="vertical" activepartset="figure" assetpartdefinition="std"/>
+	<action id="Sit" state="sit" precedence="900" main="1" geometrytype="sitting" activepartset="sit" assetpartdefinition="sit"/>
+	<action id="CarryItem" state="cri" precedence="100" activepartset="handRight" assetpartdefinition="crr"/>
+</actions>`
+
+// newTestManager creates a game data manager with the test avatar actions loaded.
+func newTestManager(t *testing.T) gd.Manager {
+	t.Helper()
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "HabboAvatarActions.xml"), []byte(testAvatarActions), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := gd.NewDirManager(dir, gd.FlashLayout)
+	if err := mgr.Load(gd.GameDataAvatar); err != nil {
+		t.Fatal(err)
+	}
+	return mgr
+}
+
+func TestActiveActionsUndefinedPrecedence(t *testing.T) {
+	imgr := &avatarImager{newTestManager(t)}
+	actions := imgr.activeActions(Avatar{
+		Actions: []nx.AvatarState{nx.ActSit, nx.ActWave, nx.ActCarry},
+	})
+
+	var states []string
+	for _, action := range actions {
+		states = append(states, action.AssetPartDefinition)
+	}
+	if len(states) != 3 || states[0] != "crr" || states[1] != "sit" || states[2] != "wav" {
+		t.Fatalf("unexpected action order: %v", states)
+	}
+	if wave := actions[2]; wave.Precedence < math.MaxInt-3 || !imgr.mgr.AvatarPartSets().Affects(wave, nx.LeftHand) {
+		t.Fatalf("undefined action should affect the figure with the lowest priority: %+v", wave)
+	}
+}
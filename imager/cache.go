@@ -0,0 +1,123 @@
+package imager
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache represents a store for encoded images, keyed by a string that uniquely identifies a render.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, data []byte)
+}
+
+type memoryCache struct {
+	mtx        sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type memoryCacheEntry struct {
+	key  string
+	data []byte
+}
+
+// NewMemoryCache creates an in-memory cache that holds up to maxEntries renders.
+// When the cache is full, the least recently used entry is evicted.
+func NewMemoryCache(maxEntries int) Cache {
+	return &memoryCache{
+		maxEntries: max(1, maxEntries),
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (c *memoryCache) Get(key string) (data []byte, ok bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(e)
+		data = e.Value.(*memoryCacheEntry).data
+	}
+	return
+}
+
+func (c *memoryCache) Set(key string, data []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*memoryCacheEntry).data = data
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{key, data})
+	for c.order.Len() > c.maxEntries {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.entries, e.Value.(*memoryCacheEntry).key)
+	}
+}
+
+type diskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a cache that stores renders as files within the specified directory.
+func NewDiskCache(dir string) Cache {
+	return diskCache{dir}
+}
+
+func (c diskCache) path(key string) string {
+	hash := sha1.Sum([]byte(key))
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+func (c diskCache) Get(key string) (data []byte, ok bool) {
+	data, err := os.ReadFile(c.path(key))
+	return data, err == nil
+}
+
+func (c diskCache) Set(key string, data []byte) {
+	filePath := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return
+	}
+	os.WriteFile(filePath, data, 0644)
+}
+
+type layeredCache []Cache
+
+// NewLayeredCache creates a cache that reads from each of the specified caches in order.
+// Entries found in a later cache are copied into the earlier caches.
+// Entries are written to all caches.
+func NewLayeredCache(caches ...Cache) Cache {
+	return layeredCache(caches)
+}
+
+func (caches layeredCache) Get(key string) (data []byte, ok bool) {
+	for i, c := range caches {
+		if data, ok = c.Get(key); ok {
+			for j := range i {
+				caches[j].Set(key, data)
+			}
+			return
+		}
+	}
+	return
+}
+
+func (caches layeredCache) Set(key string, data []byte) {
+	for _, c := range caches {
+		c.Set(key, data)
+	}
+}
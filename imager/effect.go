@@ -0,0 +1,284 @@
+package imager
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"strconv"
+
+	"xabbo.io/nx"
+	"xabbo.io/nx/res"
+)
+
+// effectBodyParts maps the body parts referenced by effect animations to the figure part types they contain.
+var effectBodyParts = map[string][]nx.FigurePartType{
+	"head":      headLayers,
+	"torso":     bodyLayers,
+	"leftarm":   leftArmLayers,
+	"rightarm":  rightArmLayers,
+	"lefthand":  {nx.LeftHandItem},
+	"righthand": {nx.RightHandItem},
+}
+
+// effectInkAdd is the ink of effect sprites drawn with additive blending.
+const effectInkAdd = 33
+
+// effectAlignBottom is the alignment of an added body part drawn behind its base body part.
+const effectAlignBottom = "bottom"
+
+// An effectLayer is an animation layer composed from an effect sprite.
+type effectLayer struct {
+	AnimationLayer
+	Id string // The ID of the effect sprite.
+	Z  int    // The Z offset of the sprite for the avatar's direction.
+}
+
+// effectLibrary gets the effect library for the avatar's effect.
+// Returns nil if the avatar has no effect.
+func (imgr avatarImager) effectLibrary(avatar Avatar) (lib res.EffectLibrary, err error) {
+	if avatar.Effect <= 0 {
+		return
+	}
+	effectMap := imgr.mgr.EffectMap()
+	if effectMap == nil {
+		err = fmt.Errorf("effect map not loaded")
+		return
+	}
+	info, ok := effectMap[avatar.Effect]
+	if !ok {
+		err = fmt.Errorf("effect not found: %d", avatar.Effect)
+		return
+	}
+	lib, ok = imgr.mgr.Library(info.Library).(res.EffectLibrary)
+	if !ok {
+		err = fmt.Errorf("required effect library not loaded: %q", info.Library)
+	}
+	return
+}
+
+// effectBodyPart gets the effect body part containing the specified figure part type.
+func effectBodyPart(partType nx.FigurePartType) (bodyPart string, ok bool) {
+	for bodyPart, partTypes := range effectBodyParts {
+		if slices.Contains(partTypes, partType) {
+			return bodyPart, true
+		}
+	}
+	return
+}
+
+// effectRemovesPart reports whether the effect removes the specified figure part type,
+// either by its part type or by the body part that contains it.
+func effectRemovesPart(effect *res.EffectAnimation, partType nx.FigurePartType) bool {
+	bodyPart, _ := effectBodyPart(partType)
+	return slices.ContainsFunc(effect.Removes, func(id string) bool {
+		return id == string(partType) || id == bodyPart
+	})
+}
+
+// effectOffset converts an effect offset into a sprite offset, mirroring it if the avatar is flipped.
+func effectOffset(offset image.Point, flip bool) image.Point {
+	if flip {
+		offset.X = -offset.X
+	}
+	return offset
+}
+
+// applyEffectFrames replaces the frames of a figure part with those defined by the effect's animation.
+// Each effect frame may override the action, frame and direction of the body part and offset it.
+// The direction offset is reversed if the part is flipped, as its assets face the opposite way.
+// If the effect does not animate the body part, the specs and sequence are returned unchanged.
+func applyEffectFrames(lib res.AssetLibrary, effect *res.EffectAnimation, bodyPart string,
+	specs []FigureAssetSpec, sequence res.FrameSequence, flip bool,
+) ([]FigureAssetSpec, []image.Point, res.FrameSequence) {
+	animated := slices.ContainsFunc(effect.Frames, func(frame res.EffectFrame) bool {
+		_, ok := frame.BodyParts[bodyPart]
+		return ok
+	})
+	if !animated {
+		return specs, nil, sequence
+	}
+
+	base := specs[0]
+	newSpecs := make([]FigureAssetSpec, 0, len(effect.Frames))
+	offsets := make([]image.Point, 0, len(effect.Frames))
+	newSequence := res.FrameSequence{}
+	for i, frame := range effect.Frames {
+		spec := base
+		var offset image.Point
+		if override, ok := frame.BodyParts[bodyPart]; ok {
+			if override.Action != "" {
+				spec.State = nx.AvatarState(override.Action)
+			}
+			spec.Frame = override.Frame
+			if dirOffset := override.DirectionOffset; dirOffset != 0 {
+				if flip {
+					dirOffset = -dirOffset
+				}
+				spec.Dir = rotateDir(spec.Dir, dirOffset)
+			}
+			offset = override.Offset
+			// Fall back to the resolved asset if the effect's asset doesn't exist for this part.
+			if !lib.AssetExists(spec.String()) {
+				spec = base
+			}
+		}
+		newSpecs = append(newSpecs, spec)
+		offsets = append(offsets, offset)
+		for range max(1, frame.Repeats) {
+			newSequence = append(newSequence, i)
+		}
+	}
+	return newSpecs, offsets, newSequence
+}
+
+// effectSpriteAssetName gets the asset name of an effect sprite.
+func effectSpriteAssetName(action, member string, dir, frame int) string {
+	return "h_" + action + "_" + member + "_" + strconv.Itoa(dir) + "_" + strconv.Itoa(frame)
+}
+
+// composeEffectSprites composes the sprites of an effect into animation layers.
+// Directional sprites are rotated by the direction offset of each frame.
+func composeEffectSprites(lib res.EffectLibrary, avatar Avatar) (layers []effectLayer, err error) {
+	effect := lib.Animation()
+
+	for _, sprite := range effect.Sprites {
+		frames := effect.Frames
+		if len(frames) == 0 {
+			frames = []res.EffectFrame{{}}
+		}
+
+		layer := AnimationLayer{Frames: map[int]Frame{}}
+		sequence := res.FrameSequence{}
+		for i, frame := range frames {
+			state := res.EffectFramePart{Action: string(nx.ActStand)}
+			if fx, ok := frame.Fx[sprite.Id]; ok {
+				state = fx
+				if state.Action == "" {
+					state.Action = string(nx.ActStand)
+				}
+			}
+
+			dir := rotateDir(avatar.Direction, state.DirectionOffset)
+			flipSprite := isMirrored(dir)
+			dirs := []int{0}
+			if sprite.Directional {
+				dirs = []int{dir}
+				if flipSprite {
+					dirs = append(dirs, flipDir(dir))
+				}
+			}
+
+			var asset *res.Asset
+			flip := false
+			for _, d := range dirs {
+				name := effectSpriteAssetName(state.Action, sprite.Member, d, state.Frame)
+				if lib.AssetExists(name) {
+					asset, err = lib.Asset(name)
+					if err != nil {
+						return
+					}
+					flip = d != dir
+					break
+				}
+			}
+			if asset != nil {
+				offset := asset.Offset
+				if flip {
+					offset.X = offset.X*-1 + asset.SourceImage().Bounds().Dx() - 64
+				}
+				dirOffset := sprite.Offsets[avatar.Direction]
+				offset = offset.Sub(effectOffset(state.Offset.Add(image.Pt(dirOffset.Dx, dirOffset.Dy)), isMirrored(avatar.Direction)))
+
+				blend := BlendNone
+				if sprite.Ink == effectInkAdd {
+					blend = BlendAdd
+				}
+				layer.Frames[i] = Frame{Sprite{
+					Asset:  asset,
+					Offset: offset,
+					FlipH:  flip,
+					Blend:  blend,
+					Alpha:  255,
+				}}
+			} else {
+				layer.Frames[i] = Frame{}
+			}
+			for range max(1, frame.Repeats) {
+				sequence = append(sequence, i)
+			}
+		}
+		if len(sequence) > 1 {
+			layer.Sequences = []res.FrameSequence{sequence}
+		}
+
+		layers = append(layers, effectLayer{
+			AnimationLayer: layer,
+			Id:             sprite.Id,
+			Z:              sprite.Offsets[avatar.Direction].Dz,
+		})
+	}
+	return
+}
+
+// arrangeEffectLayers arranges the layers of an effect's sprites around the layers of the avatar's figure parts.
+// The shadow sprite is drawn behind all other layers.
+// Sprites added as a body part are drawn in front of the last figure part of their base body part,
+// or behind its first figure part if they are aligned to the bottom.
+// All other sprites are drawn behind the avatar if their Z offset is negative, otherwise in front of it.
+func arrangeEffectLayers(effect *res.EffectAnimation, partTypes []nx.FigurePartType,
+	partLayers []AnimationLayer, sprites []effectLayer,
+) (layers []AnimationLayer) {
+	before := make([][]AnimationLayer, len(partLayers))
+	after := make([][]AnimationLayer, len(partLayers))
+	var shadow, behind, front []AnimationLayer
+	for _, sprite := range sprites {
+		if effect.Shadow != "" && sprite.Id == effect.Shadow {
+			shadow = append(shadow, sprite.AnimationLayer)
+			continue
+		}
+		if index, bottom, ok := effectAddIndex(effect, sprite.Id, partTypes); ok {
+			if bottom {
+				before[index] = append(before[index], sprite.AnimationLayer)
+			} else {
+				after[index] = append(after[index], sprite.AnimationLayer)
+			}
+			continue
+		}
+		if sprite.Z < 0 {
+			behind = append(behind, sprite.AnimationLayer)
+		} else {
+			front = append(front, sprite.AnimationLayer)
+		}
+	}
+
+	layers = append(layers, shadow...)
+	layers = append(layers, behind...)
+	for i, layer := range partLayers {
+		layers = append(layers, before[i]...)
+		layers = append(layers, layer)
+		layers = append(layers, after[i]...)
+	}
+	layers = append(layers, front...)
+	return
+}
+
+// effectAddIndex finds the index of the figure part that an added body part is aligned to.
+// Returns false if the body part is not added by the effect, or none of its base body part's figure parts are drawn.
+func effectAddIndex(effect *res.EffectAnimation, id string, partTypes []nx.FigurePartType) (index int, bottom, ok bool) {
+	i := slices.IndexFunc(effect.Adds, func(add res.EffectAdd) bool {
+		return add.Id == id
+	})
+	if i < 0 {
+		return
+	}
+	add := effect.Adds[i]
+	bottom = add.Align == effectAlignBottom
+	index = -1
+	for i, partType := range partTypes {
+		if slices.Contains(effectBodyParts[add.Base], partType) && (!bottom || index < 0) {
+			index = i
+		}
+	}
+	ok = index >= 0
+	return
+}
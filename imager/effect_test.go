@@ -0,0 +1,86 @@
+package imager
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"xabbo.io/nx"
+	"xabbo.io/nx/res"
+)
+
+// testEffectLibrary is an in-memory effect library.
+type testEffectLibrary struct {
+	assets    map[string]*res.Asset
+	animation *res.EffectAnimation
+}
+
+func (lib testEffectLibrary) Name() string                    { return "test_effect" }
+func (lib testEffectLibrary) Assets() []string                { return maps.Keys(lib.assets) }
+func (lib testEffectLibrary) Animation() *res.EffectAnimation { return lib.animation }
+
+func (lib testEffectLibrary) AssetExists(name string) bool {
+	_, ok := lib.assets[name]
+	return ok
+}
+
+func (lib testEffectLibrary) Asset(name string) (*res.Asset, error) {
+	if asset, ok := lib.assets[name]; ok {
+		return asset, nil
+	}
+	return nil, fmt.Errorf("asset not found: %q", name)
+}
+
+func testLayer(name string) AnimationLayer {
+	return AnimationLayer{Frames: map[int]Frame{0: {Sprite{Asset: &res.Asset{Name: name}}}}}
+}
+
+func TestComposeEffectAddedSprite(t *testing.T) {
+	// The sprite only has an asset for direction 2, which is an alias of the direction 0 asset.
+	source := &res.Asset{Name: "h_std_fx_0_0", Offset: image.Pt(10, 20), Image: image.NewRGBA(image.Rect(0, 0, 30, 40))}
+	lib := testEffectLibrary{
+		assets: map[string]*res.Asset{
+			source.Name:    source,
+			"h_std_fx_2_0": {Name: "h_std_fx_2_0", Source: source, FlipH: true, Offset: source.Offset},
+		},
+		animation: &res.EffectAnimation{
+			Sprites: []res.EffectSprite{
+				{Id: "fx", Member: "fx", Directional: true, Offsets: map[int]res.EffectSpriteOffset{}},
+				{Id: "sd", Member: "sd", Offsets: map[int]res.EffectSpriteOffset{}},
+			},
+			Adds:   []res.EffectAdd{{Id: "fx", Align: "bottom", Base: "head"}},
+			Shadow: "sd",
+		},
+	}
+
+	sprites, err := composeEffectSprites(lib, Avatar{Direction: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sprites) != 2 {
+		t.Fatalf("composed %d sprites (expected 2)", len(sprites))
+	}
+	sprite := sprites[0].Frames[0][0]
+	if !sprite.FlipH || sprite.Asset.Name != "h_std_fx_2_0" {
+		t.Fatalf("expected flipped direction 2 asset, got %+v", sprite)
+	}
+	if expected := image.Pt(-10+30-64, 20); sprite.Offset != expected {
+		t.Fatalf("sprite offset is %v (expected %v)", sprite.Offset, expected)
+	}
+
+	sprites[0].AnimationLayer = testLayer("fx")
+	sprites[1].AnimationLayer = testLayer("sd")
+	layers := arrangeEffectLayers(lib.animation,
+		[]nx.FigurePartType{nx.Body, nx.Head, nx.Hair},
+		[]AnimationLayer{testLayer("bd"), testLayer("hd"), testLayer("hr")},
+		sprites)
+
+	var order []string
+	for _, layer := range layers {
+		order = append(order, layer.Frames[0][0].Asset.Name)
+	}
+	if fmt.Sprint(order) != "[sd bd fx hd hr]" {
+		t.Fatalf("unexpected layer order: %v", order)
+	}
+}
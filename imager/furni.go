@@ -107,8 +107,8 @@ func (r *furniImager) Compose(furni Furni) (anim Animation, err error) {
 		z := 0
 		if visLayer, ok := vis.Layers[layerId]; ok {
 			ink = visLayer.Ink
-			if visLayer.Alpha > 0 {
-				alpha = uint8(visLayer.Alpha)
+			if visLayer.Alpha != nil {
+				alpha = uint8(*visLayer.Alpha)
 			}
 			z = visLayer.Z
 		}
@@ -189,6 +189,18 @@ func (r *furniImager) Compose(furni Furni) (anim Animation, err error) {
 	return
 }
 
+// Validate checks the loaded furni library for problems and returns any issues found.
+// Compose skips assets that do not exist, so furni with missing assets are rendered incompletely.
+func (r *furniImager) Validate(identifier string) (issues []res.FurniIssue, err error) {
+	lib, ok := r.mgr.Library(identifier).(res.FurniLibrary)
+	if !ok {
+		err = fmt.Errorf("furni library not loaded: %q", identifier)
+		return
+	}
+	issues = res.ValidateFurniLibrary(lib)
+	return
+}
+
 func flipOffsetFurni(offset image.Point, bounds image.Rectangle) image.Point {
 	offset.X = -offset.X + bounds.Dx()
 	return offset
@@ -16,6 +16,7 @@ type FurniImager interface {
 type AvatarImager interface {
 	Compose(avatar Avatar) (Animation, error)
 	Parts(figure nx.Figure) ([]AvatarPart, error)
+	AvatarParts(avatar Avatar) ([]AvatarPart, error)
 	RequiredLibs(figure nx.Figure) ([]string, error)
 }
 
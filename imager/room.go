@@ -0,0 +1,458 @@
+package imager
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/res"
+)
+
+// TileVoid is the height of a tile that does not exist.
+const TileVoid = -1
+
+var (
+	defaultFloorColor = color.RGBA{0x98, 0x98, 0x65, 0xff}
+	defaultWallColor  = color.RGBA{0xb6, 0xb8, 0xc7, 0xff}
+)
+
+// Shading factors applied to each face of a room.
+const (
+	shadeFloorTop   = 1.0
+	shadeFloorLeft  = 0.8
+	shadeFloorRight = 0.65
+	shadeWallLeft   = 0.9
+	shadeWallRight  = 0.75
+)
+
+// A RoomModel defines the tile heights and door position of a room.
+type RoomModel struct {
+	Heights [][]int     // Heights contains the height of each tile indexed by [y][x]. Void tiles have a height of TileVoid.
+	Door    image.Point // Door is the position of the door tile.
+}
+
+// ParseHeightmap parses a heightmap into a RoomModel.
+// Each row of the heightmap is separated by a new line, where each character in the row
+// defines the height of a tile as `0-9` or `a-z` (10-35), or `x` for a void tile.
+func ParseHeightmap(heightmap string, door image.Point) (model RoomModel, err error) {
+	heightmap = strings.ReplaceAll(heightmap, "\r\n", "\n")
+	heightmap = strings.ReplaceAll(heightmap, "\r", "\n")
+	rows := strings.Split(strings.Trim(heightmap, "\n"), "\n")
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(strings.TrimSpace(row)))
+	}
+	if width == 0 {
+		err = errors.New("empty heightmap")
+		return
+	}
+
+	model.Door = door
+	model.Heights = make([][]int, len(rows))
+	for y, row := range rows {
+		row = strings.TrimSpace(row)
+		model.Heights[y] = make([]int, width)
+		for x := range width {
+			if x >= len(row) {
+				model.Heights[y][x] = TileVoid
+				continue
+			}
+			switch c := row[x]; {
+			case c == 'x' || c == 'X':
+				model.Heights[y][x] = TileVoid
+			case c >= '0' && c <= '9':
+				model.Heights[y][x] = int(c - '0')
+			case c >= 'a' && c <= 'z':
+				model.Heights[y][x] = int(c-'a') + 10
+			default:
+				err = fmt.Errorf("invalid heightmap character %q at %d,%d", c, x, y)
+				return
+			}
+		}
+	}
+	return
+}
+
+// Height gets the height of the tile at the specified position, or TileVoid if it is out of bounds.
+func (model *RoomModel) Height(x, y int) int {
+	if y < 0 || y >= len(model.Heights) || x < 0 || x >= len(model.Heights[y]) {
+		return TileVoid
+	}
+	return model.Heights[y][x]
+}
+
+// Room defines a room model and the textures used to render it.
+// Textures are tiled along the isometric plane of each face, with one texel per horizontal pixel.
+type Room struct {
+	Model     RoomModel
+	Size      int         // Size selects the visualization size to render. Defaults to 64.
+	Floor     image.Image // Floor is the texture of the floor. If nil, a default color is used.
+	Wallpaper image.Image // Wallpaper is the texture of the walls. If nil, a default color is used.
+	Landscape image.Image // Landscape is the texture of the wall planes behind the walls. If nil, no landscape is drawn.
+	HideWalls bool        // HideWalls configures whether to hide the walls.
+}
+
+type roomImager struct{}
+
+// NewRoomImager creates a new imager that composes room floors and walls.
+func NewRoomImager() roomImager {
+	return roomImager{}
+}
+
+// A roomFace is a textured, convex polygon within a room.
+// The texture is mapped onto the face's plane by the screen displacements u and v
+// of a single texel along each axis of the texture, starting from the origin.
+type roomFace struct {
+	points  []image.Point
+	texture image.Image
+	color   color.Color
+	shade   float64
+	origin  [2]float64
+	u, v    [2]float64
+}
+
+// Texel axes of each plane, as screen displacements.
+var (
+	floorU     = [2]float64{1, 0.5}
+	floorV     = [2]float64{-1, 0.5}
+	leftWallU  = [2]float64{1, -0.5}
+	rightWallU = [2]float64{1, 0.5}
+	verticalV  = [2]float64{0, 1}
+)
+
+// verticalPlane creates a vertical face whose texture starts at the top edge through the point q,
+// with the texture's origin at x=0 so that it is continuous across faces on the same plane.
+func verticalPlane(points []image.Point, q image.Point, u [2]float64) roomFace {
+	t := float64(q.X) / u[0]
+	return roomFace{
+		points: points,
+		origin: [2]float64{float64(q.X) - t*u[0], float64(q.Y) - t*u[1]},
+		u:      u,
+		v:      verticalV,
+	}
+}
+
+// floorPlane creates a floor face for tiles at the specified height,
+// with the texture's origin at the top corner of tile 0,0.
+func floorPlane(points []image.Point, size, height int) roomFace {
+	origin := tileToScreen(size, 0, 0, float64(height))
+	return roomFace{
+		points: points,
+		origin: [2]float64{float64(origin.X), float64(origin.Y)},
+		u:      floorU,
+		v:      floorV,
+	}
+}
+
+// textured sets the texture, default color and shade of the face.
+func (face roomFace) textured(texture image.Image, c color.Color, shade float64) roomFace {
+	face.texture = texture
+	face.color = c
+	face.shade = shade
+	return face
+}
+
+// Compose composes a room's floor and walls into an Animation.
+// Positions are relative to the top corner of the tile at 0,0 with a height of 0,
+// matching the positions of objects composed in a Scene.
+func (imgr roomImager) Compose(room Room) (anim Animation, err error) {
+	size := room.Size
+	if size == 0 {
+		size = 64
+	}
+	if size != 32 && size != 64 {
+		err = fmt.Errorf("invalid room size: %d", size)
+		return
+	}
+
+	model := &room.Model
+	thickness := size / 8
+	wallHeight := size * 7 / 4
+	halfW, halfH, unit := size/2, size/4, size/2
+
+	type tile struct{ x, y, h int }
+	tiles := []tile{}
+	maxHeight := 0
+	for y := range model.Heights {
+		for x, h := range model.Heights[y] {
+			if h != TileVoid {
+				tiles = append(tiles, tile{x, y, h})
+				maxHeight = max(maxHeight, h)
+			}
+		}
+	}
+	if len(tiles) == 0 {
+		err = errors.New("room has no tiles")
+		return
+	}
+	// Draw tiles from back to front.
+	slices.SortStableFunc(tiles, func(a, b tile) int {
+		return (a.x + a.y) - (b.x + b.y)
+	})
+
+	walls := []roomFace{}
+	floor := []roomFace{}
+
+	for _, t := range tiles {
+		top := tileToScreen(size, t.x, t.y, float64(t.h))
+		left := top.Add(image.Pt(-halfW, halfH))
+		right := top.Add(image.Pt(halfW, halfH))
+		bottom := top.Add(image.Pt(0, halfH*2))
+
+		isDoor := t.x == model.Door.X && t.y == model.Door.Y
+		if !room.HideWalls && !isDoor {
+			// Walls extend up to the same level above the highest tile.
+			up := image.Pt(0, -wallHeight-(maxHeight-t.h)*unit)
+			if model.Height(t.x-1, t.y) == TileVoid {
+				walls = append(walls, verticalPlane(
+					[]image.Point{left, top, top.Add(up), left.Add(up)},
+					top.Add(up), leftWallU,
+				).textured(room.Wallpaper, defaultWallColor, shadeWallLeft))
+			}
+			if model.Height(t.x, t.y-1) == TileVoid {
+				walls = append(walls, verticalPlane(
+					[]image.Point{top, right, right.Add(up), top.Add(up)},
+					top.Add(up), rightWallU,
+				).textured(room.Wallpaper, defaultWallColor, shadeWallRight))
+			}
+		}
+
+		floor = append(floor, floorPlane(
+			[]image.Point{top, right, bottom, left}, size, t.h,
+		).textured(room.Floor, defaultFloorColor, shadeFloorTop))
+
+		// The left face is visible when the tile in front-left is lower or void,
+		// and the right face when the tile in front-right is lower or void.
+		// Stair steps extend the face down to the height of the lower tile.
+		if depth := sideDepth(t.h, model.Height(t.x, t.y+1), thickness, unit); depth > 0 {
+			down := image.Pt(0, depth)
+			floor = append(floor, verticalPlane(
+				[]image.Point{left, bottom, bottom.Add(down), left.Add(down)},
+				left, rightWallU,
+			).textured(room.Floor, defaultFloorColor, shadeFloorLeft))
+		}
+		if depth := sideDepth(t.h, model.Height(t.x+1, t.y), thickness, unit); depth > 0 {
+			down := image.Pt(0, depth)
+			floor = append(floor, verticalPlane(
+				[]image.Point{bottom, right, right.Add(down), bottom.Add(down)},
+				bottom, leftWallU,
+			).textured(room.Floor, defaultFloorColor, shadeFloorRight))
+		}
+	}
+
+	bounds := image.Rectangle{}
+	for _, faces := range [][]roomFace{walls, floor} {
+		for _, face := range faces {
+			for _, pt := range face.points {
+				bounds = bounds.Union(image.Rectangle{pt, pt.Add(image.Pt(1, 1))})
+			}
+		}
+	}
+
+	anim.Layers = map[int]AnimationLayer{}
+	if room.Landscape != nil && len(walls) > 0 {
+		img := image.NewRGBA(bounds)
+		for _, face := range walls {
+			face.texture = room.Landscape
+			face.shade = 1
+			face.draw(img)
+		}
+		anim.Layers[0] = roomLayer("room_landscape", img, 0)
+	}
+	if len(walls) > 0 {
+		img := image.NewRGBA(bounds)
+		for _, face := range walls {
+			face.draw(img)
+		}
+		anim.Layers[1] = roomLayer("room_walls", img, 1)
+	}
+	img := image.NewRGBA(bounds)
+	for _, face := range floor {
+		face.draw(img)
+	}
+	anim.Layers[2] = roomLayer("room_floor", img, 2)
+	return
+}
+
+// sideDepth gets the depth of a tile's side face given the height of the adjacent tile in front of it.
+func sideDepth(height, frontHeight, thickness, unit int) int {
+	switch {
+	case frontHeight == TileVoid:
+		return thickness
+	case frontHeight < height:
+		return thickness + (height-frontHeight)*unit
+	default:
+		return 0
+	}
+}
+
+// roomLayer creates an animation layer containing a single sprite of the image.
+// The image is rebased to the origin, with its position carried by the sprite's offset.
+func roomLayer(name string, img *image.RGBA, z int) AnimationLayer {
+	offset := image.Point{}.Sub(img.Rect.Min)
+	img.Rect = img.Rect.Add(offset)
+	return AnimationLayer{
+		Frames: map[int]Frame{
+			0: {Sprite{
+				Asset:  &res.Asset{Name: name, Image: img},
+				Offset: offset,
+				Color:  color.White,
+				Alpha:  255,
+			}},
+		},
+		Z: z,
+	}
+}
+
+// draw fills the face onto the canvas.
+func (face roomFace) draw(canvas *image.RGBA) {
+	bounds := image.Rectangle{}
+	for _, pt := range face.points {
+		bounds = bounds.Union(image.Rectangle{pt, pt.Add(image.Pt(1, 1))})
+	}
+	bounds = bounds.Intersect(canvas.Bounds())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if !insideConvex(face.points, image.Pt(x, y)) {
+				continue
+			}
+			c := face.color
+			if face.texture != nil {
+				c = face.texel(x, y)
+			}
+			canvas.Set(x, y, shadeColor(c, face.shade))
+		}
+	}
+}
+
+// texel gets the color of the face's texture at the screen position
+// by projecting the center of the pixel onto the face's plane.
+func (face roomFace) texel(x, y int) color.Color {
+	dx := float64(x) + 0.5 - face.origin[0]
+	dy := float64(y) + 0.5 - face.origin[1]
+	u, v := face.u, face.v
+	det := u[0]*v[1] - v[0]*u[1]
+	tu := (dx*v[1] - v[0]*dy) / det
+	tv := (u[0]*dy - dx*u[1]) / det
+
+	tb := face.texture.Bounds()
+	return face.texture.At(
+		tb.Min.X+mod(int(math.Floor(tu)), tb.Dx()),
+		tb.Min.Y+mod(int(math.Floor(tv)), tb.Dy()),
+	)
+}
+
+// insideConvex reports whether the point lies within the convex polygon.
+func insideConvex(points []image.Point, pt image.Point) bool {
+	sign := 0
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		cross := (b.X-a.X)*(pt.Y-a.Y) - (b.Y-a.Y)*(pt.X-a.X)
+		switch {
+		case cross > 0:
+			if sign < 0 {
+				return false
+			}
+			sign = 1
+		case cross < 0:
+			if sign > 0 {
+				return false
+			}
+			sign = -1
+		}
+	}
+	return true
+}
+
+func shadeColor(c color.Color, shade float64) color.Color {
+	r, g, b, a := c.RGBA()
+	return color.RGBA64{
+		R: uint16(float64(r) * shade),
+		G: uint16(float64(g) * shade),
+		B: uint16(float64(b) * shade),
+		A: uint16(a),
+	}
+}
+
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
+
+// LoadRoomTexture loads the texture of a floor, wallpaper or landscape from the room content library.
+// The param is the pattern of the floor, wallpaper or landscape furni, e.g. "101" for a wallpaper.
+// The texture is selected from the visualization of the pattern for the specified size,
+// and is multiplied by the color of the visualization layer if it defines one.
+func LoadRoomTexture(mgr gd.Manager, furniType nx.FurniType, param string, size int) (texture image.Image, err error) {
+	var planeType res.RoomPlaneType
+	switch furniType {
+	case nx.FurniTypeFloor:
+		planeType = res.RoomPlaneFloor
+	case nx.FurniTypeWallpaper:
+		planeType = res.RoomPlaneWall
+	case nx.FurniTypeLandscape:
+		planeType = res.RoomPlaneLandscape
+	default:
+		err = fmt.Errorf("not a room texture furni type: %d", furniType)
+		return
+	}
+
+	err = mgr.LoadRoomContent()
+	if err != nil {
+		return
+	}
+	lib, ok := mgr.Library(res.RoomContentLibrary).(res.RoomLibrary)
+	if !ok {
+		err = errors.New("room content library not loaded")
+		return
+	}
+
+	assetName, layer, err := lib.Visualization().PlaneTexture(planeType, param, size)
+	if err != nil {
+		return
+	}
+	asset, err := lib.Asset(assetName)
+	if err != nil {
+		return
+	}
+	texture = asset.SourceImage()
+	if texture == nil {
+		err = fmt.Errorf("no image for texture asset %q", assetName)
+		return
+	}
+
+	if layer.Color != 0 {
+		texture = multiplyImage(texture, color.RGBA{
+			R: uint8(layer.Color >> 16),
+			G: uint8(layer.Color >> 8),
+			B: uint8(layer.Color),
+			A: 255,
+		})
+	}
+	return
+}
+
+// multiplyImage creates a copy of the image with each pixel multiplied by the color.
+func multiplyImage(img image.Image, c color.RGBA) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r * uint32(c.R) / 0xff),
+				G: uint16(g * uint32(c.G) / 0xff),
+				B: uint16(b * uint32(c.B) / 0xff),
+				A: uint16(a),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,96 @@
+package imager
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestParseHeightmap(t *testing.T) {
+	model, err := ParseHeightmap("xxx\r\nx0a\n01", image.Pt(0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		x, y     int
+		expected int
+	}{
+		{0, 0, TileVoid},
+		{1, 1, 0},
+		{2, 1, 10},
+		{1, 2, 1},
+		{2, 2, TileVoid},
+		{5, 5, TileVoid},
+	} {
+		if actual := model.Height(test.x, test.y); actual != test.expected {
+			t.Fatalf("height at %d,%d is %d (expected %d)", test.x, test.y, actual, test.expected)
+		}
+	}
+
+	if _, err := ParseHeightmap("x0?", image.Point{}); err == nil {
+		t.Fatal("expected error for invalid heightmap character")
+	}
+}
+
+func TestComposeRoom(t *testing.T) {
+	model, err := ParseHeightmap("xxx\nx00\n000", image.Pt(0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	anim, err := NewRoomImager().Compose(Room{Model: model})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Layers) != 2 {
+		t.Fatalf("room has %d layers (expected 2)", len(anim.Layers))
+	}
+
+	anim, err = NewRoomImager().Compose(Room{Model: model, HideWalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Layers) != 1 {
+		t.Fatalf("room has %d layers (expected 1)", len(anim.Layers))
+	}
+}
+
+func TestComposeRoomTextureAlongPlane(t *testing.T) {
+	// The texture's color depends only on the texel's u coordinate.
+	texture := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for y := range 32 {
+		for x := range 32 {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= 16 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			texture.Set(x, y, c)
+		}
+	}
+
+	model, err := ParseHeightmap("000\n000\n000", image.Pt(-1, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	anim, err := NewRoomImager().Compose(Room{Model: model, Floor: texture, HideWalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sprite := anim.Layers[2].Frames[0][0]
+	img := sprite.Asset.Image
+	at := func(pt image.Point) color.Color {
+		return img.At(pt.X+sprite.Offset.X, pt.Y+sprite.Offset.Y)
+	}
+
+	// Moving along the floor's v axis keeps the same texture column,
+	// while moving 16 texels along the u axis changes it.
+	start := tileToScreen(64, 1, 1, 0).Add(image.Pt(0, 16))
+	expected := at(start)
+	for i := 1; i <= 4; i++ {
+		if c := at(start.Add(image.Pt(-2*i, i))); c != expected {
+			t.Fatalf("color changed along the v axis at step %d: %v (expected %v)", i, c, expected)
+		}
+	}
+	if c := at(start.Add(image.Pt(16, 8))); c == expected {
+		t.Fatalf("color did not change along the u axis")
+	}
+}
@@ -0,0 +1,372 @@
+package imager
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/res"
+)
+
+// A Scene describes a set of furni and avatars placed on an isometric room grid.
+type Scene struct {
+	Size    int           `json:"size" yaml:"size"`       // Size selects the visualization size to render. Defaults to 64.
+	Shadows bool          `json:"shadows" yaml:"shadows"` // Shadows configures whether to render furni shadows.
+	Room    *SceneRoom    `json:"room" yaml:"room"`       // Room optionally defines the floor and walls behind the scene.
+	Furni   []SceneFurni  `json:"furni" yaml:"furni"`
+	Avatars []SceneAvatar `json:"avatars" yaml:"avatars"`
+}
+
+// SceneRoom defines the room model and textures of a scene.
+type SceneRoom struct {
+	Heightmap string `json:"heightmap" yaml:"heightmap"`
+	DoorX     int    `json:"door_x" yaml:"door_x"`
+	DoorY     int    `json:"door_y" yaml:"door_y"`
+	HideWalls bool   `json:"hide_walls" yaml:"hide_walls"`
+	// Floor, Wallpaper and Landscape are the params of the floor, wallpaper and landscape furni
+	// used to texture the room, e.g. "101", selecting a pattern from the room content library.
+	Floor     string `json:"floor" yaml:"floor"`
+	Wallpaper string `json:"wallpaper" yaml:"wallpaper"`
+	Landscape string `json:"landscape" yaml:"landscape"`
+}
+
+// SceneFurni defines a furni placed in a scene.
+type SceneFurni struct {
+	// Identifier is the furni identifier.
+	// A color index may be specified with the `*` suffix, e.g. `rare_dragonlamp*4`.
+	Identifier string  `json:"identifier" yaml:"identifier"`
+	X          int     `json:"x" yaml:"x"`
+	Y          int     `json:"y" yaml:"y"`
+	Z          float64 `json:"z" yaml:"z"`
+	Direction  int     `json:"direction" yaml:"direction"`
+	State      int     `json:"state" yaml:"state"`
+}
+
+// SceneAvatar defines an avatar placed in a scene.
+type SceneAvatar struct {
+	Figure        string   `json:"figure" yaml:"figure"`
+	X             int      `json:"x" yaml:"x"`
+	Y             int      `json:"y" yaml:"y"`
+	Z             float64  `json:"z" yaml:"z"`
+	Direction     int      `json:"direction" yaml:"direction"`
+	HeadDirection *int     `json:"head_direction" yaml:"head_direction"` // Defaults to the body direction.
+	Actions       []string `json:"actions" yaml:"actions"`
+	Expression    string   `json:"expression" yaml:"expression"`
+	HandItem      int      `json:"hand_item" yaml:"hand_item"` // Requires the crr or drk action.
+}
+
+// Unmarshals a JSON or YAML scene description as raw bytes into a Scene.
+func (scene *Scene) UnmarshalBytes(data []byte) (err error) {
+	// YAML is a superset of JSON, so both can be decoded with the YAML decoder.
+	var s Scene
+	err = yaml.Unmarshal(data, &s)
+	if err != nil {
+		return
+	}
+	if s.Size == 0 {
+		s.Size = 64
+	}
+	*scene = s
+	return
+}
+
+// LibraryName gets the name of the furni library.
+func (furni *SceneFurni) LibraryName() string {
+	return strings.SplitN(furni.Identifier, "*", 2)[0]
+}
+
+// ColorIndex gets the color index specified by the furni identifier.
+func (furni *SceneFurni) ColorIndex() int {
+	if split := strings.SplitN(furni.Identifier, "*", 2); len(split) == 2 {
+		if colorIndex, err := strconv.Atoi(split[1]); err == nil {
+			return colorIndex
+		}
+	}
+	return 0
+}
+
+// Avatar converts the scene avatar into an Avatar.
+func (sceneAvatar *SceneAvatar) Avatar() (avatar Avatar, err error) {
+	err = avatar.Figure.Parse(sceneAvatar.Figure)
+	if err != nil {
+		return
+	}
+	avatar.Direction = sceneAvatar.Direction
+	avatar.HeadDirection = sceneAvatar.Direction
+	if sceneAvatar.HeadDirection != nil {
+		avatar.HeadDirection = *sceneAvatar.HeadDirection
+	}
+	for _, action := range sceneAvatar.Actions {
+		state := nx.AvatarState(action)
+		if !state.IsAction() {
+			err = fmt.Errorf("invalid action: %q", action)
+			return
+		}
+		avatar.Actions = append(avatar.Actions, state)
+	}
+	if len(avatar.Actions) == 0 {
+		avatar.Actions = []nx.AvatarState{nx.ActStand}
+	}
+	avatar.HandItem = sceneAvatar.HandItem
+	if sceneAvatar.Expression != "" {
+		avatar.Expression = nx.AvatarState(sceneAvatar.Expression)
+		if !avatar.Expression.IsExpression() {
+			err = fmt.Errorf("invalid expression: %q", sceneAvatar.Expression)
+			return
+		}
+	}
+	return
+}
+
+// avatarOrigin is the point within an avatar's canvas that is placed on the center of its tile.
+var avatarOrigin = image.Pt(32, 102)
+
+type sceneImager struct {
+	mgr    gd.Manager
+	furni  *furniImager
+	avatar AvatarImager
+}
+
+// NewSceneImager creates a new scene imager using the specified game data manager.
+// All furni and figure part libraries required by a scene must be loaded before composing it.
+func NewSceneImager(mgr gd.Manager) *sceneImager {
+	return &sceneImager{
+		mgr:    mgr,
+		furni:  NewFurniImager(mgr),
+		avatar: NewAvatarImager(mgr),
+	}
+}
+
+// RequiredFigureLibs finds the figure part libraries required by the avatars in a scene,
+// including the libraries of their hand items and signs.
+func (imgr *sceneImager) RequiredFigureLibs(scene Scene) (libs []string, err error) {
+	libSet := map[string]struct{}{}
+	for _, sceneAvatar := range scene.Avatars {
+		var avatar Avatar
+		avatar, err = sceneAvatar.Avatar()
+		if err != nil {
+			return
+		}
+		var parts []AvatarPart
+		parts, err = imgr.avatar.AvatarParts(avatar)
+		if err != nil {
+			return
+		}
+		for _, part := range parts {
+			if part.LibraryName == "" {
+				continue
+			}
+			if _, exists := libSet[part.LibraryName]; !exists {
+				libSet[part.LibraryName] = struct{}{}
+				libs = append(libs, part.LibraryName)
+			}
+		}
+	}
+	return
+}
+
+// sceneLayer is an animation layer within a scene, along with the keys used to sort it.
+type sceneLayer struct {
+	AnimationLayer
+	shadow bool    // Whether this is a shadow layer. Shadows are drawn beneath all other layers.
+	depth  int     // The sum of the X and Y coordinates of the front-most tile occupied by the object.
+	height float64 // The height of the object.
+	z      int     // The Z value of the layer within its object.
+	object int     // The index of the object within the scene.
+	id     int     // The ID of the layer within its object.
+}
+
+// Compose composes a scene into a single Animation.
+func (imgr *sceneImager) Compose(scene Scene) (anim Animation, err error) {
+	size := scene.Size
+	if size == 0 {
+		size = 64
+	}
+	if len(scene.Avatars) > 0 && size != 64 {
+		err = fmt.Errorf("avatars can only be rendered at size 64")
+		return
+	}
+
+	var roomAnim Animation
+	if scene.Room != nil {
+		roomAnim, err = imgr.composeRoom(*scene.Room, size)
+		if err != nil {
+			return
+		}
+	}
+
+	layers := []sceneLayer{}
+	object := 0
+
+	for _, sceneFurni := range scene.Furni {
+		libName := sceneFurni.LibraryName()
+		lib, ok := imgr.mgr.Library(libName).(res.FurniLibrary)
+		if !ok {
+			err = fmt.Errorf("furni library not loaded: %q", libName)
+			return
+		}
+
+		var furniAnim Animation
+		furniAnim, err = imgr.furni.Compose(Furni{
+			Identifier: libName,
+			Size:       size,
+			Direction:  sceneFurni.Direction,
+			State:      sceneFurni.State,
+			Color:      sceneFurni.ColorIndex(),
+			Shadow:     scene.Shadows,
+		})
+		if err != nil {
+			return
+		}
+
+		// Find the front-most tile occupied by the furni.
+		dimX, dimY := 1, 1
+		if logic := lib.Logic(); logic != nil && logic.Model != nil {
+			dimX = max(1, int(logic.Model.Dimensions.X))
+			dimY = max(1, int(logic.Model.Dimensions.Y))
+		}
+		if sceneFurni.Direction%4 == 0 {
+			dimX, dimY = dimY, dimX
+		}
+		depth := (sceneFurni.X + dimX - 1) + (sceneFurni.Y + dimY - 1)
+
+		pos := tileToScreen(size, sceneFurni.X, sceneFurni.Y, sceneFurni.Z)
+		for layerId, layer := range furniAnim.Layers {
+			layers = append(layers, sceneLayer{
+				AnimationLayer: translateLayer(layer, pos),
+				shadow:         layerId < 0,
+				depth:          depth,
+				height:         sceneFurni.Z,
+				z:              layer.Z,
+				object:         object,
+				id:             layerId,
+			})
+		}
+		object++
+	}
+
+	for _, sceneAvatar := range scene.Avatars {
+		var avatar Avatar
+		avatar, err = sceneAvatar.Avatar()
+		if err != nil {
+			return
+		}
+
+		var avatarAnim Animation
+		avatarAnim, err = imgr.avatar.Compose(avatar)
+		if err != nil {
+			return
+		}
+
+		pos := tileToScreen(size, sceneAvatar.X, sceneAvatar.Y, sceneAvatar.Z).
+			Add(image.Pt(0, size/4)).
+			Sub(avatarOrigin)
+		for layerId, layer := range avatarAnim.Layers {
+			layers = append(layers, sceneLayer{
+				AnimationLayer: translateLayer(layer, pos),
+				depth:          sceneAvatar.X + sceneAvatar.Y,
+				height:         sceneAvatar.Z,
+				z:              layer.Z,
+				object:         object,
+				id:             layerId,
+			})
+		}
+		object++
+	}
+
+	slices.SortStableFunc(layers, compareSceneLayers)
+
+	// The room is drawn beneath all objects in the scene.
+	roomLayerIds := maps.Keys(roomAnim.Layers)
+	slices.Sort(roomLayerIds)
+	anim.Layers = make(map[int]AnimationLayer, len(roomLayerIds)+len(layers))
+	for _, layerId := range roomLayerIds {
+		layer := roomAnim.Layers[layerId]
+		layer.Z = len(anim.Layers)
+		anim.Layers[layer.Z] = layer
+	}
+	for _, layer := range layers {
+		layer.AnimationLayer.Z = len(anim.Layers)
+		anim.Layers[layer.AnimationLayer.Z] = layer.AnimationLayer
+	}
+	return
+}
+
+// composeRoom composes the floor and walls of a scene room.
+func (imgr *sceneImager) composeRoom(sceneRoom SceneRoom, size int) (anim Animation, err error) {
+	model, err := ParseHeightmap(sceneRoom.Heightmap, image.Pt(sceneRoom.DoorX, sceneRoom.DoorY))
+	if err != nil {
+		return
+	}
+	room := Room{
+		Model:     model,
+		Size:      size,
+		HideWalls: sceneRoom.HideWalls,
+	}
+	for _, texture := range []struct {
+		param     string
+		furniType nx.FurniType
+		img       *image.Image
+	}{
+		{sceneRoom.Floor, nx.FurniTypeFloor, &room.Floor},
+		{sceneRoom.Wallpaper, nx.FurniTypeWallpaper, &room.Wallpaper},
+		{sceneRoom.Landscape, nx.FurniTypeLandscape, &room.Landscape},
+	} {
+		if texture.param == "" {
+			continue
+		}
+		*texture.img, err = LoadRoomTexture(imgr.mgr, texture.furniType, texture.param, size)
+		if err != nil {
+			return
+		}
+	}
+	return NewRoomImager().Compose(room)
+}
+
+// compareSceneLayers orders scene layers from back to front.
+func compareSceneLayers(a, b sceneLayer) int {
+	if a.shadow != b.shadow {
+		if a.shadow {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Or(
+		cmp.Compare(a.depth, b.depth),
+		cmp.Compare(a.height, b.height),
+		cmp.Compare(a.z, b.z),
+		cmp.Compare(a.object, b.object),
+		cmp.Compare(a.id, b.id),
+	)
+}
+
+// tileToScreen converts a tile position to its screen position for the specified visualization size.
+// The screen position is the top corner of the tile.
+func tileToScreen(size, x, y int, z float64) image.Point {
+	return image.Point{
+		X: (x - y) * size / 2,
+		Y: (x+y)*size/4 - int(z*float64(size/2)),
+	}
+}
+
+// translateLayer returns a copy of the animation layer with all sprites translated by the specified offset.
+func translateLayer(layer AnimationLayer, offset image.Point) AnimationLayer {
+	frames := make(map[int]Frame, len(layer.Frames))
+	for frameId, frame := range layer.Frames {
+		frame = slices.Clone(frame)
+		for i := range frame {
+			frame[i].Offset = frame[i].Offset.Sub(offset)
+		}
+		frames[frameId] = frame
+	}
+	layer.Frames = frames
+	return layer
+}
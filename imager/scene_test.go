@@ -0,0 +1,121 @@
+package imager
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	gd "xabbo.io/nx/gamedata"
+)
+
+func TestUnmarshalScene(t *testing.T) {
+	for name, data := range map[string]string{
+		"json": `{"furni":[{"identifier":"rare_dragonlamp*4","x":1,"y":2,"direction":4}],"avatars":[{"figure":"hd-180-1","actions":["sit","wav"]}]}`,
+		"yaml": "furni:\n  - identifier: rare_dragonlamp*4\n    x: 1\n    y: 2\n    direction: 4\navatars:\n  - figure: hd-180-1\n    actions: [sit, wav]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var scene Scene
+			err := scene.UnmarshalBytes([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if scene.Size != 64 {
+				t.Fatalf("size is %d (expected 64)", scene.Size)
+			}
+			if len(scene.Furni) != 1 || len(scene.Avatars) != 1 {
+				t.Fatalf("unexpected scene: %+v", scene)
+			}
+			furni := scene.Furni[0]
+			if furni.LibraryName() != "rare_dragonlamp" || furni.ColorIndex() != 4 {
+				t.Fatalf("unexpected furni: %+v", furni)
+			}
+			avatar, err := scene.Avatars[0].Avatar()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(avatar.Actions) != 2 {
+				t.Fatalf("actions are %v (expected 2 actions)", avatar.Actions)
+			}
+		})
+	}
+}
+
+func TestTileToScreen(t *testing.T) {
+	for _, test := range []struct {
+		size     int
+		x, y     int
+		z        float64
+		expected image.Point
+	}{
+		{64, 0, 0, 0, image.Pt(0, 0)},
+		{64, 1, 0, 0, image.Pt(32, 16)},
+		{64, 0, 1, 0, image.Pt(-32, 16)},
+		{64, 1, 1, 1, image.Pt(0, 0)},
+		{32, 2, 0, 0.5, image.Pt(32, 8)},
+	} {
+		actual := tileToScreen(test.size, test.x, test.y, test.z)
+		if actual != test.expected {
+			t.Fatalf("tileToScreen(%d, %d, %d, %v) = %v (expected %v)",
+				test.size, test.x, test.y, test.z, actual, test.expected)
+		}
+	}
+}
+
+// newTestFigureManager creates a game data manager with test figure data, figure map and avatar actions loaded.
+func newTestFigureManager(t *testing.T) gd.Manager {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"figuredata.xml": `<figuredata><colors/><sets>
+			<settype type="hd" paletteid="1">
+				<set id="180" gender="M"><part id="1" type="hd" colorable="0" index="0" colorindex="0"/></set>
+			</settype>
+		</sets></figuredata>`,
+		"figuremap.xml": `<map>
+			<lib id="hh_human_body" revision="1"><part id="1" type="hd"/></lib>
+			<lib id="hh_human_item" revision="1"><part id="2" type="ri"/></lib>
+			<lib id="hh_human_drink" revision="1"><part id="3" type="ri"/></lib>
+		</map>`,
+		"HabboAvatarActions.xml": `<actions>
+			<action id="Default" state="std" precedence="1000" main="1" isdefault="1" activepartset="figure" assetpartdefinition="std"/>
+			<action id="CarryItem" state="cri" precedence="100" activepartset="handRight" assetpartdefinition="crr">
+				<param id="1" value="2"/>
+			</action>
+			<action id="UseItem" state="usei" precedence="90" activepartset="handRight" assetpartdefinition="drk">
+				<param id="1" value="3"/>
+			</action>
+		</actions>`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mgr := gd.NewDirManager(dir, gd.FlashLayout)
+	if err := mgr.Load(gd.GameDataFigure, gd.GameDataFigureMap, gd.GameDataAvatar); err != nil {
+		t.Fatal(err)
+	}
+	return mgr
+}
+
+func TestSceneRequiredFigureLibsHandItem(t *testing.T) {
+	imgr := NewSceneImager(newTestFigureManager(t))
+	for action, expected := range map[string]string{
+		"crr": "hh_human_item",
+		"drk": "hh_human_drink",
+	} {
+		t.Run(action, func(t *testing.T) {
+			libs, err := imgr.RequiredFigureLibs(Scene{Avatars: []SceneAvatar{
+				{Figure: "hd-180", Actions: []string{action}, HandItem: 1},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Contains(libs, "hh_human_body") || !slices.Contains(libs, expected) {
+				t.Fatalf("libraries are %v (expected hh_human_body and %s)", libs, expected)
+			}
+		})
+	}
+}
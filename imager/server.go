@@ -0,0 +1,481 @@
+package imager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/disintegration/imaging"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/res"
+)
+
+const (
+	AvatarImagePath = "/habbo-imaging/avatarimage" // The path used to serve avatar images.
+	FurniImagePath  = "/habbo-imaging/furniimage"  // The path used to serve furni images.
+)
+
+var imageFormats = map[string]string{
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"apng": "image/apng",
+}
+
+// Server serves rendered avatar and furni images over HTTP.
+// Avatar images are served using the same query interface as the hotel's
+// `/habbo-imaging/avatarimage` endpoint.
+type Server struct {
+	// Api is used to resolve figure strings for the `user` parameter.
+	// If nil, requests specifying a user will fail.
+	Api *nx.ApiClient
+	// Cache is used to store encoded images. If nil, images are not cached.
+	Cache Cache
+	// FurniIssues is called with the problems found in a furni library the first time it is rendered.
+	// Missing assets are skipped when rendering, so these issues indicate incomplete images.
+	FurniIssues func(identifier string, issues []res.FurniIssue)
+
+	// mtx guards the game data manager, which is not safe for concurrent use.
+	mtx    sync.Mutex
+	mgr    gd.Manager
+	avatar AvatarImager
+	furni  *furniImager
+	mux    *http.ServeMux
+	// validated contains the furni libraries that have been validated.
+	validated map[string]bool
+}
+
+// NewServer creates a new imaging server using the specified game data manager.
+func NewServer(mgr gd.Manager) *Server {
+	s := &Server{
+		mgr:    mgr,
+		avatar: NewAvatarImager(mgr),
+		furni:  NewFurniImager(mgr),
+		mux:    http.NewServeMux(),
+
+		validated: map[string]bool{},
+	}
+	s.mux.HandleFunc(AvatarImagePath, s.ServeAvatar)
+	s.mux.HandleFunc(FurniImagePath, s.ServeFurni)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ServeAvatar serves an avatar image.
+// The following query parameters are supported:
+// figure, user, direction, head_direction, action, gesture, size, headonly and img_format.
+func (s *Server) ServeAvatar(w http.ResponseWriter, r *http.Request) {
+	req, err := parseAvatarQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.user != "" {
+		if s.Api == nil {
+			http.Error(w, "user lookup is not available", http.StatusBadRequest)
+			return
+		}
+		user, err := s.Api.GetUserByName(req.user)
+		if err != nil {
+			if errors.Is(err, nx.ErrUserNotFound) || errors.Is(err, nx.ErrUserBanned) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			} else {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+			}
+			return
+		}
+		req.figure = user.FigureString
+	}
+
+	err = req.avatar.Figure.Parse(req.figure)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.serve(w, req.key(), req.format, func() (Animation, error) {
+		return s.composeAvatar(req.avatar)
+	}, req.scale)
+}
+
+// ServeFurni serves a furni image.
+// The following query parameters are supported:
+// furni, size, direction, state, color, shadow and img_format.
+func (s *Server) ServeFurni(w http.ResponseWriter, r *http.Request) {
+	req, err := parseFurniQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.serve(w, req.key(), req.format, func() (Animation, error) {
+		return s.composeFurni(req)
+	}, 1)
+}
+
+func (s *Server) serve(w http.ResponseWriter, key, format string, compose func() (Animation, error), scale float64) {
+	if s.Cache != nil {
+		if data, ok := s.Cache.Get(key); ok {
+			writeImage(w, format, data)
+			return
+		}
+	}
+
+	anim, err := compose()
+	if err != nil {
+		if errors.Is(err, gd.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	data, err := encodeAnimation(anim, format, scale)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if s.Cache != nil {
+		s.Cache.Set(key, data)
+	}
+	writeImage(w, format, data)
+}
+
+func writeImage(w http.ResponseWriter, format string, data []byte) {
+	w.Header().Set("Content-Type", imageFormats[format])
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+func (s *Server) composeAvatar(avatar Avatar) (anim Animation, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	types := []gd.Type{
+		gd.GameDataFigure, gd.GameDataFigureMap, gd.GameDataVariables,
+		gd.GameDataAvatar, gd.GameDataPartSets, gd.GameDataGeometry,
+	}
+	if !s.mgr.Loaded(types...) {
+		err = s.mgr.Load(types...)
+		if err != nil {
+			return
+		}
+	}
+
+	parts, err := s.avatar.AvatarParts(avatar)
+	if err != nil {
+		return
+	}
+
+	for _, part := range parts {
+		if part.LibraryName == "" {
+			continue
+		}
+		err = s.mgr.LoadFigureParts(part.LibraryName)
+		if err != nil {
+			return
+		}
+	}
+
+	return s.avatar.Compose(avatar)
+}
+
+func (s *Server) composeFurni(req furniRequest) (anim Animation, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.mgr.Loaded(gd.GameDataFurni, gd.GameDataVariables) {
+		err = s.mgr.Load(gd.GameDataFurni, gd.GameDataVariables)
+		if err != nil {
+			return
+		}
+	}
+
+	err = s.mgr.LoadFurni(req.identifier)
+	if err != nil {
+		return
+	}
+
+	if s.FurniIssues != nil && !s.validated[req.furni.Identifier] {
+		var issues []res.FurniIssue
+		issues, err = s.furni.Validate(req.furni.Identifier)
+		if err != nil {
+			return
+		}
+		s.validated[req.furni.Identifier] = true
+		if len(issues) > 0 {
+			s.FurniIssues(req.furni.Identifier, issues)
+		}
+	}
+
+	return s.furni.Compose(req.furni)
+}
+
+// encodeAnimation encodes an animation to the specified format.
+// Each frame is scaled by the specified factor.
+func encodeAnimation(anim Animation, format string, scale float64) (data []byte, err error) {
+	frameCount := 1
+	if format != "png" {
+		frameCount = anim.TotalFrames(0)
+	}
+
+	frames := RenderFrames(anim, 0, frameCount)
+	if scale != 1 {
+		for i, frame := range frames {
+			bounds := frame.Bounds()
+			frames[i] = imaging.Resize(frame,
+				max(1, int(float64(bounds.Dx())*scale)),
+				max(1, int(float64(bounds.Dy())*scale)),
+				imaging.NearestNeighbor)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	switch format {
+	case "png":
+		err = NewEncoderPNG().EncodeImage(buf, frames[0])
+	case "gif":
+		err = NewEncoderGIF().EncodeImages(buf, frames)
+	case "apng":
+		err = NewEncoderAPNG().EncodeImages(buf, frames)
+	default:
+		err = fmt.Errorf("unknown image format: %q", format)
+	}
+	if err == nil {
+		data = buf.Bytes()
+	}
+	return
+}
+
+type avatarRequest struct {
+	figure string
+	user   string
+	avatar Avatar
+	size   string
+	scale  float64
+	format string
+}
+
+// key returns a string that uniquely identifies the rendered image.
+func (req *avatarRequest) key() string {
+	actions := make([]string, len(req.avatar.Actions))
+	for i, action := range req.avatar.Actions {
+		actions[i] = string(action)
+	}
+	return "avatar?" + url.Values{
+		"figure":         {req.figure},
+		"direction":      {strconv.Itoa(req.avatar.Direction)},
+		"head_direction": {strconv.Itoa(req.avatar.HeadDirection)},
+		"action":         {strings.Join(actions, ",")},
+		"gesture":        {string(req.avatar.Expression)},
+		"hand_item":      {strconv.Itoa(req.avatar.HandItem)},
+		"sign":           {strconv.Itoa(req.avatar.Sign)},
+		"size":           {req.size},
+		"headonly":       {strconv.FormatBool(req.avatar.HeadOnly)},
+		"img_format":     {req.format},
+	}.Encode()
+}
+
+func parseAvatarQuery(q url.Values) (req avatarRequest, err error) {
+	req.figure = q.Get("figure")
+	req.user = q.Get("user")
+	if req.figure == "" && req.user == "" {
+		err = errors.New("no figure or user specified")
+		return
+	}
+	if req.figure != "" && req.user != "" {
+		err = errors.New("only one of either figure or user may be specified")
+		return
+	}
+
+	req.avatar.Direction, err = parseDirection(q, "direction", 2)
+	if err != nil {
+		return
+	}
+	req.avatar.HeadDirection, err = parseDirection(q, "head_direction", req.avatar.Direction)
+	if err != nil {
+		return
+	}
+
+	if action := q.Get("action"); action != "" {
+		for _, s := range strings.Split(action, ",") {
+			name, value, hasValue := strings.Cut(strings.TrimSpace(s), "=")
+			state := nx.AvatarState(name)
+			if !state.IsAction() {
+				err = fmt.Errorf("invalid action: %q", name)
+				return
+			}
+			if hasValue {
+				var n int
+				n, err = strconv.Atoi(value)
+				if err != nil {
+					err = fmt.Errorf("invalid action parameter: %q", s)
+					return
+				}
+				switch state {
+				case nx.ActCarry, nx.ActDrink:
+					req.avatar.HandItem = n
+				case nx.ActSign:
+					if n < 0 || n > MaxSign {
+						err = fmt.Errorf("invalid sign: %d", n)
+						return
+					}
+					req.avatar.Sign = n
+				}
+			}
+			req.avatar.Actions = append(req.avatar.Actions, state)
+		}
+	}
+	if len(req.avatar.Actions) == 0 {
+		req.avatar.Actions = []nx.AvatarState{nx.ActStand}
+	}
+
+	switch gesture := nx.AvatarState(q.Get("gesture")); gesture {
+	case "", "std":
+	default:
+		if !slices.Contains(nx.AvatarExpressions, gesture) {
+			err = fmt.Errorf("invalid gesture: %q", gesture)
+			return
+		}
+		req.avatar.Expression = gesture
+	}
+
+	switch req.size = q.Get("size"); req.size {
+	case "s":
+		req.scale = 0.5
+	case "", "m", "n":
+		req.size = "m"
+		req.scale = 1
+	case "l":
+		req.scale = 2
+	default:
+		err = fmt.Errorf("invalid size: %q", req.size)
+		return
+	}
+
+	req.avatar.HeadOnly, err = parseBool(q, "headonly", false)
+	if err != nil {
+		return
+	}
+
+	req.format, err = parseFormat(q)
+	return
+}
+
+type furniRequest struct {
+	identifier string
+	furni      Furni
+	format     string
+}
+
+// key returns a string that uniquely identifies the rendered image.
+func (req *furniRequest) key() string {
+	return "furni?" + url.Values{
+		"furni":      {req.identifier},
+		"size":       {strconv.Itoa(req.furni.Size)},
+		"direction":  {strconv.Itoa(req.furni.Direction)},
+		"state":      {strconv.Itoa(req.furni.State)},
+		"color":      {strconv.Itoa(req.furni.Color)},
+		"shadow":     {strconv.FormatBool(req.furni.Shadow)},
+		"img_format": {req.format},
+	}.Encode()
+}
+
+func parseFurniQuery(q url.Values) (req furniRequest, err error) {
+	req.identifier = q.Get("furni")
+	if req.identifier == "" {
+		err = errors.New("no furni specified")
+		return
+	}
+
+	libName, strColor, _ := strings.Cut(req.identifier, "*")
+	req.furni.Identifier = libName
+
+	req.furni.Size, err = parseInt(q, "size", 64)
+	if err != nil {
+		return
+	}
+	req.furni.Direction, err = parseDirection(q, "direction", 2)
+	if err != nil {
+		return
+	}
+	req.furni.State, err = parseInt(q, "state", 0)
+	if err != nil {
+		return
+	}
+
+	defaultColor := 0
+	if strColor != "" {
+		defaultColor, _ = strconv.Atoi(strColor)
+	}
+	req.furni.Color, err = parseInt(q, "color", defaultColor)
+	if err != nil {
+		return
+	}
+
+	req.format, err = parseFormat(q)
+	if err != nil {
+		return
+	}
+
+	req.furni.Shadow, err = parseBool(q, "shadow", req.format != "gif")
+	return
+}
+
+func parseFormat(q url.Values) (format string, err error) {
+	format = strings.ToLower(q.Get("img_format"))
+	if format == "" {
+		format = "png"
+	}
+	if _, ok := imageFormats[format]; !ok {
+		err = fmt.Errorf("invalid image format: %q", format)
+	}
+	return
+}
+
+func parseInt(q url.Values, key string, def int) (n int, err error) {
+	s := q.Get(key)
+	if s == "" {
+		return def, nil
+	}
+	n, err = strconv.Atoi(s)
+	if err != nil {
+		err = fmt.Errorf("invalid %s: %q", key, s)
+	}
+	return
+}
+
+func parseDirection(q url.Values, key string, def int) (dir int, err error) {
+	dir, err = parseInt(q, key, def)
+	if err == nil && (dir < 0 || dir > 7) {
+		err = fmt.Errorf("invalid %s: %d", key, dir)
+	}
+	return
+}
+
+func parseBool(q url.Values, key string, def bool) (b bool, err error) {
+	switch s := q.Get(key); s {
+	case "":
+		b = def
+	case "1", "true":
+		b = true
+	case "0", "false":
+		b = false
+	default:
+		err = fmt.Errorf("invalid %s: %q", key, s)
+	}
+	return
+}
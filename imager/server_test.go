@@ -0,0 +1,180 @@
+package imager
+
+import (
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx"
+	gd "xabbo.io/nx/gamedata"
+	"xabbo.io/nx/res"
+)
+
+func TestParseAvatarQuery(t *testing.T) {
+	q, _ := url.ParseQuery("figure=hd-180-1&direction=4&action=sit,crr=6&gesture=sml&size=l&headonly=1&img_format=gif")
+	req, err := parseAvatarQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.avatar.Direction != 4 || req.avatar.HeadDirection != 4 {
+		t.Fatalf("direction is %d/%d (expected 4/4)", req.avatar.Direction, req.avatar.HeadDirection)
+	}
+	expectedActions := []nx.AvatarState{nx.ActSit, nx.ActCarry}
+	if !slices.Equal(req.avatar.Actions, expectedActions) {
+		t.Fatalf("actions are %v (expected %v)", req.avatar.Actions, expectedActions)
+	}
+	if req.avatar.HandItem != 6 {
+		t.Fatalf("hand item is %d (expected 6)", req.avatar.HandItem)
+	}
+	if req.avatar.Expression != nx.ExprSmile {
+		t.Fatalf("expression is %q (expected %q)", req.avatar.Expression, nx.ExprSmile)
+	}
+	if !req.avatar.HeadOnly || req.scale != 2 || req.format != "gif" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
+
+func TestParseAvatarQueryInvalid(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"figure=hd-180-1&user=xb7c",
+		"figure=hd-180-1&direction=8",
+		"figure=hd-180-1&action=fly",
+		"figure=hd-180-1&action=sig=18",
+		"figure=hd-180-1&gesture=xyz",
+		"figure=hd-180-1&size=xl",
+		"figure=hd-180-1&img_format=bmp",
+	} {
+		t.Run(query, func(t *testing.T) {
+			q, _ := url.ParseQuery(query)
+			if _, err := parseAvatarQuery(q); err == nil {
+				t.Fatalf("expected error for query %q", query)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", []byte{1})
+	cache.Set("b", []byte{2})
+	cache.Get("a")
+	cache.Set("c", []byte{3})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("least recently used entry should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Fatalf("entry %q should be cached", key)
+		}
+	}
+}
+
+func TestServeFurniStatus(t *testing.T) {
+	dir := t.TempDir()
+	server := NewServer(gd.NewDirManager(dir, gd.FlashLayout))
+
+	// Without game data, the server is misconfigured.
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FurniImagePath+"?furni=unknown", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status is %d (expected %d)", rec.Code, http.StatusInternalServerError)
+	}
+
+	files := map[string]string{
+		"furnidata.json":         `{"roomitemtypes": {"furnitype": []}, "wallitemtypes": {"furnitype": []}}`,
+		"external_variables.txt": "",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FurniImagePath+"?furni=unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status is %d (expected %d): %s", rec.Code, http.StatusNotFound, rec.Body)
+	}
+}
+
+// testFurniLibrary is a furni library with a single visualization.
+type testFurniLibrary struct {
+	vis    *res.Visualization
+	assets res.Assets
+}
+
+func (lib *testFurniLibrary) Name() string            { return "lamp" }
+func (lib *testFurniLibrary) Index() *res.Index       { return &res.Index{Type: "lamp"} }
+func (lib *testFurniLibrary) Manifest() *res.Manifest { return &res.Manifest{Name: "lamp"} }
+func (lib *testFurniLibrary) Logic() *res.Logic       { return &res.Logic{Type: "lamp"} }
+func (lib *testFurniLibrary) Assets() []string        { return maps.Keys(lib.assets) }
+
+func (lib *testFurniLibrary) Visualizations() map[int]*res.Visualization {
+	return map[int]*res.Visualization{lib.vis.Size: lib.vis}
+}
+
+func (lib *testFurniLibrary) Asset(name string) (*res.Asset, error) {
+	if asset, ok := lib.assets[name]; ok {
+		return asset, nil
+	}
+	return nil, gd.ErrNotFound
+}
+
+func (lib *testFurniLibrary) AssetExists(name string) bool {
+	_, ok := lib.assets[name]
+	return ok
+}
+
+func TestServeFurniIssues(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"furnidata.json":         `{"roomitemtypes": {"furnitype": []}, "wallitemtypes": {"furnitype": []}}`,
+		"external_variables.txt": "",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mgr := gd.NewDirManager(dir, gd.FlashLayout)
+	mgr.AddLibrary(&testFurniLibrary{
+		vis: &res.Visualization{
+			Size:       64,
+			LayerCount: 1,
+			Directions: map[int]struct{}{2: {}},
+			Animations: map[int]*res.Animation{0: {Layers: map[int]*res.AnimationLayer{
+				0: {FrameSequences: []res.FrameSequence{{0, 1}}},
+			}}},
+		},
+		assets: res.Assets{
+			"lamp_64_a_2_0": {Name: "lamp_64_a_2_0", Image: image.NewRGBA(image.Rect(0, 0, 2, 2))},
+		},
+	})
+
+	server := NewServer(mgr)
+	var reported []res.FurniIssue
+	server.FurniIssues = func(identifier string, issues []res.FurniIssue) {
+		reported = append(reported, issues...)
+	}
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FurniImagePath+"?furni=lamp", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status is %d (expected %d): %s", rec.Code, http.StatusOK, rec.Body)
+		}
+	}
+	if len(reported) != 1 || reported[0].Kind != res.FurniIssueMissingAsset || reported[0].Asset != "lamp_64_a_2_1" {
+		t.Fatalf("unexpected issues: %v", reported)
+	}
+}
@@ -1,2 +1,2 @@
-// Package nitro provides functionality for reading Nitro assets.
+// Package nitro provides functionality for reading Nitro assets and game data.
 package nitro
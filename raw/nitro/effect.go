@@ -0,0 +1,77 @@
+package nitro
+
+// Effect defines the metadata of a Nitro avatar effect library.
+type Effect struct {
+	Name        string                     `json:"name"`
+	Type        string                     `json:"type"`
+	Assets      map[string]Asset           `json:"assets"`
+	Animations  map[string]EffectAnimation `json:"animations"`
+	Spritesheet Spritesheet                `json:"spritesheet"`
+}
+
+type EffectAnimation struct {
+	Desc          string           `json:"desc"`
+	ResetOnToggle bool             `json:"resetOnToggle"`
+	Sprites       []EffectSprite   `json:"sprites"`
+	Adds          []EffectAdd      `json:"adds"`
+	Removes       []EffectRemove   `json:"removes"`
+	Shadow        *EffectShadow    `json:"shadow"`
+	Direction     *EffectDirection `json:"direction"`
+	Frames        []EffectFrame    `json:"frames"`
+	Avatars       []EffectAvatar   `json:"avatars"`
+}
+
+type EffectSprite struct {
+	Id            string                  `json:"id"`
+	Member        string                  `json:"member"`
+	Directions    int                     `json:"directions"`
+	Ink           int                     `json:"ink"`
+	StaticY       int                     `json:"staticY"`
+	DirectionList []EffectSpriteDirection `json:"directionList"`
+}
+
+type EffectSpriteDirection struct {
+	Id int `json:"id"`
+	Dx int `json:"dx"`
+	Dy int `json:"dy"`
+	Dz int `json:"dz"`
+}
+
+type EffectAdd struct {
+	Id    string `json:"id"`
+	Align string `json:"align"`
+	Base  string `json:"base"`
+}
+
+type EffectRemove struct {
+	Id string `json:"id"`
+}
+
+type EffectShadow struct {
+	Id string `json:"id"`
+}
+
+type EffectDirection struct {
+	Offset int `json:"offset"`
+}
+
+type EffectFrame struct {
+	Repeats   int               `json:"repeats"`
+	BodyParts []EffectFramePart `json:"bodyparts"`
+	Fxs       []EffectFramePart `json:"fxs"`
+}
+
+type EffectFramePart struct {
+	Id     string `json:"id"`
+	Action string `json:"action"`
+	Frame  int    `json:"frame"`
+	Dx     int    `json:"dx"`
+	Dy     int    `json:"dy"`
+	Dd     int    `json:"dd"`
+}
+
+type EffectAvatar struct {
+	Ink        int    `json:"ink"`
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+}
@@ -0,0 +1,9 @@
+package nitro
+
+// Figure defines the metadata of a Nitro figure part library.
+type Figure struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Assets      map[string]Asset `json:"assets"`
+	Spritesheet Spritesheet      `json:"spritesheet"`
+}
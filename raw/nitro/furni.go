@@ -16,21 +16,21 @@ type Furni struct {
 }
 
 type Asset struct {
-	Source string  `json:"source"`
+	Source string  `json:"source,omitempty"`
 	X      float64 `json:"x"`
 	Y      float64 `json:"y"`
-	FlipH  bool    `json:"flipH"`
-	FlipV  bool    `json:"flipV"`
+	FlipH  bool    `json:"flipH,omitempty"`
+	FlipV  bool    `json:"flipV,omitempty"`
 }
 
 type Logic struct {
 	Model           Model            `json:"model"`
-	ParticleSystems []ParticleSystem `json:"particleSystems"`
+	ParticleSystems []ParticleSystem `json:"particleSystems,omitempty"`
 }
 
 type Model struct {
 	Dimensions Dimensions `json:"dimensions"`
-	Directions []int      `json:"directions"`
+	Directions []int      `json:"directions,omitempty"`
 }
 
 type Dimensions struct {
@@ -47,37 +47,37 @@ type Visualization struct {
 	Angle      int               `json:"angle"`
 	LayerCount int               `json:"layerCount"`
 	Size       int               `json:"size"`
-	Layers     map[int]Layer     `json:"layers"`
-	Directions map[int]Direction `json:"directions"`
-	Colors     map[int]Color     `json:"colors"`
-	Animations map[int]Animation `json:"animations"`
+	Layers     map[int]Layer     `json:"layers,omitempty"`
+	Directions map[int]Direction `json:"directions,omitempty"`
+	Colors     map[int]Color     `json:"colors,omitempty"`
+	Animations map[int]Animation `json:"animations,omitempty"`
 }
 
 type Layer struct {
 	Z           float64 `json:"z"`
-	Ink         string  `json:"ink"`
-	Alpha       int     `json:"alpha"`
-	Color       int     `json:"color"`
-	IgnoreMouse bool    `json:"ignoreMouse"`
+	Ink         string  `json:"ink,omitempty"`
+	Alpha       *int    `json:"alpha,omitempty"`
+	Color       *int    `json:"color,omitempty"`
+	IgnoreMouse bool    `json:"ignoreMouse,omitempty"`
 }
 
 type Direction struct {
-	Layers map[int]Layer `json:"layers"`
+	Layers map[int]Layer `json:"layers,omitempty"`
 }
 
 type Color struct {
-	Layers map[int]Layer `json:"layers"`
+	Layers map[int]Layer `json:"layers,omitempty"`
 }
 
 type Animation struct {
-	Layers       map[int]AnimationLayer `json:"layers"`
-	TransitionTo *int
+	Layers       map[int]AnimationLayer `json:"layers,omitempty"`
+	TransitionTo *int                   `json:"transitionTo,omitempty"`
 }
 
 type AnimationLayer struct {
-	LoopCount      float64               `json:"loopCount"`
-	FrameRepeat    float64               `json:"frameRepeat"`
-	Random         float64               `json:"random"`
+	LoopCount      float64               `json:"loopCount,omitempty"`
+	FrameRepeat    float64               `json:"frameRepeat,omitempty"`
+	Random         float64               `json:"random,omitempty"`
 	FrameSequences map[int]FrameSequence `json:"frameSequences"`
 }
 
@@ -96,8 +96,8 @@ type Spritesheet struct {
 
 type SpriteFrame struct {
 	Frame            Size  `json:"frame"`
-	Rotated          bool  `json:"rotated"`
-	Trimmed          bool  `json:"trimmed"`
+	Rotated          bool  `json:"rotated,omitempty"`
+	Trimmed          bool  `json:"trimmed,omitempty"`
 	SpriteSourceSize Size  `json:"spriteSourceSize"`
 	SourceSize       Size  `json:"sourceSize"`
 	Pivot            Pivot `json:"pivot"`
@@ -0,0 +1,319 @@
+package nitro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FigureData.json
+
+type FigureData struct {
+	Palettes []FigurePalette `json:"palettes"`
+	SetTypes []FigureSetType `json:"setTypes"`
+}
+
+type FigurePalette struct {
+	Id     int           `json:"id"`
+	Colors []FigureColor `json:"colors"`
+}
+
+type FigureColor struct {
+	Id         int    `json:"id"`
+	Index      int    `json:"index"`
+	Club       int    `json:"club"`
+	Selectable bool   `json:"selectable"`
+	HexCode    string `json:"hexCode"`
+}
+
+type FigureSetType struct {
+	Type      string      `json:"type"`
+	PaletteId int         `json:"paletteId"`
+	MandM0    bool        `json:"mandatory_m_0"`
+	MandF0    bool        `json:"mandatory_f_0"`
+	MandM1    bool        `json:"mandatory_m_1"`
+	MandF1    bool        `json:"mandatory_f_1"`
+	Sets      []FigureSet `json:"sets"`
+}
+
+type FigureSet struct {
+	Id            int                 `json:"id"`
+	Gender        string              `json:"gender"`
+	Club          int                 `json:"club"`
+	Colorable     bool                `json:"colorable"`
+	Selectable    bool                `json:"selectable"`
+	Preselectable bool                `json:"preselectable"`
+	Sellable      bool                `json:"sellable"`
+	Parts         []FigurePart        `json:"parts"`
+	HiddenLayers  []FigureHiddenLayer `json:"hiddenLayers"`
+}
+
+type FigurePart struct {
+	Id         int    `json:"id"`
+	Type       string `json:"type"`
+	Colorable  bool   `json:"colorable"`
+	Index      int    `json:"index"`
+	ColorIndex int    `json:"colorindex"`
+}
+
+type FigureHiddenLayer struct {
+	PartType string `json:"partType"`
+}
+
+// FigureMap.json
+
+type FigureMap struct {
+	Libraries []FigureMapLib `json:"libraries"`
+}
+
+type FigureMapLib struct {
+	Id       string          `json:"id"`
+	Revision int             `json:"revision"`
+	Parts    []FigureMapPart `json:"parts"`
+}
+
+type FigureMapPart struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+func (part *FigureMapPart) UnmarshalJSON(data []byte) (err error) {
+	shim := struct {
+		Id   any    `json:"id"`
+		Type string `json:"type"`
+	}{}
+
+	err = json.Unmarshal(data, &shim)
+	if err != nil {
+		return
+	}
+
+	*part = FigureMapPart{Type: shim.Type}
+	// Part IDs are numeric, except for a few parts in the effect libraries.
+	switch id := shim.Id.(type) {
+	case string:
+		part.Id = id
+	case float64:
+		part.Id = strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return fmt.Errorf("unknown type %T for FigureMapPart.Id", shim.Id)
+	}
+	return
+}
+
+// ExternalTexts.json, UITexts.json
+
+// Texts maps text keys to values.
+type Texts map[string]string
+
+func (texts *Texts) UnmarshalJSON(data []byte) (err error) {
+	var m map[string]any
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return
+	}
+
+	*texts = make(Texts, len(m))
+	for key, value := range m {
+		(*texts)[key] = configString(value)
+	}
+	return
+}
+
+// renderer-config.json
+
+// RendererConfig maps renderer configuration keys to values.
+// Values may be strings, numbers, booleans, arrays or objects.
+type RendererConfig map[string]any
+
+// configString converts a configuration value to its string representation.
+// Arrays are joined with commas and objects are encoded as JSON.
+func configString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = configString(e)
+		}
+		return strings.Join(values, ",")
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// Strings returns the configuration values converted to strings.
+// Arrays are joined with commas and objects are encoded as JSON.
+func (config RendererConfig) Strings() map[string]string {
+	m := make(map[string]string, len(config))
+	for key, value := range config {
+		m[key] = configString(value)
+	}
+	return m
+}
+
+// HabboAvatarActions.json
+
+type AvatarActions struct {
+	Actions []Action        `json:"actions"`
+	Offsets []ActionOffsets `json:"actionOffsets"`
+}
+
+type Action struct {
+	Id                  string        `json:"id"`
+	State               string        `json:"state"`
+	Precedence          int           `json:"precedence"`
+	Main                bool          `json:"main"`
+	IsDefault           bool          `json:"isDefault"`
+	GeometryType        string        `json:"geometryType"`
+	ActivePartSet       string        `json:"activePartSet"`
+	AssetPartDefinition string        `json:"assetPartDefinition"`
+	Prevents            []string      `json:"prevents"`
+	Animation           bool          `json:"animation"`
+	PreventHeadTurn     bool          `json:"preventHeadTurn"`
+	StartFromFrameZero  bool          `json:"startFromFrameZero"`
+	Lay                 string        `json:"lay"`
+	Types               []ActionType  `json:"types"`
+	Params              []ActionParam `json:"params"`
+}
+
+type ActionType struct {
+	Id              int      `json:"id"`
+	Animated        bool     `json:"animated"`
+	Prevents        []string `json:"prevents"`
+	PreventHeadTurn bool     `json:"preventHeadTurn"`
+}
+
+type ActionParam struct {
+	Id    string `json:"id"`
+	Value string `json:"value"`
+}
+
+type ActionOffsets struct {
+	Action  string         `json:"action"`
+	Offsets []ActionOffset `json:"offsets"`
+}
+
+type ActionOffset struct {
+	Size      string  `json:"size"`
+	Direction int     `json:"direction"`
+	X         int     `json:"x"`
+	Y         int     `json:"y"`
+	Z         float64 `json:"z"`
+}
+
+// HabboAvatarPartSets.json
+
+type AvatarPartSets struct {
+	ActivePartSets []ActivePartSet `json:"activePartSets"`
+}
+
+type ActivePartSet struct {
+	Id          string       `json:"id"`
+	ActiveParts []ActivePart `json:"activeParts"`
+}
+
+type ActivePart struct {
+	SetType string `json:"setType"`
+}
+
+// HabboAvatarGeometry.json
+
+type AvatarGeometry struct {
+	Camera   GeometryVector   `json:"camera"`
+	Canvases []GeometryCanvas `json:"canvases"`
+	Types    []GeometryType   `json:"types"`
+}
+
+type GeometryVector struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+type GeometryCanvas struct {
+	Scope      string                   `json:"scope"`
+	Geometries []GeometryCanvasGeometry `json:"geometries"`
+}
+
+type GeometryCanvasGeometry struct {
+	Id     string `json:"id"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Dx     int    `json:"dx"`
+	Dy     int    `json:"dy"`
+}
+
+type GeometryType struct {
+	Id        string             `json:"id"`
+	BodyParts []GeometryBodyPart `json:"bodyParts"`
+}
+
+type GeometryBodyPart struct {
+	Id     string         `json:"id"`
+	X      float64        `json:"x"`
+	Y      float64        `json:"y"`
+	Z      float64        `json:"z"`
+	Radius float64        `json:"radius"`
+	Items  []GeometryItem `json:"items"`
+}
+
+type GeometryItem struct {
+	Id     string  `json:"id"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Z      float64 `json:"z"`
+	Radius float64 `json:"radius"`
+	Nx     float64 `json:"nx"`
+	Ny     float64 `json:"ny"`
+	Nz     float64 `json:"nz"`
+	Double bool    `json:"double"`
+}
+
+// EffectMap.json
+
+type EffectMap struct {
+	Effects []EffectMapEffect `json:"effects"`
+}
+
+type EffectMapEffect struct {
+	Id       int    `json:"id"`
+	Lib      string `json:"lib"`
+	Type     string `json:"type"`
+	Revision int    `json:"revision"`
+}
+
+func (effect *EffectMapEffect) UnmarshalJSON(data []byte) (err error) {
+	shim := struct {
+		Id       any    `json:"id"`
+		Lib      string `json:"lib"`
+		Type     string `json:"type"`
+		Revision int    `json:"revision"`
+	}{}
+
+	err = json.Unmarshal(data, &shim)
+	if err != nil {
+		return
+	}
+
+	*effect = EffectMapEffect{Lib: shim.Lib, Type: shim.Type, Revision: shim.Revision}
+	// Effect IDs may be encoded as either strings or numbers.
+	switch id := shim.Id.(type) {
+	case string:
+		effect.Id, err = strconv.Atoi(id)
+	case float64:
+		effect.Id = int(id)
+	default:
+		err = fmt.Errorf("unknown type %T for EffectMapEffect.Id", shim.Id)
+	}
+	return
+}
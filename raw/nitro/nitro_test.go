@@ -0,0 +1,54 @@
+package nitro
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	archive := Archive{Files: map[string]File{
+		"chair.json": {Name: "chair.json", Data: []byte(`{"name":"chair"}`)},
+		"chair.png":  {Name: "chair.png", Data: bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 100)},
+		"empty.txt":  {Name: "empty.txt", Data: []byte{}},
+	}}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).WriteArchive(archive); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	read, err := NewReader(bytes.NewReader(data)).ReadArchive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, archive) {
+		t.Fatalf("read archive does not match written archive")
+	}
+
+	buf.Reset()
+	if err := NewWriter(&buf).WriteArchive(read); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("rewritten archive is not byte-identical")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	file := File{Name: "lib.json", Data: []byte("{}")}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).WriteFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := NewReader(&buf).ReadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, file) {
+		t.Fatalf("expected %+v, got %+v", file, read)
+	}
+}
@@ -0,0 +1,67 @@
+package nitro
+
+// Room is the metadata of a Nitro room content library.
+type Room struct {
+	Name              string            `json:"name"`
+	Assets            map[string]Asset  `json:"assets"`
+	RoomVisualization RoomVisualization `json:"roomVisualization"`
+	Spritesheet       Spritesheet       `json:"spritesheet"`
+}
+
+type RoomVisualization struct {
+	FloorData     PlaneVisualizationData `json:"floorData"`
+	WallData      PlaneVisualizationData `json:"wallData"`
+	LandscapeData PlaneVisualizationData `json:"landscapeData"`
+}
+
+type PlaneVisualizationData struct {
+	Planes    []Plane         `json:"planes"`
+	Materials []PlaneMaterial `json:"materials"`
+	Textures  []PlaneTexture  `json:"textures"`
+}
+
+type Plane struct {
+	Id             string               `json:"id"`
+	Visualizations []PlaneVisualization `json:"visualizations"`
+}
+
+type PlaneVisualization struct {
+	Size   int          `json:"size"`
+	Layers []PlaneLayer `json:"layers"`
+}
+
+type PlaneLayer struct {
+	MaterialId string `json:"materialId"`
+	Color      int    `json:"color"`
+	Offset     int    `json:"offset"`
+}
+
+type PlaneMaterial struct {
+	Id       string                `json:"id"`
+	Matrices []PlaneMaterialMatrix `json:"matrices"`
+}
+
+type PlaneMaterialMatrix struct {
+	RepeatMode string                `json:"repeatMode"`
+	Align      string                `json:"align"`
+	Columns    []PlaneMaterialColumn `json:"columns"`
+}
+
+type PlaneMaterialColumn struct {
+	RepeatMode string              `json:"repeatMode"`
+	Width      int                 `json:"width"`
+	Cells      []PlaneMaterialCell `json:"cells"`
+}
+
+type PlaneMaterialCell struct {
+	TextureId string `json:"textureId"`
+}
+
+type PlaneTexture struct {
+	Id      string               `json:"id"`
+	Bitmaps []PlaneTextureBitmap `json:"bitmaps"`
+}
+
+type PlaneTextureBitmap struct {
+	AssetName string `json:"assetName"`
+}
@@ -0,0 +1,100 @@
+package nitro
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"golang.org/x/exp/maps"
+)
+
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) writeShort(v uint16) (err error) {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], v)
+	_, err = w.w.Write(buf[:])
+	return
+}
+
+func (w *Writer) writeInt(v uint32) (err error) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	_, err = w.w.Write(buf[:])
+	return
+}
+
+func (w *Writer) writeString(s string) (err error) {
+	if len(s) > math.MaxUint16 {
+		return fmt.Errorf("string too long: %d bytes", len(s))
+	}
+	err = w.writeShort(uint16(len(s)))
+	if err != nil {
+		return
+	}
+	_, err = io.WriteString(w.w, s)
+	return
+}
+
+// WriteArchive writes the files of an archive, ordered by name.
+func (w *Writer) WriteArchive(archive Archive) (err error) {
+	if len(archive.Files) > math.MaxUint16 {
+		return fmt.Errorf("too many files in archive: %d", len(archive.Files))
+	}
+
+	err = w.writeShort(uint16(len(archive.Files)))
+	if err != nil {
+		return
+	}
+
+	names := maps.Keys(archive.Files)
+	slices.Sort(names)
+	for _, name := range names {
+		file := archive.Files[name]
+		if file.Name == "" {
+			file.Name = name
+		}
+		err = w.WriteFile(file)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WriteFile writes a single file, compressing its data with zlib.
+func (w *Writer) WriteFile(file File) (err error) {
+	err = w.writeString(file.Name)
+	if err != nil {
+		return
+	}
+
+	var buffer bytes.Buffer
+	z := zlib.NewWriter(&buffer)
+	_, err = z.Write(file.Data)
+	if err != nil {
+		return
+	}
+	err = z.Close()
+	if err != nil {
+		return
+	}
+
+	err = w.writeInt(uint32(buffer.Len()))
+	if err != nil {
+		return
+	}
+	_, err = w.w.Write(buffer.Bytes())
+	return
+}
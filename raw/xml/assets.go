@@ -22,12 +22,12 @@ type Assets struct {
 
 type Asset struct {
 	Name     string  `xml:"name,attr"`
-	MimeType string  `xml:"mimeType,attr"`
-	X        int     `xml:"x,attr"`
-	Y        int     `xml:"y,attr"`
-	FlipH    bool    `xml:"flipH,attr"`
-	FlipV    bool    `xml:"flipV,attr"`
-	Source   string  `xml:"source,attr"`
+	MimeType string  `xml:"mimeType,attr,omitempty"`
+	X        int     `xml:"x,attr,omitempty"`
+	Y        int     `xml:"y,attr,omitempty"`
+	FlipH    bool    `xml:"flipH,attr,omitempty"`
+	FlipV    bool    `xml:"flipV,attr,omitempty"`
+	Source   string  `xml:"source,attr,omitempty"`
 	Params   []Param `xml:"param"`
 }
 
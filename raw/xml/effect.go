@@ -0,0 +1,89 @@
+package xml
+
+// effectmap.xml
+
+type EffectMap struct {
+	Effects []EffectMapEffect `xml:"effect"`
+}
+
+type EffectMapEffect struct {
+	Id       int    `xml:"id,attr"`
+	Lib      string `xml:"lib,attr"`
+	Type     string `xml:"type,attr"`
+	Revision int    `xml:"revision,attr"`
+}
+
+// *_animation.xml in effect libraries
+
+type EffectAnimation struct {
+	Desc          string             `xml:"desc,attr"`
+	ResetOnToggle bool               `xml:"resetOnToggle,attr"`
+	Sprites       []EffectSprite     `xml:"sprite"`
+	Adds          []EffectAdd        `xml:"add"`
+	Removes       []EffectRemove     `xml:"remove"`
+	Shadow        *EffectShadow      `xml:"shadow"`
+	Direction     *EffectDirection   `xml:"direction"`
+	Frames        []EffectFrame      `xml:"frame"`
+	Overrides     []EffectOverride   `xml:"override"`
+	Avatars       []EffectAvatarInfo `xml:"avatar"`
+}
+
+type EffectSprite struct {
+	Id         string                  `xml:"id,attr"`
+	Member     string                  `xml:"member,attr"`
+	Directions bool                    `xml:"directions,attr"`
+	Ink        int                     `xml:"ink,attr"`
+	StaticY    int                     `xml:"staticY,attr"`
+	Offsets    []EffectSpriteDirection `xml:"direction"`
+}
+
+type EffectSpriteDirection struct {
+	Id int `xml:"id,attr"`
+	Dx int `xml:"dx,attr"`
+	Dy int `xml:"dy,attr"`
+	Dz int `xml:"dz,attr"`
+}
+
+type EffectAdd struct {
+	Id    string `xml:"id,attr"`
+	Align string `xml:"align,attr"`
+	Base  string `xml:"base,attr"`
+}
+
+type EffectRemove struct {
+	Id string `xml:"id,attr"`
+}
+
+type EffectShadow struct {
+	Id string `xml:"id,attr"`
+}
+
+type EffectDirection struct {
+	Offset int `xml:"offset,attr"`
+}
+
+type EffectFrame struct {
+	Repeats   int               `xml:"repeats,attr"`
+	BodyParts []EffectFramePart `xml:"bodypart"`
+	Fx        []EffectFramePart `xml:"fx"`
+}
+
+type EffectFramePart struct {
+	Id     string `xml:"id,attr"`
+	Action string `xml:"action,attr"`
+	Frame  int    `xml:"frame,attr"`
+	Dx     int    `xml:"dx,attr"`
+	Dy     int    `xml:"dy,attr"`
+	Dd     int    `xml:"dd,attr"`
+}
+
+type EffectOverride struct {
+	Name     string `xml:"name,attr"`
+	Override string `xml:"override,attr"`
+}
+
+type EffectAvatarInfo struct {
+	Ink        int    `xml:"ink,attr"`
+	Foreground string `xml:"foreground,attr"`
+	Background string `xml:"background,attr"`
+}
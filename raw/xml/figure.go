@@ -73,26 +73,32 @@ type FigureMapPart struct {
 
 // HabboAvatarActions.xml
 
+type AvatarActions struct {
+	Actions []Action        `xml:"action"`
+	Offsets []ActionOffsets `xml:"actionoffsets>action"`
+}
+
 type Action struct {
-	Id                  string `xml:"id,attr"`
-	State               string `xml:"lay,attr"`
-	Precedence          int    `xml:"precedence,attr"`
-	Main                bool   `xml:"main,attr"`
-	IsDefault           bool   `xml:"isdefault,attr"`
-	GeometryType        string `xml:"geometrytype,attr"`
-	ActivePartSet       string `xml:"activepartset,attr"`
-	AssetPartDefinition string `xml:"assetpartdefinition,attr"`
-	Prevents            string `xml:"prevents,attr"`
-	Animation           bool   `xml:"animation,attr"`
-	PreventHeadTurn     bool   `xml:"preventheadturn,attr"`
-	StartFromFrameZero  bool   `xml:"startfromframezero,attr"`
-	Types               []ActionType
-	Params              []ActionParam
+	Id                  string        `xml:"id,attr"`
+	State               string        `xml:"state,attr"`
+	Precedence          int           `xml:"precedence,attr"`
+	Main                bool          `xml:"main,attr"`
+	IsDefault           bool          `xml:"isdefault,attr"`
+	GeometryType        string        `xml:"geometrytype,attr"`
+	ActivePartSet       string        `xml:"activepartset,attr"`
+	AssetPartDefinition string        `xml:"assetpartdefinition,attr"`
+	Prevents            string        `xml:"prevents,attr"`
+	Animation           bool          `xml:"animation,attr"`
+	PreventHeadTurn     bool          `xml:"preventheadturn,attr"`
+	StartFromFrameZero  bool          `xml:"startfromframezero,attr"`
+	Lay                 string        `xml:"lay,attr"`
+	Types               []ActionType  `xml:"type"`
+	Params              []ActionParam `xml:"param"`
 }
 
 type ActionType struct {
 	Id              int    `xml:"id,attr"`
-	Animated        bool   `xml:"animation,attr"`
+	Animated        bool   `xml:"animated,attr"`
 	Prevents        string `xml:"prevents,attr"`
 	PreventHeadTurn bool   `xml:"preventheadturn,attr"`
 }
@@ -101,3 +107,84 @@ type ActionParam struct {
 	Id    string `xml:"id,attr"`
 	Value string `xml:"value,attr"`
 }
+
+type ActionOffsets struct {
+	Id      string         `xml:"id,attr"`
+	Offsets []ActionOffset `xml:"offset"`
+}
+
+type ActionOffset struct {
+	Size      string  `xml:"size,attr"`
+	Direction int     `xml:"direction,attr"`
+	X         int     `xml:"x,attr"`
+	Y         int     `xml:"y,attr"`
+	Z         float64 `xml:"z,attr"`
+}
+
+// HabboAvatarPartSets.xml
+
+type AvatarPartSets struct {
+	ActivePartSets []ActivePartSet `xml:"activePartSet"`
+}
+
+type ActivePartSet struct {
+	Id          string       `xml:"id,attr"`
+	ActiveParts []ActivePart `xml:"activePart"`
+}
+
+type ActivePart struct {
+	SetType string `xml:"set-type,attr"`
+}
+
+// HabboAvatarGeometry.xml
+
+type AvatarGeometry struct {
+	Camera   GeometryVector   `xml:"camera"`
+	Canvases []GeometryCanvas `xml:"canvas"`
+	Types    []GeometryType   `xml:"type"`
+}
+
+type GeometryVector struct {
+	X float64 `xml:"x"`
+	Y float64 `xml:"y"`
+	Z float64 `xml:"z"`
+}
+
+type GeometryCanvas struct {
+	Scope      string                   `xml:"scope,attr"`
+	Geometries []GeometryCanvasGeometry `xml:"geometry"`
+}
+
+type GeometryCanvasGeometry struct {
+	Id     string `xml:"id,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Dx     int    `xml:"dx,attr"`
+	Dy     int    `xml:"dy,attr"`
+}
+
+type GeometryType struct {
+	Id        string             `xml:"id,attr"`
+	BodyParts []GeometryBodyPart `xml:"bodypart"`
+}
+
+type GeometryBodyPart struct {
+	Id     string         `xml:"id,attr"`
+	X      float64        `xml:"x,attr"`
+	Y      float64        `xml:"y,attr"`
+	Z      float64        `xml:"z,attr"`
+	Radius float64        `xml:"radius,attr"`
+	Items  []GeometryItem `xml:"item"`
+}
+
+type GeometryItem struct {
+	Id     string  `xml:"id,attr"`
+	X      float64 `xml:"x,attr"`
+	Y      float64 `xml:"y,attr"`
+	Z      float64 `xml:"z,attr"`
+	Radius float64 `xml:"radius,attr"`
+	Nx     float64 `xml:"nx,attr"`
+	Ny     float64 `xml:"ny,attr"`
+	Nz     float64 `xml:"nz,attr"`
+	Double bool    `xml:"double,attr"`
+}
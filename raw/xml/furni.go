@@ -10,7 +10,7 @@ import (
 type Index struct {
 	Type          string `xml:"type,attr"`
 	Visualization string `xml:"visualization,attr"`
-	Logic         string `xml:"logic"`
+	Logic         string `xml:"logic,attr"`
 }
 
 // logic.xml
@@ -65,11 +65,11 @@ type Visualization struct {
 
 type Layer struct {
 	Id          int    `xml:"id,attr"`
-	Z           int    `xml:"z,attr"`
-	Alpha       int    `xml:"alpha,attr"`
-	Ink         string `xml:"ink,attr"`
-	IgnoreMouse bool   `xml:"ignoreMouse,attr"`
-	Color       int    `xml:"color,attr"`
+	Z           int    `xml:"z,attr,omitempty"`
+	Alpha       *int   `xml:"alpha,attr,omitempty"`
+	Ink         string `xml:"ink,attr,omitempty"`
+	IgnoreMouse bool   `xml:"ignoreMouse,attr,omitempty"`
+	Color       int    `xml:"color,attr,omitempty"`
 }
 
 type Color struct {
@@ -90,9 +90,9 @@ type Animation struct {
 
 type AnimationLayer struct {
 	Id             int             `xml:"id,attr"`
-	LoopCount      int             `xml:"loopCount,attr"`
-	FrameRepeat    int             `xml:"frameRepeat,attr"`
-	Random         int             `xml:"random,attr"`
+	LoopCount      int             `xml:"loopCount,attr,omitempty"`
+	FrameRepeat    int             `xml:"frameRepeat,attr,omitempty"`
+	Random         int             `xml:"random,attr,omitempty"`
 	FrameSequences []FrameSequence `xml:"frameSequence"`
 }
 
@@ -0,0 +1,36 @@
+package xml
+
+// furnidata.xml
+
+type FurniData struct {
+	FloorItems []FurniInfo `xml:"roomitemtypes>furnitype"`
+	WallItems  []FurniInfo `xml:"wallitemtypes>furnitype"`
+}
+
+type FurniInfo struct {
+	Id              int      `xml:"id,attr"`
+	Identifier      string   `xml:"classname,attr"`
+	Revision        int      `xml:"revision"`
+	Name            string   `xml:"name"`
+	Description     string   `xml:"description"`
+	Category        string   `xml:"category"`
+	Environment     string   `xml:"environment"`
+	Line            string   `xml:"furniline"`
+	DefaultDir      int      `xml:"defaultdir"`
+	XDim            int      `xml:"xdim"`
+	YDim            int      `xml:"ydim"`
+	PartColors      []string `xml:"partcolors>color"`
+	AdUrl           string   `xml:"adurl"` // Obsolete.
+	OfferId         int      `xml:"offerid"`
+	Buyout          bool     `xml:"buyout"`
+	RentOfferId     int      `xml:"rentofferid"` // Obsolete.
+	RentBuyout      bool     `xml:"rentbuyout"`  // Obsolete.
+	BC              bool     `xml:"bc"`
+	ExcludedDynamic bool     `xml:"excludeddynamic"`
+	CustomParams    string   `xml:"customparams"`
+	SpecialType     int      `xml:"specialtype"`
+	CanStandOn      bool     `xml:"canstandon"`
+	CanSitOn        bool     `xml:"cansiton"`
+	CanLayOn        bool     `xml:"canlayon"`
+	Rare            bool     `xml:"rare"` // Obsolete.
+}
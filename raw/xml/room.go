@@ -0,0 +1,66 @@
+package xml
+
+// room_visualization.xml
+
+type RoomVisualizationData struct {
+	Type          string                 `xml:"type,attr"`
+	FloorData     PlaneVisualizationData `xml:"floorData"`
+	WallData      PlaneVisualizationData `xml:"wallData"`
+	LandscapeData PlaneVisualizationData `xml:"landscapeData"`
+}
+
+// PlaneVisualizationData contains the planes, materials and textures of a type of room plane.
+// The planes are contained in either Floors, Walls or Landscapes depending on the plane type.
+type PlaneVisualizationData struct {
+	Floors     []Plane         `xml:"floors>floor"`
+	Walls      []Plane         `xml:"walls>wall"`
+	Landscapes []Plane         `xml:"landscapes>landscape"`
+	Textures   []PlaneTexture  `xml:"textures>texture"`
+	Materials  []PlaneMaterial `xml:"materials>material"`
+}
+
+type Plane struct {
+	Id             string               `xml:"id,attr"`
+	Visualizations []PlaneVisualization `xml:"visualization"`
+}
+
+type PlaneVisualization struct {
+	Size   int          `xml:"size,attr"`
+	Layers []PlaneLayer `xml:"visualizationLayer"`
+}
+
+type PlaneLayer struct {
+	MaterialId string `xml:"materialId,attr"`
+	Color      string `xml:"color,attr"`
+	Offset     int    `xml:"offset,attr"`
+}
+
+type PlaneTexture struct {
+	Id      string        `xml:"id,attr"`
+	Bitmaps []PlaneBitmap `xml:"bitmap"`
+}
+
+type PlaneBitmap struct {
+	AssetName string `xml:"assetName,attr"`
+}
+
+type PlaneMaterial struct {
+	Id       string                `xml:"id,attr"`
+	Matrices []PlaneMaterialMatrix `xml:"materialCellMatrix"`
+}
+
+type PlaneMaterialMatrix struct {
+	RepeatMode string                `xml:"repeatMode,attr"`
+	Align      string                `xml:"align,attr"`
+	Columns    []PlaneMaterialColumn `xml:"materialCellColumn"`
+}
+
+type PlaneMaterialColumn struct {
+	RepeatMode string              `xml:"repeatMode,attr"`
+	Width      int                 `xml:"width,attr"`
+	Cells      []PlaneMaterialCell `xml:"materialCell"`
+}
+
+type PlaneMaterialCell struct {
+	TextureId string `xml:"textureId,attr"`
+}
@@ -54,6 +54,20 @@ func (a *Asset) fromXml(xAsset x.Asset) {
 	a.Offset = image.Point{xAsset.X, xAsset.Y}
 }
 
+func (a *Asset) toXml() x.Asset {
+	v := x.Asset{
+		Name:  a.Name,
+		X:     a.Offset.X,
+		Y:     a.Offset.Y,
+		FlipH: a.FlipH,
+		FlipV: a.FlipV,
+	}
+	if a.Source != nil {
+		v.Source = a.Source.Name
+	}
+	return v
+}
+
 func (a *Asset) fromNitro(name string, src nitro.Asset) *Asset {
 	*a = Asset{
 		Name:   name,
@@ -63,3 +77,16 @@ func (a *Asset) fromNitro(name string, src nitro.Asset) *Asset {
 	}
 	return a
 }
+
+func (a *Asset) toNitro() nitro.Asset {
+	v := nitro.Asset{
+		FlipH: a.FlipH,
+		FlipV: a.FlipV,
+		X:     float64(a.Offset.X),
+		Y:     float64(a.Offset.Y),
+	}
+	if a.Source != nil {
+		v.Source = a.Source.Name
+	}
+	return v
+}
@@ -0,0 +1,120 @@
+package res
+
+import (
+	"image"
+
+	x "xabbo.io/nx/raw/xml"
+)
+
+// EffectAnimation defines the sprites, part replacements and animation frames of an avatar effect.
+type EffectAnimation struct {
+	Sprites         []EffectSprite // The sprites drawn in addition to the avatar's figure parts.
+	Adds            []EffectAdd    // The body parts added to the avatar.
+	Removes         []string       // The figure part types or body parts removed from the avatar.
+	Shadow          string         // The ID of the sprite drawn as the avatar's shadow.
+	DirectionOffset int            // The offset applied to the avatar's direction.
+	Frames          []EffectFrame  // The animation frames of the effect.
+}
+
+// An EffectSprite defines a sprite drawn by an effect.
+type EffectSprite struct {
+	Id          string
+	Member      string // The member name used to select the sprite's assets.
+	Directional bool   // Whether the sprite has assets for each direction.
+	Ink         int    // The ink used to draw the sprite. An ink of 33 uses additive blending.
+	StaticY     int
+	Offsets     map[int]EffectSpriteOffset // Maps the sprite's offsets by direction.
+}
+
+// An EffectSpriteOffset defines the offset of an effect sprite for a direction.
+// A negative Z offset places the sprite behind the avatar.
+type EffectSpriteOffset struct {
+	Dx, Dy, Dz int
+}
+
+// An EffectAdd defines a body part added to the avatar by an effect.
+// The sprite with the same ID is drawn aligned to the base body part.
+type EffectAdd struct {
+	Id    string
+	Align string // The alignment to the base body part. Body parts aligned to the bottom are drawn behind it.
+	Base  string // The body part the added body part is aligned to, e.g. torso.
+}
+
+// An EffectFrame defines the state of each body part and sprite during a frame of an effect.
+type EffectFrame struct {
+	Repeats   int                        // The number of times the frame is repeated.
+	BodyParts map[string]EffectFramePart // Maps body part overrides by body part ID.
+	Fx        map[string]EffectFramePart // Maps sprite states by sprite ID.
+}
+
+// An EffectFramePart defines the action, frame and offset of a body part or sprite during an effect frame.
+type EffectFramePart struct {
+	Action          string
+	Frame           int
+	Offset          image.Point
+	DirectionOffset int // The offset applied to the direction of the body part or sprite.
+}
+
+// Unmarshals an effect animation XML document as raw bytes into an EffectAnimation.
+func (anim *EffectAnimation) UnmarshalBytes(b []byte) (err error) {
+	var xAnim x.EffectAnimation
+	err = decodeXml(b, &xAnim)
+	if err != nil {
+		return
+	}
+	anim.fromXml(&xAnim)
+	return
+}
+
+func (anim *EffectAnimation) fromXml(v *x.EffectAnimation) {
+	*anim = EffectAnimation{}
+	for _, xSprite := range v.Sprites {
+		sprite := EffectSprite{
+			Id:          xSprite.Id,
+			Member:      xSprite.Member,
+			Directional: xSprite.Directions,
+			Ink:         xSprite.Ink,
+			StaticY:     xSprite.StaticY,
+			Offsets:     map[int]EffectSpriteOffset{},
+		}
+		for _, xOffset := range xSprite.Offsets {
+			sprite.Offsets[xOffset.Id] = EffectSpriteOffset{xOffset.Dx, xOffset.Dy, xOffset.Dz}
+		}
+		anim.Sprites = append(anim.Sprites, sprite)
+	}
+	for _, xAdd := range v.Adds {
+		anim.Adds = append(anim.Adds, EffectAdd{xAdd.Id, xAdd.Align, xAdd.Base})
+	}
+	for _, xRemove := range v.Removes {
+		anim.Removes = append(anim.Removes, xRemove.Id)
+	}
+	if v.Shadow != nil {
+		anim.Shadow = v.Shadow.Id
+	}
+	if v.Direction != nil {
+		anim.DirectionOffset = v.Direction.Offset
+	}
+	for _, xFrame := range v.Frames {
+		frame := EffectFrame{
+			Repeats:   xFrame.Repeats,
+			BodyParts: map[string]EffectFramePart{},
+			Fx:        map[string]EffectFramePart{},
+		}
+		for _, xPart := range xFrame.BodyParts {
+			frame.BodyParts[xPart.Id] = effectFramePartFromXml(xPart)
+		}
+		for _, xPart := range xFrame.Fx {
+			frame.Fx[xPart.Id] = effectFramePartFromXml(xPart)
+		}
+		anim.Frames = append(anim.Frames, frame)
+	}
+}
+
+func effectFramePartFromXml(v x.EffectFramePart) EffectFramePart {
+	return EffectFramePart{
+		Action:          v.Action,
+		Frame:           v.Frame,
+		Offset:          image.Pt(v.Dx, v.Dy),
+		DirectionOffset: v.Dd,
+	}
+}
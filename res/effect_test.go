@@ -0,0 +1,40 @@
+package res
+
+import (
+	"image"
+	"testing"
+)
+
+func TestUnmarshalEffectAnimation(t *testing.T) {
+	data := `<animation desc="Hoverboard">
+		<sprite id="board" member="fx1_hoverboard" directions="1" ink="33">
+			<direction id="2" dz="-1"/>
+		</sprite>
+		<remove id="sh"/>
+		<frame repeats="2">
+			<bodypart id="torso" action="std" frame="0" dy="-4"/>
+			<fx id="board" action="std" frame="1"/>
+		</frame>
+	</animation>`
+
+	var anim EffectAnimation
+	err := anim.UnmarshalBytes([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Sprites) != 1 || !anim.Sprites[0].Directional || anim.Sprites[0].Offsets[2].Dz != -1 {
+		t.Fatalf("unexpected sprites: %+v", anim.Sprites)
+	}
+	if len(anim.Removes) != 1 || anim.Removes[0] != "sh" {
+		t.Fatalf("removes are %v (expected [sh])", anim.Removes)
+	}
+	if len(anim.Frames) != 1 || anim.Frames[0].Repeats != 2 {
+		t.Fatalf("unexpected frames: %+v", anim.Frames)
+	}
+	if offset := anim.Frames[0].BodyParts["torso"].Offset; offset != image.Pt(0, -4) {
+		t.Fatalf("torso offset is %v (expected (0,-4))", offset)
+	}
+	if fx := anim.Frames[0].Fx["board"]; fx.Frame != 1 {
+		t.Fatalf("board frame is %d (expected 1)", fx.Frame)
+	}
+}
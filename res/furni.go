@@ -1,6 +1,7 @@
 package res
 
 import (
+	"fmt"
 	"slices"
 	"strconv"
 
@@ -62,6 +63,17 @@ func (visualizationData *VisualizationData) fromXml(v *x.VisualizationData) {
 	}
 }
 
+func (visualizationData *VisualizationData) toXml() *x.VisualizationData {
+	v := &x.VisualizationData{Type: visualizationData.Type}
+	sizes := maps.Keys(visualizationData.Visualizations)
+	slices.Sort(sizes)
+	for _, size := range sizes {
+		v.Graphics.Visualizations = append(v.Graphics.Visualizations,
+			visualizationData.Visualizations[size].toXml())
+	}
+	return v
+}
+
 func (visualizations Visualizations) fromNitro(v []nitro.Visualization) Visualizations {
 	visualizations = Visualizations{}
 	for i := range v {
@@ -70,6 +82,21 @@ func (visualizations Visualizations) fromNitro(v []nitro.Visualization) Visualiz
 	return visualizations
 }
 
+func (visualizations Visualizations) toNitro() (v []nitro.Visualization, err error) {
+	sizes := maps.Keys(visualizations)
+	slices.Sort(sizes)
+	v = make([]nitro.Visualization, 0, len(sizes))
+	for _, size := range sizes {
+		var vis nitro.Visualization
+		vis, err = visualizations[size].toNitro()
+		if err != nil {
+			return
+		}
+		v = append(v, vis)
+	}
+	return
+}
+
 type Visualization struct {
 	Size       int
 	LayerCount int
@@ -117,8 +144,40 @@ func (vis *Visualization) fromXml(v *x.Visualization) {
 		}
 	}
 	for from, to := range transitions {
-		vis.Animations[from].TransitionTo = vis.Animations[to]
+		vis.Animations[from].TransitionTo = vis.transitionTarget(to)
+	}
+}
+
+// transitionTarget returns the animation with the specified ID.
+// If it does not exist, a placeholder animation that is not in the visualization is returned.
+func (vis *Visualization) transitionTarget(id int) *Animation {
+	if anim, ok := vis.Animations[id]; ok {
+		return anim
 	}
+	return &Animation{Id: id}
+}
+
+func (vis *Visualization) toXml() x.Visualization {
+	v := x.Visualization{
+		Size:       vis.Size,
+		LayerCount: vis.LayerCount,
+		Angle:      vis.Angle,
+	}
+
+	for _, id := range sortedKeys(vis.Layers) {
+		v.Layers = append(v.Layers, vis.Layers[id].toXml())
+	}
+	for _, dir := range sortedKeys(vis.Directions) {
+		v.Directions = append(v.Directions, x.Direction{Id: dir})
+	}
+	for _, id := range sortedKeys(vis.Colors) {
+		v.Colors = append(v.Colors, vis.Colors[id].toXml())
+	}
+	for _, id := range sortedKeys(vis.Animations) {
+		v.Animations = append(v.Animations, vis.Animations[id].toXml())
+	}
+
+	return v
 }
 
 func (vis *Visualization) fromNitro(v *nitro.Visualization) *Visualization {
@@ -150,16 +209,57 @@ func (vis *Visualization) fromNitro(v *nitro.Visualization) *Visualization {
 		}
 	}
 	for from, to := range transitions {
-		vis.Animations[from].TransitionTo = vis.Animations[to]
+		vis.Animations[from].TransitionTo = vis.transitionTarget(to)
 	}
 
 	return vis
 }
 
+func (vis *Visualization) toNitro() (v nitro.Visualization, err error) {
+	v = nitro.Visualization{
+		Size:       vis.Size,
+		LayerCount: vis.LayerCount,
+		Angle:      vis.Angle,
+	}
+
+	if len(vis.Directions) > 0 {
+		v.Directions = make(map[int]nitro.Direction, len(vis.Directions))
+		for dir := range vis.Directions {
+			v.Directions[dir] = nitro.Direction{}
+		}
+	}
+
+	if len(vis.Layers) > 0 {
+		v.Layers = make(map[int]nitro.Layer, len(vis.Layers))
+		for id, layer := range vis.Layers {
+			v.Layers[id] = layer.toNitro()
+		}
+	}
+
+	if len(vis.Colors) > 0 {
+		v.Colors = make(map[int]nitro.Color, len(vis.Colors))
+		for id, color := range vis.Colors {
+			v.Colors[id], err = color.toNitro()
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if len(vis.Animations) > 0 {
+		v.Animations = make(map[int]nitro.Animation, len(vis.Animations))
+		for id, anim := range vis.Animations {
+			v.Animations[id] = anim.toNitro()
+		}
+	}
+
+	return
+}
+
 type Layer struct {
 	Id          int
 	Z           int
-	Alpha       int
+	Alpha       *int // The layer opacity, or nil if the layer is opaque.
 	Ink         string
 	IgnoreMouse bool
 	Color       int
@@ -174,16 +274,43 @@ func (layer *Layer) fromXml(v *x.Layer) {
 	layer.Color = v.Color
 }
 
+func (layer *Layer) toXml() x.Layer {
+	return x.Layer{
+		Id:          layer.Id,
+		Z:           layer.Z,
+		Alpha:       layer.Alpha,
+		Ink:         layer.Ink,
+		IgnoreMouse: layer.IgnoreMouse,
+		Color:       layer.Color,
+	}
+}
+
 func (layer *Layer) fromNitro(id int, v nitro.Layer) *Layer {
 	layer.Id = id
 	layer.Z = int(v.Z)
 	layer.Alpha = v.Alpha
 	layer.Ink = v.Ink
 	layer.IgnoreMouse = v.IgnoreMouse
-	layer.Color = v.Color
+	if v.Color != nil {
+		layer.Color = *v.Color
+	}
 	return layer
 }
 
+func (layer *Layer) toNitro() (v nitro.Layer) {
+	v = nitro.Layer{
+		Z:           float64(layer.Z),
+		Alpha:       layer.Alpha,
+		Ink:         layer.Ink,
+		IgnoreMouse: layer.IgnoreMouse,
+	}
+	if layer.Color != 0 {
+		color := layer.Color
+		v.Color = &color
+	}
+	return
+}
+
 type Color struct {
 	Id     int
 	Layers map[int]*ColorLayer
@@ -199,6 +326,14 @@ func (color *Color) fromXml(v *x.Color) {
 	}
 }
 
+func (color *Color) toXml() x.Color {
+	v := x.Color{Id: color.Id}
+	for _, id := range sortedKeys(color.Layers) {
+		v.Layers = append(v.Layers, x.ColorLayer{Id: id, Color: color.Layers[id].Color})
+	}
+	return v
+}
+
 func (color *Color) fromNitro(id int, v nitro.Color) *Color {
 	*color = Color{
 		Id:     id,
@@ -210,6 +345,18 @@ func (color *Color) fromNitro(id int, v nitro.Color) *Color {
 	return color
 }
 
+func (color *Color) toNitro() (v nitro.Color, err error) {
+	v = nitro.Color{Layers: make(map[int]nitro.Layer, len(color.Layers))}
+	for id, layer := range color.Layers {
+		v.Layers[id], err = layer.toNitro()
+		if err != nil {
+			err = fmt.Errorf("color %d: %w", color.Id, err)
+			return
+		}
+	}
+	return
+}
+
 type ColorLayer struct {
 	Id    int
 	Color string
@@ -222,12 +369,27 @@ func (colorLayer *ColorLayer) fromXml(v *x.ColorLayer) {
 
 func (colorLayer *ColorLayer) fromNitro(id int, v nitro.Layer) *ColorLayer {
 	*colorLayer = ColorLayer{Id: id}
-	colorLayer.Color = strconv.FormatInt(int64(v.Color), 16)
+	if v.Color != nil {
+		colorLayer.Color = strconv.FormatInt(int64(*v.Color), 16)
+	}
 	return colorLayer
 }
 
+func (colorLayer *ColorLayer) toNitro() (v nitro.Layer, err error) {
+	color, err := strconv.ParseInt(colorLayer.Color, 16, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid color %q for layer %d", colorLayer.Color, colorLayer.Id)
+		return
+	}
+	v.Color = new(int)
+	*v.Color = int(color)
+	return
+}
+
 type Animation struct {
-	Id           int
+	Id int
+	// The animation to transition to after this animation completes.
+	// If the target animation is not defined in the visualization, this is a placeholder animation with no layers.
 	TransitionTo *Animation
 	Layers       map[int]*AnimationLayer
 }
@@ -242,6 +404,18 @@ func (anim *Animation) fromXml(v *x.Animation) {
 	}
 }
 
+func (anim *Animation) toXml() x.Animation {
+	v := x.Animation{Id: anim.Id}
+	if anim.TransitionTo != nil {
+		transitionTo := anim.TransitionTo.Id
+		v.TransitionTo = &transitionTo
+	}
+	for _, id := range sortedKeys(anim.Layers) {
+		v.Layers = append(v.Layers, anim.Layers[id].toXml())
+	}
+	return v
+}
+
 func (anim *Animation) fromNitro(id int, v nitro.Animation) *Animation {
 	*anim = Animation{
 		Id:     id,
@@ -253,6 +427,18 @@ func (anim *Animation) fromNitro(id int, v nitro.Animation) *Animation {
 	return anim
 }
 
+func (anim *Animation) toNitro() nitro.Animation {
+	v := nitro.Animation{Layers: make(map[int]nitro.AnimationLayer, len(anim.Layers))}
+	for id, layer := range anim.Layers {
+		v.Layers[id] = layer.toNitro()
+	}
+	if anim.TransitionTo != nil {
+		transitionTo := anim.TransitionTo.Id
+		v.TransitionTo = &transitionTo
+	}
+	return v
+}
+
 type AnimationLayer struct {
 	Id             int
 	LoopCount      int
@@ -279,6 +465,23 @@ func (animLayer *AnimationLayer) fromXml(v *x.AnimationLayer) {
 	}
 }
 
+func (animLayer *AnimationLayer) toXml() x.AnimationLayer {
+	v := x.AnimationLayer{
+		Id:          animLayer.Id,
+		LoopCount:   animLayer.LoopCount,
+		FrameRepeat: animLayer.FrameRepeat,
+		Random:      animLayer.Random,
+	}
+	for _, sequence := range animLayer.FrameSequences {
+		var xSequence x.FrameSequence
+		for _, frameId := range sequence {
+			xSequence.Frames = append(xSequence.Frames, x.AnimationFrame{Id: frameId})
+		}
+		v.FrameSequences = append(v.FrameSequences, xSequence)
+	}
+	return v
+}
+
 func (layer *AnimationLayer) fromNitro(id int, v nitro.AnimationLayer) *AnimationLayer {
 	*layer = AnimationLayer{
 		Id:             id,
@@ -299,6 +502,23 @@ func (layer *AnimationLayer) fromNitro(id int, v nitro.AnimationLayer) *Animatio
 	return layer
 }
 
+func (layer *AnimationLayer) toNitro() nitro.AnimationLayer {
+	v := nitro.AnimationLayer{
+		LoopCount:      float64(layer.LoopCount),
+		FrameRepeat:    float64(layer.FrameRepeat),
+		Random:         float64(layer.Random),
+		FrameSequences: make(map[int]nitro.FrameSequence, len(layer.FrameSequences)),
+	}
+	for i, sequence := range layer.FrameSequences {
+		frames := make(map[int]nitro.AnimationFrame, len(sequence))
+		for j, frameId := range sequence {
+			frames[j] = nitro.AnimationFrame{Id: frameId}
+		}
+		v.FrameSequences[i] = nitro.FrameSequence{Frames: frames}
+	}
+	return v
+}
+
 // logic
 
 type Logic struct {
@@ -331,6 +551,17 @@ func (logic *Logic) fromXml(v *x.Logic) {
 	}
 }
 
+func (logic *Logic) toXml() *x.Logic {
+	v := &x.Logic{Type: logic.Type}
+	if logic.Model != nil {
+		v.Model = logic.Model.toXml()
+	}
+	for _, size := range sortedKeys(logic.ParticleSystems) {
+		v.ParticleSystems = append(v.ParticleSystems, x.ParticleSystem{Size: size})
+	}
+	return v
+}
+
 func (logic *Logic) fromNitro(v *nitro.Logic) *Logic {
 	*logic = Logic{
 		Model:           new(Model).fromNitro(v.Model),
@@ -342,6 +573,19 @@ func (logic *Logic) fromNitro(v *nitro.Logic) *Logic {
 	return logic
 }
 
+func (logic *Logic) toNitro() nitro.Logic {
+	var v nitro.Logic
+	if logic.Model != nil {
+		v.Model = logic.Model.toNitro()
+	}
+	sizes := maps.Keys(logic.ParticleSystems)
+	slices.Sort(sizes)
+	for _, size := range sizes {
+		v.ParticleSystems = append(v.ParticleSystems, nitro.ParticleSystem{Size: size})
+	}
+	return v
+}
+
 type ParticleSystem struct {
 	Size int
 }
@@ -371,6 +615,16 @@ func (model *Model) fromXml(v *x.Model) *Model {
 	return model
 }
 
+func (model *Model) toXml() x.Model {
+	v := x.Model{
+		Dimensions: x.Dimensions{X: model.Dimensions.X, Y: model.Dimensions.Y, Z: model.Dimensions.Z},
+	}
+	for _, dir := range model.Directions {
+		v.Directions = append(v.Directions, x.Direction{Id: dir})
+	}
+	return v
+}
+
 func (model *Model) fromNitro(v nitro.Model) *Model {
 	*model = Model{
 		Dimensions: *new(Dimensions).fromNitro(v.Dimensions),
@@ -382,6 +636,13 @@ func (model *Model) fromNitro(v nitro.Model) *Model {
 	return model
 }
 
+func (model *Model) toNitro() nitro.Model {
+	return nitro.Model{
+		Dimensions: nitro.Dimensions{X: model.Dimensions.X, Y: model.Dimensions.Y, Z: model.Dimensions.Z},
+		Directions: slices.Clone(model.Directions),
+	}
+}
+
 type Dimensions struct {
 	X float64
 	Y float64
@@ -396,3 +657,10 @@ func (dimensions *Dimensions) fromNitro(v nitro.Dimensions) *Dimensions {
 	*dimensions = Dimensions{v.X, v.Y, v.Z}
 	return dimensions
 }
+
+// sortedKeys returns the keys of a map in ascending order.
+func sortedKeys[V any](m map[int]V) []int {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
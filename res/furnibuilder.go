@@ -0,0 +1,336 @@
+package res
+
+import (
+	"errors"
+	"fmt"
+	"image/png"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type builtFurniLibrary struct {
+	name           string
+	index          *Index
+	manifest       *Manifest
+	logic          *Logic
+	visualizations Visualizations
+	assets         Assets
+}
+
+// BuildFurniLibrary builds a furni library from a spec and a file system containing PNG images.
+// Each image must be named after its asset in the form <name>_<size>_<layer>_<direction>_<frame>.png,
+// for example chair_64_a_0_0.png, where the layer is a letter from a-z, or sd for the shadow layer.
+// Icons may be included in the form <name>_icon_<layer>.png, for example chair_icon_a.png.
+// Each image other than an icon must have an entry in the spec's assets defining its offset.
+// The spec and the assets are validated, and all errors found are joined into the returned error.
+func BuildFurniLibrary(spec FurniSpec, fsys fs.FS) (lib FurniLibrary, err error) {
+	spec.applyDefaults()
+	if err = spec.Validate(); err != nil {
+		return
+	}
+
+	var errs []error
+	fail := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	assets := Assets{}
+	specs := map[string]FurniAssetSpec{}
+
+	// load images
+
+	matches, err := fs.Glob(fsys, "*.png")
+	if err != nil {
+		return
+	}
+	for _, fileName := range matches {
+		assetName := strings.TrimSuffix(path.Base(fileName), ".png")
+		icon := false
+		assetSpec, ok := parseFurniAssetSpec(spec.Name, assetName)
+		if !ok {
+			var layer int
+			if layer, icon = parseFurniIconAsset(spec.Name, assetName); !icon {
+				fail("%s: invalid asset name, expected %s_<size>_<layer>_<direction>_<frame> or %s_icon_<layer>",
+					fileName, spec.Name, spec.Name)
+				continue
+			}
+			if layer >= spec.LayerCount {
+				fail("%s: layer %d is out of range", fileName, layer)
+				continue
+			}
+		} else if err := spec.checkAssetSpec(assetSpec); err != nil {
+			fail("%s: %w", fileName, err)
+			continue
+		}
+
+		f, err := fsys.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			fail("%s: %w", fileName, err)
+			continue
+		}
+
+		assets[assetName] = &Asset{Name: assetName, Image: img}
+		if icon {
+			continue
+		}
+		if _, ok := spec.Assets[assetName]; !ok {
+			fail("%s: no offset defined in assets", fileName)
+		}
+		specs[assetName] = assetSpec
+	}
+
+	// apply asset definitions
+
+	defNames := maps.Keys(spec.Assets)
+	slices.Sort(defNames)
+	for _, assetName := range defNames {
+		def := spec.Assets[assetName]
+		asset, exists := assets[assetName]
+		if def.Source != "" {
+			if exists {
+				fail("asset %q has both an image and a source", assetName)
+				continue
+			}
+			asset = &Asset{Name: assetName}
+			if _, icon := parseFurniIconAsset(spec.Name, assetName); !icon {
+				assetSpec, ok := parseFurniAssetSpec(spec.Name, assetName)
+				if !ok {
+					fail("asset %q: invalid asset name", assetName)
+					continue
+				}
+				if err := spec.checkAssetSpec(assetSpec); err != nil {
+					fail("asset %q: %w", assetName, err)
+					continue
+				}
+				specs[assetName] = assetSpec
+			}
+			assets[assetName] = asset
+		} else if !exists {
+			fail("asset %q has no image or source", assetName)
+			continue
+		}
+		asset.Offset.X, asset.Offset.Y = def.X, def.Y
+		asset.FlipH, asset.FlipV = def.FlipH, def.FlipV
+	}
+
+	for _, assetName := range defNames {
+		def := spec.Assets[assetName]
+		if def.Source == "" || assets[assetName] == nil {
+			continue
+		}
+		source, ok := assets[def.Source]
+		if !ok {
+			fail("asset %q: source %q not found", assetName, def.Source)
+			continue
+		}
+		assets[assetName].Source = source
+	}
+	for _, assetName := range defNames {
+		asset := assets[assetName]
+		if asset == nil || asset.Source == nil {
+			continue
+		}
+		visited := map[*Asset]bool{}
+		for asset.Source != nil && !visited[asset] {
+			visited[asset] = true
+			asset = asset.Source
+		}
+		if asset.Source != nil {
+			fail("asset %q: cyclic source chain", assetName)
+		} else if asset.Image == nil {
+			fail("asset %q: source chain does not lead to an image", assetName)
+		}
+	}
+
+	// check that each direction has assets, and that each animation frame exists
+
+	hasAssets := map[[3]int]bool{} // size, layer, direction
+	for _, assetSpec := range specs {
+		hasAssets[[3]int{assetSpec.Size, assetSpec.Layer, assetSpec.Direction}] = true
+	}
+	for _, size := range spec.Sizes {
+		for _, dir := range spec.Directions {
+			found := false
+			for layer := -1; layer < spec.LayerCount && !found; layer++ {
+				found = hasAssets[[3]int{size, layer, dir}]
+			}
+			if !found {
+				fail("no assets for size %d direction %d", size, dir)
+			}
+		}
+	}
+	for _, animId := range sortedKeys(spec.Animations) {
+		anim := spec.Animations[animId]
+		for _, layerId := range sortedKeys(anim.Layers) {
+			for _, size := range spec.Sizes {
+				for _, dir := range spec.Directions {
+					if !hasAssets[[3]int{size, layerId, dir}] {
+						continue
+					}
+					for _, sequence := range anim.Layers[layerId].FrameSequences {
+						for _, frame := range sequence {
+							assetSpec := FurniAssetSpec{spec.Name, size, layerId, dir, frame}
+							if _, ok := assets[assetSpec.String()]; !ok {
+								fail("animation %d: asset %q not found", animId, assetSpec.String())
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		err = errors.Join(errs...)
+		return
+	}
+
+	built := &builtFurniLibrary{
+		name: spec.Name,
+		index: &Index{
+			Type:          spec.Name,
+			Visualization: spec.Visualization,
+			Logic:         spec.Logic,
+		},
+		manifest: &Manifest{
+			Name:    spec.Name,
+			Version: "0.1",
+			Assets:  assets,
+		},
+		logic: &Logic{
+			Type: spec.Name,
+			Model: &Model{
+				Dimensions: Dimensions{spec.Dimensions.X, spec.Dimensions.Y, spec.Dimensions.Z},
+			},
+			ParticleSystems: map[int]*ParticleSystem{},
+		},
+		visualizations: Visualizations{},
+		assets:         assets,
+	}
+	for _, dir := range spec.Directions {
+		built.logic.Model.Directions = append(built.logic.Model.Directions, dir*45)
+	}
+	for _, size := range spec.Sizes {
+		built.visualizations[size] = spec.buildVisualization(size)
+	}
+
+	lib = built
+	return
+}
+
+// checkAssetSpec checks whether an asset matches the sizes, layers and directions of the spec.
+func (spec *FurniSpec) checkAssetSpec(assetSpec FurniAssetSpec) error {
+	switch {
+	case !slices.Contains(spec.Sizes, assetSpec.Size):
+		return fmt.Errorf("size %d is not defined", assetSpec.Size)
+	case assetSpec.Layer >= spec.LayerCount:
+		return fmt.Errorf("layer %d is out of range", assetSpec.Layer)
+	case !slices.Contains(spec.Directions, assetSpec.Direction):
+		return fmt.Errorf("direction %d is not defined", assetSpec.Direction)
+	}
+	return nil
+}
+
+func (spec *FurniSpec) buildVisualization(size int) *Visualization {
+	vis := &Visualization{
+		Size:       size,
+		LayerCount: spec.LayerCount,
+		Angle:      45,
+		Directions: map[int]struct{}{},
+		Layers:     map[int]*Layer{},
+		Colors:     map[int]*Color{},
+		Animations: map[int]*Animation{},
+	}
+
+	for _, dir := range spec.Directions {
+		vis.Directions[dir] = struct{}{}
+	}
+
+	for id, specLayer := range spec.Layers {
+		vis.Layers[id] = &Layer{
+			Id:          id,
+			Z:           specLayer.Z,
+			Alpha:       specLayer.Alpha,
+			Ink:         specLayer.Ink,
+			IgnoreMouse: specLayer.IgnoreMouse,
+		}
+	}
+
+	for id, specColor := range spec.Colors {
+		color := &Color{Id: id, Layers: map[int]*ColorLayer{}}
+		for layerId, hex := range specColor {
+			color.Layers[layerId] = &ColorLayer{Id: layerId, Color: strings.ToUpper(hex)}
+		}
+		vis.Colors[id] = color
+	}
+
+	for id, specAnim := range spec.Animations {
+		anim := &Animation{Id: id, Layers: map[int]*AnimationLayer{}}
+		for layerId, specLayer := range specAnim.Layers {
+			animLayer := &AnimationLayer{
+				Id:          layerId,
+				LoopCount:   specLayer.LoopCount,
+				FrameRepeat: specLayer.FrameRepeat,
+				Random:      specLayer.Random,
+			}
+			for _, sequence := range specLayer.FrameSequences {
+				animLayer.FrameSequences = append(animLayer.FrameSequences, FrameSequence(sequence))
+			}
+			anim.Layers[layerId] = animLayer
+		}
+		vis.Animations[id] = anim
+	}
+	for id, specAnim := range spec.Animations {
+		if specAnim.TransitionTo != nil {
+			vis.Animations[id].TransitionTo = vis.Animations[*specAnim.TransitionTo]
+		}
+	}
+
+	return vis
+}
+
+func (lib *builtFurniLibrary) Name() string {
+	return lib.name
+}
+
+func (lib *builtFurniLibrary) Index() *Index {
+	return lib.index
+}
+
+func (lib *builtFurniLibrary) Manifest() *Manifest {
+	return lib.manifest
+}
+
+func (lib *builtFurniLibrary) Logic() *Logic {
+	return lib.logic
+}
+
+func (lib *builtFurniLibrary) Visualizations() map[int]*Visualization {
+	return lib.visualizations
+}
+
+func (lib *builtFurniLibrary) Asset(name string) (asset *Asset, err error) {
+	asset, ok := lib.assets[name]
+	if !ok {
+		err = fmt.Errorf("asset %q not found in library %q", name, lib.name)
+	}
+	return
+}
+
+func (lib *builtFurniLibrary) Assets() []string {
+	return maps.Keys(lib.assets)
+}
+
+func (lib *builtFurniLibrary) AssetExists(name string) bool {
+	_, exists := lib.assets[name]
+	return exists
+}
@@ -0,0 +1,222 @@
+package res
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func encodeTestPng(t *testing.T, w, h int) *fstest.MapFile {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return &fstest.MapFile{Data: buf.Bytes()}
+}
+
+const testFurniSpec = `
+name: lamp
+directions: [0, 2]
+layer_count: 2
+layers:
+  1: { z: 1, ink: ADD, alpha: 128 }
+colors:
+  1: { 0: ff0000 }
+animations:
+  0: { layers: { 1: { frame_sequences: [[0]] } } }
+  1:
+    transition_to: 0
+    layers: { 1: { frame_repeat: 2, frame_sequences: [[0, 1]] } }
+assets:
+  lamp_64_a_0_0: { x: -10, y: 20 }
+  lamp_64_b_0_0: { x: -4, y: 12 }
+  lamp_64_b_0_1: { x: -4, y: 13 }
+  lamp_64_sd_0_0: { x: -10, y: 2 }
+  lamp_64_a_2_0: { source: lamp_64_a_0_0, flip_h: true, x: 10, y: 20 }
+  lamp_64_b_2_0: { source: lamp_64_b_0_0, flip_h: true }
+  lamp_64_b_2_1: { source: lamp_64_b_0_1, flip_h: true }
+`
+
+func testFurniFS(t *testing.T) fstest.MapFS {
+	return fstest.MapFS{
+		"lamp_64_a_0_0.png":  encodeTestPng(t, 4, 6),
+		"lamp_64_b_0_0.png":  encodeTestPng(t, 2, 2),
+		"lamp_64_b_0_1.png":  encodeTestPng(t, 2, 3),
+		"lamp_64_sd_0_0.png": encodeTestPng(t, 4, 2),
+		"lamp_icon_a.png":    encodeTestPng(t, 4, 4),
+	}
+}
+
+func loadTestFurniSpec(t *testing.T, data string) FurniSpec {
+	t.Helper()
+	var spec FurniSpec
+	if err := spec.UnmarshalBytes([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestBuildFurniLibrary(t *testing.T) {
+	lib, err := BuildFurniLibrary(loadTestFurniSpec(t, testFurniSpec), testFurniFS(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *lib.Index() != (Index{Type: "lamp", Visualization: "furniture_animated", Logic: "furniture_basic"}) {
+		t.Fatalf("unexpected index: %+v", lib.Index())
+	}
+	if !reflect.DeepEqual(lib.Logic().Model.Directions, []int{0, 90}) {
+		t.Fatalf("unexpected directions: %v", lib.Logic().Model.Directions)
+	}
+
+	vis := lib.Visualizations()[64]
+	if vis == nil || vis.LayerCount != 2 || len(vis.Directions) != 2 {
+		t.Fatalf("unexpected visualization: %+v", vis)
+	}
+	if layer := vis.Layers[1]; layer == nil || layer.Ink != "ADD" || layer.Alpha == nil || *layer.Alpha != 128 || layer.Z != 1 {
+		t.Fatalf("unexpected layer: %+v", layer)
+	}
+	if c := vis.Colors[1]; c == nil || c.Layers[0].Color != "FF0000" {
+		t.Fatalf("unexpected color: %+v", c)
+	}
+	if anim := vis.Animations[1]; anim == nil || anim.TransitionTo != vis.Animations[0] {
+		t.Fatalf("unexpected animation: %+v", anim)
+	}
+
+	asset, err := lib.Asset("lamp_64_a_2_0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Source == nil || asset.Source.Name != "lamp_64_a_0_0" || !asset.FlipH || asset.Offset != image.Pt(10, 20) {
+		t.Fatalf("unexpected asset: %+v", asset)
+	}
+	if !lib.AssetExists("lamp_icon_a") {
+		t.Fatalf("icon asset not found")
+	}
+	if len(lib.Assets()) != 8 {
+		t.Fatalf("expected 8 assets, got %d", len(lib.Assets()))
+	}
+}
+
+func TestBuildFurniLibraryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected string
+	}{
+		{
+			name:     "invalid ink",
+			spec:     "layers: { 0: { ink: GLOW } }",
+			expected: `layer 0: invalid ink "GLOW"`,
+		},
+		{
+			name:     "missing frame",
+			spec:     "animations: { 0: { layers: { 1: { frame_sequences: [[0, 2]] } } } }",
+			expected: `animation 0: asset "lamp_64_b_0_2" not found`,
+		},
+		{
+			name:     "undefined transition",
+			spec:     "animations: { 0: { transition_to: 3 } }",
+			expected: "animation 0: transition to undefined animation 3",
+		},
+		{
+			name:     "missing direction",
+			spec:     "sizes: [32, 64]",
+			expected: "no assets for size 32 direction 0",
+		},
+		{
+			name:     "cyclic source",
+			spec:     "assets: { lamp_64_a_2_0: { source: lamp_64_a_2_1 }, lamp_64_a_2_1: { source: lamp_64_a_2_0 } }",
+			expected: `asset "lamp_64_a_2_0": cyclic source chain`,
+		},
+		{
+			name:     "missing offset",
+			spec:     "assets: { lamp_64_a_0_0: { x: -10, y: 20 } }",
+			expected: "lamp_64_b_0_0.png: no offset defined in assets",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := loadTestFurniSpec(t, "name: lamp\ndirections: [0, 2]\nlayer_count: 2\n"+test.spec)
+			if spec.Assets == nil {
+				spec.Assets = map[string]FurniSpecAsset{}
+			}
+			if _, ok := spec.Assets["lamp_64_a_2_0"]; !ok {
+				spec.Assets["lamp_64_a_2_0"] = FurniSpecAsset{Source: "lamp_64_a_0_0", FlipH: true}
+			}
+			_, err := BuildFurniLibrary(spec, testFurniFS(t))
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected error containing %q, got %q", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestExportFurniLibraryXml(t *testing.T) {
+	lib, err := BuildFurniLibrary(loadTestFurniSpec(t, testFurniSpec), testFurniFS(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ExportFurniLibraryXml(lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"lamp_index.xml", "lamp_manifest.xml", "lamp_lamp_logic.xml",
+		"lamp_lamp_visualization.xml", "lamp_lamp_assets.xml", "lamp_lamp_64_a_0_0.png"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing file %q", name)
+		}
+	}
+	if _, ok := files["lamp_lamp_64_a_2_0.png"]; ok {
+		t.Fatalf("unexpected image for sourced asset")
+	}
+
+	var index Index
+	if err := index.UnmarshalBytes(files["lamp_index.xml"]); err != nil {
+		t.Fatal(err)
+	}
+	if index != *lib.Index() {
+		t.Fatalf("unexpected index: %+v", index)
+	}
+
+	var logic Logic
+	if err := logic.UnmarshalBytes(files["lamp_lamp_logic.xml"]); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(logic.Model, lib.Logic().Model) {
+		t.Fatalf("unexpected model: %+v", logic.Model)
+	}
+
+	var visData VisualizationData
+	if err := visData.UnmarshalBytes(files["lamp_lamp_visualization.xml"]); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(map[int]*Visualization(visData.Visualizations), lib.Visualizations()) {
+		t.Fatalf("visualizations do not match")
+	}
+
+	var assets Assets
+	if err := assets.UnmarshalBytes(files["lamp_lamp_assets.xml"]); err != nil {
+		t.Fatal(err)
+	}
+	if len(assets) != len(lib.Assets()) {
+		t.Fatalf("expected %d assets, got %d", len(lib.Assets()), len(assets))
+	}
+	if a := assets["lamp_64_a_2_0"]; a == nil || a.Source == nil || a.Source.Name != "lamp_64_a_0_0" ||
+		!a.FlipH || a.Offset != image.Pt(10, 20) {
+		t.Fatalf("unexpected asset: %+v", a)
+	}
+}
@@ -0,0 +1,258 @@
+package res
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A FurniSpec declaratively describes a furni library to be built from a set of PNG images.
+type FurniSpec struct {
+	Name          string `json:"name" yaml:"name"`                   // Name is the name of the library.
+	Logic         string `json:"logic" yaml:"logic"`                 // Logic is the logic type. Defaults to furniture_basic.
+	Visualization string `json:"visualization" yaml:"visualization"` // Visualization is the visualization type. Defaults to furniture_static, or furniture_animated if animations are defined.
+	// Dimensions defines the size of the furni in tiles. The X and Y dimensions default to 1.
+	Dimensions FurniSpecDimensions `json:"dimensions" yaml:"dimensions"`
+	// Directions defines the directions of the furni, from 0 to 7.
+	Directions []int `json:"directions" yaml:"directions"`
+	// Sizes defines the visualization sizes to build. Defaults to 64.
+	Sizes      []int                      `json:"sizes" yaml:"sizes"`
+	LayerCount int                        `json:"layer_count" yaml:"layer_count"`
+	Layers     map[int]FurniSpecLayer     `json:"layers" yaml:"layers"`
+	Colors     map[int]map[int]string     `json:"colors" yaml:"colors"` // Colors maps color IDs to layer IDs to hex colors.
+	Animations map[int]FurniSpecAnimation `json:"animations" yaml:"animations"`
+	Assets     map[string]FurniSpecAsset  `json:"assets" yaml:"assets"` // Assets defines the offsets and sources of assets by name.
+}
+
+// FurniSpecDimensions defines the size of a furni in tiles.
+type FurniSpecDimensions struct {
+	X float64 `json:"x" yaml:"x"`
+	Y float64 `json:"y" yaml:"y"`
+	Z float64 `json:"z" yaml:"z"`
+}
+
+// FurniSpecLayer defines the properties of a visualization layer.
+type FurniSpecLayer struct {
+	Z           int    `json:"z" yaml:"z"`
+	Alpha       *int   `json:"alpha" yaml:"alpha"`
+	Ink         string `json:"ink" yaml:"ink"`
+	IgnoreMouse bool   `json:"ignore_mouse" yaml:"ignore_mouse"`
+}
+
+// FurniSpecAnimation defines an animation, or state, of a furni.
+type FurniSpecAnimation struct {
+	TransitionTo *int                            `json:"transition_to" yaml:"transition_to"`
+	Layers       map[int]FurniSpecAnimationLayer `json:"layers" yaml:"layers"`
+}
+
+// FurniSpecAnimationLayer defines the frame sequences of a layer within an animation.
+type FurniSpecAnimationLayer struct {
+	LoopCount      int     `json:"loop_count" yaml:"loop_count"`
+	FrameRepeat    int     `json:"frame_repeat" yaml:"frame_repeat"`
+	Random         int     `json:"random" yaml:"random"`
+	FrameSequences [][]int `json:"frame_sequences" yaml:"frame_sequences"`
+}
+
+// FurniSpecAsset defines the offset of an asset, or the asset it is sourced from.
+type FurniSpecAsset struct {
+	X      int    `json:"x" yaml:"x"`
+	Y      int    `json:"y" yaml:"y"`
+	Source string `json:"source" yaml:"source"`
+	FlipH  bool   `json:"flip_h" yaml:"flip_h"`
+	FlipV  bool   `json:"flip_v" yaml:"flip_v"`
+}
+
+// inks contains the valid layer ink values.
+var inks = []string{
+	"ADD", "ALPHA", "COPY", "DARKEN", "DIFFERENCE", "ERASE", "HARDLIGHT", "INVERT",
+	"LAYER", "LIGHTEN", "MULTIPLY", "NORMAL", "OVERLAY", "SCREEN", "SUBTRACT",
+}
+
+var hexColorRegex = regexp.MustCompile(`^[0-9A-Fa-f]{6}$`)
+
+// Unmarshals a JSON or YAML furni spec as raw bytes into a FurniSpec.
+// Default values are applied to unspecified fields.
+func (spec *FurniSpec) UnmarshalBytes(data []byte) (err error) {
+	// YAML is a superset of JSON, so both can be decoded with the YAML decoder.
+	var s FurniSpec
+	err = yaml.Unmarshal(data, &s)
+	if err != nil {
+		return
+	}
+	s.applyDefaults()
+	*spec = s
+	return
+}
+
+func (spec *FurniSpec) applyDefaults() {
+	if spec.Logic == "" {
+		spec.Logic = "furniture_basic"
+	}
+	if spec.Visualization == "" {
+		spec.Visualization = "furniture_static"
+		if len(spec.Animations) > 0 {
+			spec.Visualization = "furniture_animated"
+		}
+	}
+	if spec.Dimensions.X == 0 {
+		spec.Dimensions.X = 1
+	}
+	if spec.Dimensions.Y == 0 {
+		spec.Dimensions.Y = 1
+	}
+	if len(spec.Sizes) == 0 {
+		spec.Sizes = []int{64}
+	}
+}
+
+// Validate checks the spec for errors.
+// All errors found are joined into the returned error.
+func (spec *FurniSpec) Validate() error {
+	var errs []error
+	fail := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	if spec.Name == "" {
+		fail("name is required")
+	} else if strings.ContainsAny(spec.Name, "* ") {
+		fail("invalid name: %q", spec.Name)
+	}
+
+	if spec.LayerCount < 1 || spec.LayerCount > 26 {
+		fail("layer count must be between 1 and 26, got %d", spec.LayerCount)
+	}
+	if spec.Dimensions.X < 1 || spec.Dimensions.Y < 1 || spec.Dimensions.Z < 0 {
+		fail("invalid dimensions: %v", spec.Dimensions)
+	}
+
+	for _, size := range spec.Sizes {
+		if size <= 0 {
+			fail("invalid size: %d", size)
+		}
+	}
+
+	if len(spec.Directions) == 0 {
+		fail("at least one direction is required")
+	}
+	for i, dir := range spec.Directions {
+		if dir < 0 || dir > 7 {
+			fail("invalid direction: %d", dir)
+		} else if slices.Contains(spec.Directions[:i], dir) {
+			fail("duplicate direction: %d", dir)
+		}
+	}
+
+	for _, id := range sortedKeys(spec.Layers) {
+		layer := spec.Layers[id]
+		if !spec.validLayer(id) {
+			fail("layer %d is out of range", id)
+		}
+		if layer.Alpha != nil && (*layer.Alpha < 0 || *layer.Alpha > 255) {
+			fail("layer %d: alpha must be between 0 and 255, got %d", id, *layer.Alpha)
+		}
+		if layer.Ink != "" && !slices.Contains(inks, layer.Ink) {
+			fail("layer %d: invalid ink %q", id, layer.Ink)
+		}
+	}
+
+	for _, colorId := range sortedKeys(spec.Colors) {
+		colorLayers := spec.Colors[colorId]
+		if len(colorLayers) == 0 {
+			fail("color %d has no layers", colorId)
+		}
+		for _, layerId := range sortedKeys(colorLayers) {
+			if !spec.validLayer(layerId) {
+				fail("color %d: layer %d is out of range", colorId, layerId)
+			}
+			if !hexColorRegex.MatchString(colorLayers[layerId]) {
+				fail("color %d: layer %d: invalid color %q", colorId, layerId, colorLayers[layerId])
+			}
+		}
+	}
+
+	for _, animId := range sortedKeys(spec.Animations) {
+		anim := spec.Animations[animId]
+		if anim.TransitionTo != nil {
+			if _, ok := spec.Animations[*anim.TransitionTo]; !ok {
+				fail("animation %d: transition to undefined animation %d", animId, *anim.TransitionTo)
+			}
+		}
+		for _, layerId := range sortedKeys(anim.Layers) {
+			animLayer := anim.Layers[layerId]
+			if !spec.validLayer(layerId) {
+				fail("animation %d: layer %d is out of range", animId, layerId)
+			}
+			if animLayer.LoopCount < 0 || animLayer.FrameRepeat < 0 || animLayer.Random < 0 {
+				fail("animation %d: layer %d: loop count, frame repeat and random must not be negative", animId, layerId)
+			}
+			if len(animLayer.FrameSequences) == 0 {
+				fail("animation %d: layer %d has no frame sequences", animId, layerId)
+			}
+			for i, sequence := range animLayer.FrameSequences {
+				if len(sequence) == 0 {
+					fail("animation %d: layer %d: frame sequence %d is empty", animId, layerId, i)
+				}
+				for _, frame := range sequence {
+					if frame < 0 {
+						fail("animation %d: layer %d: invalid frame %d", animId, layerId, frame)
+					}
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (spec *FurniSpec) validLayer(id int) bool {
+	return id >= 0 && id < spec.LayerCount
+}
+
+// parseFurniAssetSpec parses an asset name in the form <name>_<size>_<layer>_<direction>_<frame>.
+func parseFurniAssetSpec(libName, assetName string) (spec FurniAssetSpec, ok bool) {
+	rest, ok := strings.CutPrefix(assetName, libName+"_")
+	if !ok {
+		return
+	}
+	parts := strings.Split(rest, "_")
+	if len(parts) != 4 {
+		return spec, false
+	}
+
+	spec.Name = libName
+	var err error
+	if spec.Size, err = strconv.Atoi(parts[0]); err != nil {
+		return spec, false
+	}
+	switch {
+	case parts[1] == "sd":
+		spec.Layer = -1
+	case len(parts[1]) == 1 && parts[1][0] >= 'a' && parts[1][0] <= 'z':
+		spec.Layer = int(parts[1][0] - 'a')
+	default:
+		return spec, false
+	}
+	if spec.Direction, err = strconv.Atoi(parts[2]); err != nil {
+		return spec, false
+	}
+	if spec.Frame, err = strconv.Atoi(parts[3]); err != nil {
+		return spec, false
+	}
+	return spec, true
+}
+
+// parseFurniIconAsset parses an icon asset name in the form <name>_icon_<layer>,
+// returning the layer index.
+func parseFurniIconAsset(libName, assetName string) (layer int, ok bool) {
+	rest, ok := strings.CutPrefix(assetName, libName+"_icon_")
+	if !ok || len(rest) != 1 || rest[0] < 'a' || rest[0] > 'z' {
+		return 0, false
+	}
+	return int(rest[0] - 'a'), true
+}
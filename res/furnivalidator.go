@@ -0,0 +1,171 @@
+package res
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A FurniIssueKind identifies the kind of problem found when validating a furni library.
+type FurniIssueKind string
+
+const (
+	FurniIssueMissingAsset      FurniIssueKind = "missing-asset"      // An asset referenced by a frame sequence does not exist.
+	FurniIssueBrokenSource      FurniIssueKind = "broken-source"      // The asset's source chain does not lead to an image.
+	FurniIssueCyclicSource      FurniIssueKind = "cyclic-source"      // The asset's source chain is cyclic.
+	FurniIssueMissingDirection  FurniIssueKind = "missing-direction"  // A direction of the visualization has no assets.
+	FurniIssueLayerRange        FurniIssueKind = "layer-range"        // A layer is beyond the visualization's layer count.
+	FurniIssueEmptyColor        FurniIssueKind = "empty-color"        // A color has no color layers.
+	FurniIssueMissingTransition FurniIssueKind = "missing-transition" // An animation transitions to an animation that does not exist.
+)
+
+// A FurniIssue describes a problem with a furni library.
+type FurniIssue struct {
+	Kind      FurniIssueKind
+	Size      int    // The visualization size, for visualization issues.
+	Asset     string // The asset name, for asset and source issues.
+	Layer     int    // The layer ID, for layer range issues.
+	Element   string // The element that references the layer, for layer range issues, e.g. "animation 1".
+	Direction int    // The direction, for direction issues.
+	Color     int    // The color ID, for color issues.
+	Animation int    // The animation ID, for missing asset and transition issues.
+	Target    int    // The target animation ID, for transition issues.
+}
+
+// Error implements error.
+func (issue FurniIssue) Error() string {
+	switch issue.Kind {
+	case FurniIssueMissingAsset:
+		return fmt.Sprintf("size %d: animation %d: asset %q not found", issue.Size, issue.Animation, issue.Asset)
+	case FurniIssueBrokenSource:
+		return fmt.Sprintf("asset %q: source chain does not lead to an image", issue.Asset)
+	case FurniIssueCyclicSource:
+		return fmt.Sprintf("asset %q: cyclic source chain", issue.Asset)
+	case FurniIssueMissingDirection:
+		return fmt.Sprintf("size %d: no assets for direction %d", issue.Size, issue.Direction)
+	case FurniIssueLayerRange:
+		return fmt.Sprintf("size %d: %s: layer %d is out of range", issue.Size, issue.Element, issue.Layer)
+	case FurniIssueEmptyColor:
+		return fmt.Sprintf("size %d: color %d has no color layers", issue.Size, issue.Color)
+	case FurniIssueMissingTransition:
+		return fmt.Sprintf("size %d: animation %d transitions to undefined animation %d",
+			issue.Size, issue.Animation, issue.Target)
+	default:
+		return fmt.Sprintf("%s: %s", issue.Kind, issue.Asset)
+	}
+}
+
+// ValidateFurniLibrary checks the furni library for problems and returns any issues found.
+//
+// Frames referenced by an animation layer are only checked in directions where
+// the layer has at least one asset, as layers are not required to be visible in every direction.
+// If the layer has no assets in any direction, the frames are checked in all directions.
+func ValidateFurniLibrary(lib FurniLibrary) (issues []FurniIssue) {
+	libName := lib.Name()
+	assetNames := lib.Assets()
+	slices.Sort(assetNames)
+
+	hasAssets := map[[3]int]bool{}    // size, layer, direction
+	hasLayer := map[[2]int]bool{}     // size, layer
+	hasDirection := map[[2]int]bool{} // size, direction
+	for _, assetName := range assetNames {
+		if spec, ok := parseFurniAssetSpec(libName, assetName); ok {
+			hasAssets[[3]int{spec.Size, spec.Layer, spec.Direction}] = true
+			hasLayer[[2]int{spec.Size, spec.Layer}] = true
+			hasDirection[[2]int{spec.Size, spec.Direction}] = true
+		}
+
+		asset, err := lib.Asset(assetName)
+		if err != nil || asset.Source == nil {
+			continue
+		}
+		visited := map[*Asset]bool{}
+		for asset.Source != nil && !visited[asset] {
+			visited[asset] = true
+			asset = asset.Source
+		}
+		if asset.Source != nil {
+			issues = append(issues, FurniIssue{Kind: FurniIssueCyclicSource, Asset: assetName})
+		} else if asset.Image == nil {
+			issues = append(issues, FurniIssue{Kind: FurniIssueBrokenSource, Asset: assetName})
+		}
+	}
+
+	visualizations := lib.Visualizations()
+	for _, size := range sortedKeys(visualizations) {
+		vis := visualizations[size]
+		layerRange := func(element string, layerId int) {
+			if layerId < 0 || layerId >= vis.LayerCount {
+				issues = append(issues, FurniIssue{
+					Kind:    FurniIssueLayerRange,
+					Size:    size,
+					Layer:   layerId,
+					Element: element,
+				})
+			}
+		}
+
+		for _, dir := range sortedKeys(vis.Directions) {
+			if !hasDirection[[2]int{size, dir}] {
+				issues = append(issues, FurniIssue{Kind: FurniIssueMissingDirection, Size: size, Direction: dir})
+			}
+		}
+
+		for _, layerId := range sortedKeys(vis.Layers) {
+			layerRange("layers", layerId)
+		}
+
+		for _, colorId := range sortedKeys(vis.Colors) {
+			color := vis.Colors[colorId]
+			if len(color.Layers) == 0 {
+				issues = append(issues, FurniIssue{Kind: FurniIssueEmptyColor, Size: size, Color: colorId})
+			}
+			for _, layerId := range sortedKeys(color.Layers) {
+				layerRange(fmt.Sprintf("color %d", colorId), layerId)
+			}
+		}
+
+		for _, animId := range sortedKeys(vis.Animations) {
+			anim := vis.Animations[animId]
+			if anim.TransitionTo != nil && vis.Animations[anim.TransitionTo.Id] != anim.TransitionTo {
+				issues = append(issues, FurniIssue{
+					Kind:      FurniIssueMissingTransition,
+					Size:      size,
+					Animation: animId,
+					Target:    anim.TransitionTo.Id,
+				})
+			}
+
+			for _, layerId := range sortedKeys(anim.Layers) {
+				layerRange(fmt.Sprintf("animation %d", animId), layerId)
+				if layerId < 0 || layerId >= vis.LayerCount {
+					continue
+				}
+
+				reported := map[string]bool{}
+				for _, dir := range sortedKeys(vis.Directions) {
+					if hasLayer[[2]int{size, layerId}] && !hasAssets[[3]int{size, layerId, dir}] {
+						continue
+					}
+					for _, sequence := range anim.Layers[layerId].FrameSequences {
+						for _, frame := range sequence {
+							spec := FurniAssetSpec{libName, size, layerId, dir, frame}
+							assetName := spec.String()
+							if reported[assetName] || lib.AssetExists(assetName) {
+								continue
+							}
+							reported[assetName] = true
+							issues = append(issues, FurniIssue{
+								Kind:      FurniIssueMissingAsset,
+								Size:      size,
+								Asset:     assetName,
+								Animation: animId,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,116 @@
+package res
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestValidateFurniLibrary(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	cycleA := &Asset{Name: "lamp_64_b_0_0"}
+	cycleB := &Asset{Name: "lamp_64_b_2_0", Source: cycleA}
+	cycleA.Source = cycleB
+	assets := Assets{
+		"lamp_64_a_0_0": {Name: "lamp_64_a_0_0", Image: img},
+		"lamp_64_a_0_1": {Name: "lamp_64_a_0_1", Image: img},
+		"lamp_64_a_2_0": {Name: "lamp_64_a_2_0", Source: &Asset{Name: "lamp_64_a_9_0"}},
+		"lamp_64_b_0_0": cycleA,
+		"lamp_64_b_2_0": cycleB,
+	}
+
+	anim := &Animation{Id: 0, TransitionTo: &Animation{Id: 5}, Layers: map[int]*AnimationLayer{
+		0: {Id: 0, FrameSequences: []FrameSequence{{0, 1, 2}}},
+		2: {Id: 2, FrameSequences: []FrameSequence{{0}}},
+	}}
+	vis := &Visualization{
+		Size:       64,
+		LayerCount: 2,
+		Directions: map[int]struct{}{0: {}, 2: {}, 4: {}},
+		Layers:     map[int]*Layer{1: {Id: 1}, 3: {Id: 3}},
+		Colors:     map[int]*Color{1: {Id: 1, Layers: map[int]*ColorLayer{}}},
+		Animations: map[int]*Animation{0: anim, 1: {Id: 1, TransitionTo: anim}},
+	}
+	lib := &builtFurniLibrary{
+		name:           "lamp",
+		visualizations: Visualizations{64: vis},
+		assets:         assets,
+	}
+
+	expected := []FurniIssue{
+		{Kind: FurniIssueBrokenSource, Asset: "lamp_64_a_2_0"},
+		{Kind: FurniIssueCyclicSource, Asset: "lamp_64_b_0_0"},
+		{Kind: FurniIssueCyclicSource, Asset: "lamp_64_b_2_0"},
+		{Kind: FurniIssueMissingDirection, Size: 64, Direction: 4},
+		{Kind: FurniIssueLayerRange, Size: 64, Layer: 3, Element: "layers"},
+		{Kind: FurniIssueEmptyColor, Size: 64, Color: 1},
+		{Kind: FurniIssueMissingTransition, Size: 64, Animation: 0, Target: 5},
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_a_0_2", Animation: 0},
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_a_2_1", Animation: 0},
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_a_2_2", Animation: 0},
+		{Kind: FurniIssueLayerRange, Size: 64, Layer: 2, Element: "animation 0"},
+	}
+
+	issues := ValidateFurniLibrary(lib)
+	if !reflect.DeepEqual(issues, expected) {
+		t.Fatalf("expected issues %v, got %v", expected, issues)
+	}
+}
+
+func TestValidateFurniLibraryLayerWithoutAssets(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	vis := &Visualization{
+		Size:       64,
+		LayerCount: 2,
+		Directions: map[int]struct{}{0: {}, 2: {}},
+		Animations: map[int]*Animation{0: {Id: 0, Layers: map[int]*AnimationLayer{
+			1: {Id: 1, FrameSequences: []FrameSequence{{0, 1}}},
+		}}},
+	}
+	lib := &builtFurniLibrary{
+		name:           "lamp",
+		visualizations: Visualizations{64: vis},
+		assets: Assets{
+			"lamp_64_a_0_0": {Name: "lamp_64_a_0_0", Image: img},
+			"lamp_64_a_2_0": {Name: "lamp_64_a_2_0", Image: img},
+		},
+	}
+
+	expected := []FurniIssue{
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_b_0_0", Animation: 0},
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_b_0_1", Animation: 0},
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_b_2_0", Animation: 0},
+		{Kind: FurniIssueMissingAsset, Size: 64, Asset: "lamp_64_b_2_1", Animation: 0},
+	}
+
+	issues := ValidateFurniLibrary(lib)
+	if !reflect.DeepEqual(issues, expected) {
+		t.Fatalf("expected issues %v, got %v", expected, issues)
+	}
+}
+
+func TestValidateBuiltFurniLibrary(t *testing.T) {
+	lib, err := BuildFurniLibrary(loadTestFurniSpec(t, testFurniSpec), testFurniFS(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := ValidateFurniLibrary(lib); len(issues) > 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+}
+
+func TestLoadMissingTransition(t *testing.T) {
+	var visData VisualizationData
+	err := visData.UnmarshalBytes([]byte(`<visualizationData type="lamp"><graphics>
+		<visualization size="64" layerCount="1" angle="45">
+			<animations><animation id="0" transitionTo="3"></animation></animations>
+		</visualization>
+	</graphics></visualizationData>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	anim := visData.Visualizations[64].Animations[0]
+	if anim.TransitionTo == nil || anim.TransitionTo.Id != 3 {
+		t.Fatalf("expected transition to animation 3, got %+v", anim.TransitionTo)
+	}
+}
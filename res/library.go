@@ -25,3 +25,17 @@ type FurniLibrary interface {
 	Logic() *Logic
 	Visualizations() map[int]*Visualization
 }
+
+type EffectLibrary interface {
+	AssetLibrary
+	Animation() *EffectAnimation
+}
+
+// RoomContentLibrary is the name of the room content library.
+const RoomContentLibrary = "room"
+
+// A RoomLibrary contains the textures of room floors, walls and landscapes.
+type RoomLibrary interface {
+	AssetLibrary
+	Visualization() *RoomVisualizationData
+}
@@ -0,0 +1,102 @@
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx/raw/nitro"
+	x "xabbo.io/nx/raw/xml"
+)
+
+type nitroEffectLibrary struct {
+	*nitroFigurePartLibrary
+	animation *EffectAnimation
+}
+
+// LoadEffectLibraryNitro loads an avatar effect library from a Nitro archive.
+func LoadEffectLibraryNitro(archive nitro.Archive) (lib EffectLibrary, err error) {
+	metadataFile, err := findNitroMetadata(archive)
+	if err != nil {
+		return
+	}
+
+	var nitroEffect nitro.Effect
+	err = json.Unmarshal(metadataFile.Data, &nitroEffect)
+	if err != nil {
+		return
+	}
+
+	if len(nitroEffect.Animations) == 0 {
+		err = fmt.Errorf("failed to find animation in effect library %q", nitroEffect.Name)
+		return
+	}
+	animationNames := maps.Keys(nitroEffect.Animations)
+	slices.Sort(animationNames)
+
+	var animation EffectAnimation
+	animation.fromNitro(nitroEffect.Animations[animationNames[0]])
+
+	figureLib, err := newNitroFigurePartLibrary(archive, nitroEffect.Name, nitroEffect.Assets, &nitroEffect.Spritesheet)
+	if err != nil {
+		return
+	}
+
+	lib = &nitroEffectLibrary{
+		nitroFigurePartLibrary: figureLib,
+		animation:              &animation,
+	}
+	return
+}
+
+func (lib *nitroEffectLibrary) Animation() *EffectAnimation {
+	return lib.animation
+}
+
+func (anim *EffectAnimation) fromNitro(v nitro.EffectAnimation) {
+	xAnim := x.EffectAnimation{
+		Desc:          v.Desc,
+		ResetOnToggle: v.ResetOnToggle,
+	}
+	for _, nSprite := range v.Sprites {
+		xSprite := x.EffectSprite{
+			Id:         nSprite.Id,
+			Member:     nSprite.Member,
+			Directions: nSprite.Directions != 0,
+			Ink:        nSprite.Ink,
+			StaticY:    nSprite.StaticY,
+		}
+		for _, nOffset := range nSprite.DirectionList {
+			xSprite.Offsets = append(xSprite.Offsets, x.EffectSpriteDirection(nOffset))
+		}
+		xAnim.Sprites = append(xAnim.Sprites, xSprite)
+	}
+	for _, nAdd := range v.Adds {
+		xAnim.Adds = append(xAnim.Adds, x.EffectAdd(nAdd))
+	}
+	for _, nRemove := range v.Removes {
+		xAnim.Removes = append(xAnim.Removes, x.EffectRemove(nRemove))
+	}
+	if v.Shadow != nil {
+		xAnim.Shadow = &x.EffectShadow{Id: v.Shadow.Id}
+	}
+	if v.Direction != nil {
+		xAnim.Direction = &x.EffectDirection{Offset: v.Direction.Offset}
+	}
+	for _, nFrame := range v.Frames {
+		xFrame := x.EffectFrame{Repeats: nFrame.Repeats}
+		for _, nPart := range nFrame.BodyParts {
+			xFrame.BodyParts = append(xFrame.BodyParts, x.EffectFramePart(nPart))
+		}
+		for _, nPart := range nFrame.Fxs {
+			xFrame.Fx = append(xFrame.Fx, x.EffectFramePart(nPart))
+		}
+		xAnim.Frames = append(xAnim.Frames, xFrame)
+	}
+	for _, nAvatar := range v.Avatars {
+		xAnim.Avatars = append(xAnim.Avatars, x.EffectAvatarInfo(nAvatar))
+	}
+	anim.fromXml(&xAnim)
+}
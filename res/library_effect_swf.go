@@ -0,0 +1,51 @@
+package res
+
+import (
+	"fmt"
+	"strings"
+
+	"b7c.io/swfx"
+)
+
+type swfEffectLibrary struct {
+	*swfFigurePartLibrary
+	animation *EffectAnimation
+}
+
+// LoadEffectLibrarySwf loads an avatar effect library from a SWF.
+// Effect libraries contain figure part assets along with an animation defining how the effect is applied.
+func LoadEffectLibrarySwf(swf *swfx.Swf) (lib EffectLibrary, err error) {
+	assetLib, err := LoadFigureLibrarySwf(swf)
+	if err != nil {
+		return
+	}
+	figureLib := assetLib.(*swfFigurePartLibrary)
+
+	var animationTag *swfx.DefineBinaryData
+	for symbol, id := range swf.Symbols {
+		if strings.HasSuffix(symbol, "_animation") {
+			animationTag, _ = swf.Characters[id].(*swfx.DefineBinaryData)
+			break
+		}
+	}
+	if animationTag == nil {
+		err = fmt.Errorf("failed to find animation in effect library %q", figureLib.name)
+		return
+	}
+
+	var animation EffectAnimation
+	err = animation.UnmarshalBytes(animationTag.Data)
+	if err != nil {
+		return
+	}
+
+	lib = &swfEffectLibrary{
+		swfFigurePartLibrary: figureLib,
+		animation:            &animation,
+	}
+	return
+}
+
+func (lib *swfEffectLibrary) Animation() *EffectAnimation {
+	return lib.animation
+}
@@ -0,0 +1,82 @@
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx/raw/nitro"
+)
+
+type nitroFigurePartLibrary struct {
+	name   string
+	assets Assets
+}
+
+// LoadFigureLibraryNitro loads a figure part library from a Nitro archive.
+func LoadFigureLibraryNitro(archive nitro.Archive) (lib AssetLibrary, err error) {
+	metadataFile, err := findNitroMetadata(archive)
+	if err != nil {
+		return
+	}
+
+	var nitroFigure nitro.Figure
+	err = json.Unmarshal(metadataFile.Data, &nitroFigure)
+	if err != nil {
+		return
+	}
+
+	nitroLib, err := newNitroFigurePartLibrary(archive, nitroFigure.Name, nitroFigure.Assets, &nitroFigure.Spritesheet)
+	if err != nil {
+		return
+	}
+
+	lib = nitroLib
+	return
+}
+
+// newNitroFigurePartLibrary creates a figure part library from the assets and spritesheet of a Nitro archive.
+func newNitroFigurePartLibrary(archive nitro.Archive, libName string,
+	assets map[string]nitro.Asset, spritesheet *nitro.Spritesheet) (nitroLib *nitroFigurePartLibrary, err error) {
+
+	nitroLib = &nitroFigurePartLibrary{
+		name:   libName,
+		assets: Assets{},
+	}
+
+	sourceMap := map[string]string{}
+	for name, asset := range assets {
+		nitroLib.assets[name] = new(Asset).fromNitro(name, asset)
+		if asset.Source != "" {
+			sourceMap[name] = asset.Source
+		}
+	}
+	for dstName, srcName := range sourceMap {
+		nitroLib.assets[dstName].Source = nitroLib.assets[srcName]
+	}
+
+	err = extractNitroSprites(archive, nitroLib.name, spritesheet, nitroLib.assets)
+	return
+}
+
+func (lib *nitroFigurePartLibrary) Name() string {
+	return lib.name
+}
+
+func (lib *nitroFigurePartLibrary) Asset(name string) (asset *Asset, err error) {
+	asset, ok := lib.assets[name]
+	if !ok {
+		err = fmt.Errorf("asset %s/%q not found", lib.name, name)
+	}
+	return
+}
+
+func (lib *nitroFigurePartLibrary) Assets() []string {
+	return maps.Keys(lib.assets)
+}
+
+func (lib *nitroFigurePartLibrary) AssetExists(name string) bool {
+	_, exists := lib.assets[name]
+	return exists
+}
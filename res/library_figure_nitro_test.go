@@ -0,0 +1,74 @@
+package res
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"xabbo.io/nx/raw/nitro"
+)
+
+func TestLoadFigureLibraryNitro(t *testing.T) {
+	sheet := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	sheet.Set(2, 0, color.RGBA{255, 0, 0, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sheet); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := `{
+		"name": "hh_human_body",
+		"type": "figure",
+		"assets": {
+			"h_std_bd_1_0_0": {"x": -10, "y": 20},
+			"h_std_bd_1_4_0": {"source": "h_std_bd_1_0_0", "x": 12, "y": 20, "flipH": true}
+		},
+		"spritesheet": {
+			"frames": {
+				"hh_human_body_h_std_bd_1_0_0.png": {"frame": {"x": 2, "y": 0, "w": 2, "h": 2}}
+			},
+			"meta": {"image": "hh_human_body.png", "scale": "1"}
+		}
+	}`
+
+	archive := nitro.Archive{Files: map[string]nitro.File{
+		"hh_human_body.json": {Name: "hh_human_body.json", Data: []byte(metadata)},
+		"hh_human_body.png":  {Name: "hh_human_body.png", Data: buf.Bytes()},
+	}}
+
+	lib, err := LoadFigureLibraryNitro(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.Name() != "hh_human_body" {
+		t.Fatalf("library name is %q (expected hh_human_body)", lib.Name())
+	}
+
+	asset, err := lib.Asset("h_std_bd_1_0_0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Offset != (image.Point{-10, 20}) {
+		t.Fatalf("offset is %v (expected (-10,20))", asset.Offset)
+	}
+	if asset.Image == nil || asset.Image.Bounds().Dx() != 2 {
+		t.Fatalf("unexpected image: %v", asset.Image)
+	}
+	if _, _, _, a := asset.Image.At(0, 0).RGBA(); a == 0 {
+		t.Fatalf("expected sprite to be extracted from the spritesheet")
+	}
+
+	flipped, err := lib.Asset("h_std_bd_1_4_0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !flipped.FlipH || flipped.Source != asset || flipped.SourceImage() != asset.Image {
+		t.Fatalf("expected flipped asset to use its source image")
+	}
+
+	if _, err := lib.Asset("h_std_bd_2_0_0"); err == nil {
+		t.Fatal("expected missing asset to return an error")
+	}
+}
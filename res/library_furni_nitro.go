@@ -28,17 +28,8 @@ func LoadFurniLibraryNitro(archive nitro.Archive) (furniLibrary FurniLibrary, er
 		assets: map[string]*Asset{},
 	}
 
-	// find metadata
-
-	var metadataFile nitro.File
-	for name := range archive.Files {
-		if strings.HasSuffix(name, ".json") {
-			metadataFile = archive.Files[name]
-		}
-	}
-
-	if metadataFile.Data == nil {
-		err = fmt.Errorf("failed to find metadata in Nitro archive")
+	metadataFile, err := findNitroMetadata(archive)
+	if err != nil {
 		return
 	}
 
@@ -81,18 +72,43 @@ func LoadFurniLibraryNitro(archive nitro.Archive) (furniLibrary FurniLibrary, er
 		nitroLib.assets[dstName].Source = nitroLib.assets[srcName]
 	}
 
-	// extract images from spritesheet
-	bytesSpritesheet := archive.Files[nitroFurni.Spritesheet.Meta.Image].Data
+	err = extractNitroSprites(archive, nitroLib.name, &nitroFurni.Spritesheet, nitroLib.assets)
+	if err != nil {
+		return
+	}
+
+	furniLibrary = nitroLib
+	return
+}
+
+// findNitroMetadata finds the JSON metadata file in a Nitro archive.
+func findNitroMetadata(archive nitro.Archive) (metadataFile nitro.File, err error) {
+	for name := range archive.Files {
+		if strings.HasSuffix(name, ".json") {
+			metadataFile = archive.Files[name]
+		}
+	}
+
+	if metadataFile.Data == nil {
+		err = fmt.Errorf("failed to find metadata in Nitro archive")
+	}
+	return
+}
+
+// extractNitroSprites extracts the images of the specified assets from a Nitro archive's spritesheet.
+// Sprites are named by the library name and asset name, with an optional .png extension.
+func extractNitroSprites(archive nitro.Archive, libName string, spritesheet *nitro.Spritesheet, assets map[string]*Asset) (err error) {
+	bytesSpritesheet := archive.Files[spritesheet.Meta.Image].Data
 	imgSprites, err := png.Decode(bytes.NewReader(bytesSpritesheet))
 	if err != nil {
 		return
 	}
 
-	for name, asset := range nitroLib.assets {
-		name = nitroLib.name + "_" + name
-		spriteInfo, ok := nitroFurni.Spritesheet.Frames[name]
+	for name, asset := range assets {
+		name = libName + "_" + name
+		spriteInfo, ok := spritesheet.Frames[name]
 		if !ok {
-			spriteInfo, ok = nitroFurni.Spritesheet.Frames[name+".png"]
+			spriteInfo, ok = spritesheet.Frames[name+".png"]
 			if !ok {
 				continue
 			}
@@ -104,7 +120,6 @@ func LoadFurniLibraryNitro(archive nitro.Archive) (furniLibrary FurniLibrary, er
 		asset.Image = spriteImg
 	}
 
-	furniLibrary = nitroLib
 	return
 }
 
@@ -0,0 +1,85 @@
+package res
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+
+	"xabbo.io/nx/raw/nitro"
+)
+
+// ConvertFurniLibraryNitro converts a furni library into a Nitro archive.
+// The asset images are packed into a spritesheet, and the assets, logic, visualizations
+// and spritesheet frames are written to the furni metadata JSON file.
+func ConvertFurniLibraryNitro(lib FurniLibrary) (archive nitro.Archive, err error) {
+	name := lib.Name()
+
+	nitroFurni := nitro.Furni{
+		Name:   name,
+		Assets: map[string]nitro.Asset{},
+	}
+	if index := lib.Index(); index != nil {
+		nitroFurni.LogicType = index.Logic
+		nitroFurni.VisualizationType = index.Visualization
+	}
+	if logic := lib.Logic(); logic != nil {
+		nitroFurni.Logic = logic.toNitro()
+	}
+	nitroFurni.Visualizations, err = Visualizations(lib.Visualizations()).toNitro()
+	if err != nil {
+		return
+	}
+
+	images := map[string]image.Image{}
+	for _, assetName := range lib.Assets() {
+		var asset *Asset
+		asset, err = lib.Asset(assetName)
+		if err != nil {
+			return
+		}
+		nitroFurni.Assets[assetName] = asset.toNitro()
+		if asset.Source == nil && asset.Image != nil {
+			images[assetName] = asset.Image
+		}
+	}
+
+	sheet, regions := packSpritesheet(images)
+	var imageData bytes.Buffer
+	err = png.Encode(&imageData, sheet)
+	if err != nil {
+		return
+	}
+
+	imageName := name + ".png"
+	nitroFurni.Spritesheet = nitro.Spritesheet{
+		Frames: make(map[string]nitro.SpriteFrame, len(regions)),
+		Meta: nitro.Meta{
+			Image:  imageName,
+			Format: "RGBA8888",
+			Size:   nitro.Size{W: sheet.Rect.Dx(), H: sheet.Rect.Dy()},
+			Scale:  1,
+		},
+	}
+	for assetName, r := range regions {
+		nitroFurni.Spritesheet.Frames[name+"_"+assetName] = nitro.SpriteFrame{
+			Frame:            nitro.Size{X: r.Min.X, Y: r.Min.Y, W: r.Dx(), H: r.Dy()},
+			SpriteSourceSize: nitro.Size{W: r.Dx(), H: r.Dy()},
+			SourceSize:       nitro.Size{W: r.Dx(), H: r.Dy()},
+			Pivot:            nitro.Pivot{X: 0.5, Y: 0.5},
+		}
+	}
+
+	metadata, err := json.Marshal(nitroFurni)
+	if err != nil {
+		err = fmt.Errorf("failed to encode metadata: %w", err)
+		return
+	}
+
+	archive = nitro.Archive{Files: map[string]nitro.File{
+		name + ".json": {Name: name + ".json", Data: metadata},
+		imageName:      {Name: imageName, Data: imageData.Bytes()},
+	}}
+	return
+}
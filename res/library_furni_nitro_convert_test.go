@@ -0,0 +1,160 @@
+package res
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestConvertFurniLibraryNitro(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.RGBA{0, 0, 255, 255})
+	other := image.NewRGBA(image.Rect(0, 0, 2, 4))
+	other.Set(0, 3, color.RGBA{0, 255, 0, 255})
+
+	base := &Asset{Name: "chair_64_a_0_0", Offset: image.Pt(-30, 20), Image: img}
+	assets := map[string]*Asset{
+		"chair_64_a_0_0":  base,
+		"chair_64_a_2_0":  {Name: "chair_64_a_2_0", Source: base, FlipH: true, Offset: image.Pt(33, 20)},
+		"chair_64_b_0_0":  {Name: "chair_64_b_0_0", Offset: image.Pt(-1, -2), Image: other},
+		"chair_64_sd_0_0": {Name: "chair_64_sd_0_0", Image: img},
+	}
+
+	anim := &Animation{Id: 1, Layers: map[int]*AnimationLayer{
+		0: {Id: 0, FrameRepeat: 2, FrameSequences: []FrameSequence{{0, 1, 0}}},
+	}}
+	alpha := 128
+	vis := &Visualization{
+		Size:       64,
+		LayerCount: 2,
+		Angle:      45,
+		Directions: map[int]struct{}{0: {}, 2: {}},
+		Layers:     map[int]*Layer{1: {Id: 1, Z: 2, Alpha: &alpha, Ink: "ADD"}},
+		Colors: map[int]*Color{1: {Id: 1, Layers: map[int]*ColorLayer{
+			0: {Id: 0, Color: "ff00ff"},
+		}}},
+		Animations: map[int]*Animation{0: {Id: 0, TransitionTo: anim, Layers: map[int]*AnimationLayer{}}, 1: anim},
+	}
+
+	src := &nitroFurniLibrary{
+		name:           "chair",
+		index:          &Index{Type: "chair", Visualization: "furniture_animated", Logic: "furniture_basic"},
+		logic:          &Logic{Model: &Model{Dimensions: Dimensions{1, 1, 1.5}, Directions: []int{90, 180}}, ParticleSystems: map[int]*ParticleSystem{}},
+		visualizations: Visualizations{64: vis},
+		assets:         assets,
+	}
+
+	archive, err := ConvertFurniLibraryNitro(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Files) != 2 {
+		t.Fatalf("expected 2 files in archive, got %d", len(archive.Files))
+	}
+
+	lib, err := LoadFurniLibraryNitro(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lib.Name() != "chair" || *lib.Index() != *src.index {
+		t.Fatalf("unexpected library name or index: %q %+v", lib.Name(), lib.Index())
+	}
+	if !reflect.DeepEqual(lib.Logic().Model, src.logic.Model) {
+		t.Fatalf("unexpected model: %+v", lib.Logic().Model)
+	}
+	if !reflect.DeepEqual(lib.Visualizations(), map[int]*Visualization(src.visualizations)) {
+		t.Fatalf("visualizations do not match")
+	}
+
+	for name, expected := range assets {
+		asset, err := lib.Asset(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if asset.Offset != expected.Offset || asset.FlipH != expected.FlipH {
+			t.Fatalf("%s: unexpected asset %+v", name, asset)
+		}
+		if (asset.Source == nil) != (expected.Source == nil) ||
+			(asset.Source != nil && asset.Source.Name != expected.Source.Name) {
+			t.Fatalf("%s: unexpected source %+v", name, asset.Source)
+		}
+		if expected.Image == nil {
+			continue
+		}
+		if asset.Image == nil || asset.Image.Bounds().Size() != expected.Image.Bounds().Size() {
+			t.Fatalf("%s: unexpected image bounds", name)
+		}
+		b := expected.Image.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if !reflect.DeepEqual(color.RGBAModel.Convert(asset.Image.At(x, y)), color.RGBAModel.Convert(expected.Image.At(x, y))) {
+					t.Fatalf("%s: pixel (%d,%d) does not match", name, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestConvertFurniLibraryNitroInvalidColor(t *testing.T) {
+	src := &nitroFurniLibrary{
+		name: "chair",
+		visualizations: Visualizations{64: {
+			Size: 64,
+			Colors: map[int]*Color{1: {Id: 1, Layers: map[int]*ColorLayer{
+				0: {Id: 0, Color: "#zzzzzz"},
+			}}},
+		}},
+		assets: map[string]*Asset{},
+	}
+	if _, err := ConvertFurniLibraryNitro(src); err == nil {
+		t.Fatal("expected error for invalid color")
+	}
+}
+
+func TestConvertFurniLibraryNitroZeroValues(t *testing.T) {
+	alpha := 0
+	vis := &Visualization{
+		Size:       64,
+		LayerCount: 2,
+		Directions: map[int]struct{}{0: {}},
+		Layers: map[int]*Layer{
+			0: {Id: 0, Z: 0, Alpha: &alpha},
+			1: {Id: 1, Z: 1},
+		},
+		Colors: map[int]*Color{1: {Id: 1, Layers: map[int]*ColorLayer{
+			0: {Id: 0, Color: "0"},
+		}}},
+		Animations: map[int]*Animation{},
+	}
+	src := &nitroFurniLibrary{
+		name:           "chair",
+		index:          &Index{Type: "chair"},
+		logic:          &Logic{Model: &Model{Dimensions: Dimensions{1, 1, 0}}, ParticleSystems: map[int]*ParticleSystem{}},
+		visualizations: Visualizations{64: vis},
+		assets:         map[string]*Asset{},
+	}
+
+	archive, err := ConvertFurniLibraryNitro(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, err := LoadFurniLibraryNitro(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lib.Logic().Model.Dimensions != (Dimensions{1, 1, 0}) {
+		t.Fatalf("unexpected dimensions: %+v", lib.Logic().Model.Dimensions)
+	}
+	if !reflect.DeepEqual(lib.Visualizations(), map[int]*Visualization(src.visualizations)) {
+		t.Fatalf("visualizations do not match")
+	}
+	if layer := lib.Visualizations()[64].Layers[0]; layer.Alpha == nil || *layer.Alpha != 0 {
+		t.Fatalf("expected zero alpha to be kept, got %v", layer.Alpha)
+	}
+	if layer := lib.Visualizations()[64].Layers[1]; layer.Alpha != nil {
+		t.Fatalf("expected no alpha, got %d", *layer.Alpha)
+	}
+}
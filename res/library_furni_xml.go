@@ -0,0 +1,102 @@
+package res
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"slices"
+	"strconv"
+
+	x "xabbo.io/nx/raw/xml"
+)
+
+// ExportFurniLibraryXml exports a furni library as the XML documents and PNG images contained in a SWF library.
+// Files are named after their SWF symbols, as extracted by `nx extract`, e.g.
+// chair_index.xml, chair_chair_visualization.xml and chair_chair_64_a_0_0.png.
+func ExportFurniLibraryXml(lib FurniLibrary) (files map[string][]byte, err error) {
+	name := lib.Name()
+	files = map[string][]byte{}
+
+	assetNames := lib.Assets()
+	slices.Sort(assetNames)
+
+	var xAssets x.Assets
+	manifest := x.Manifest{Library: x.Library{Name: name, Version: "0.1"}}
+	if m := lib.Manifest(); m != nil && m.Version != "" {
+		manifest.Library.Version = m.Version
+	}
+
+	for _, assetName := range assetNames {
+		var asset *Asset
+		asset, err = lib.Asset(assetName)
+		if err != nil {
+			return
+		}
+		xAssets.Assets = append(xAssets.Assets, asset.toXml())
+
+		if asset.Source != nil || asset.Image == nil {
+			continue
+		}
+		manifest.Library.Assets = append(manifest.Library.Assets, x.Asset{
+			Name:     assetName,
+			MimeType: "image/png",
+			Params: []x.Param{{
+				Key:   "offset",
+				Value: strconv.Itoa(asset.Offset.X) + "," + strconv.Itoa(asset.Offset.Y),
+			}},
+		})
+
+		var buf bytes.Buffer
+		err = png.Encode(&buf, asset.Image)
+		if err != nil {
+			return
+		}
+		files[name+"_"+assetName+".png"] = buf.Bytes()
+	}
+
+	var index x.Index
+	if idx := lib.Index(); idx != nil {
+		index = x.Index{Type: idx.Type, Visualization: idx.Visualization, Logic: idx.Logic}
+	}
+	visData := VisualizationData{Type: name, Visualizations: lib.Visualizations()}
+	var logic *x.Logic
+	if l := lib.Logic(); l != nil {
+		logic = l.toXml()
+	} else {
+		logic = &x.Logic{Type: name}
+	}
+
+	docs := []struct {
+		fileName string
+		root     string
+		v        any
+	}{
+		{name + "_index.xml", "object", index},
+		{name + "_manifest.xml", "manifest", manifest},
+		{name + "_" + name + "_logic.xml", "objectData", logic},
+		{name + "_" + name + "_visualization.xml", "visualizationData", visData.toXml()},
+		{name + "_" + name + "_assets.xml", "assets", xAssets},
+	}
+	for _, doc := range docs {
+		files[doc.fileName], err = encodeXml(doc.root, doc.v)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func encodeXml(root string, v any) (data []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	err = enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: root}})
+	if err != nil {
+		return
+	}
+	buf.WriteByte('\n')
+	data = buf.Bytes()
+	return
+}
@@ -0,0 +1,141 @@
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"strings"
+
+	"b7c.io/swfx"
+	"golang.org/x/exp/maps"
+
+	"xabbo.io/nx/raw/nitro"
+)
+
+type roomLibrary struct {
+	visualization *RoomVisualizationData
+	assets        Assets
+}
+
+// LoadRoomLibrarySwf loads the room content library from a SWF.
+// Only the bitmaps referenced by plane textures are loaded as assets.
+func LoadRoomLibrarySwf(swf *swfx.Swf) (lib RoomLibrary, err error) {
+	var visTag *swfx.DefineBinaryData
+	prefix := ""
+	for symbol, id := range swf.Symbols {
+		if strings.HasSuffix(symbol, "_room_visualization") {
+			visTag, _ = swf.Characters[id].(*swfx.DefineBinaryData)
+			prefix = strings.TrimSuffix(symbol, "room_visualization")
+			break
+		}
+	}
+	if visTag == nil {
+		err = fmt.Errorf("failed to find room visualization in library")
+		return
+	}
+
+	var visualization RoomVisualizationData
+	err = visualization.UnmarshalBytes(visTag.Data)
+	if err != nil {
+		return
+	}
+
+	roomLib := &roomLibrary{
+		visualization: &visualization,
+		assets:        Assets{},
+	}
+	for _, assetName := range visualization.bitmaps() {
+		imgTag := getImageTag(swf, prefix+assetName)
+		if imgTag == nil {
+			continue
+		}
+		var img image.Image
+		img, err = imgTag.Decode()
+		if err != nil {
+			return
+		}
+		roomLib.assets[assetName] = &Asset{Name: assetName, Image: img}
+	}
+
+	lib = roomLib
+	return
+}
+
+// LoadRoomLibraryNitro loads the room content library from a Nitro archive.
+func LoadRoomLibraryNitro(archive nitro.Archive) (lib RoomLibrary, err error) {
+	metadataFile, err := findNitroMetadata(archive)
+	if err != nil {
+		return
+	}
+
+	var nitroRoom nitro.Room
+	err = json.Unmarshal(metadataFile.Data, &nitroRoom)
+	if err != nil {
+		return
+	}
+
+	roomLib := &roomLibrary{
+		visualization: new(RoomVisualizationData).fromNitro(&nitroRoom.RoomVisualization),
+		assets:        Assets{},
+	}
+
+	sourceMap := map[string]string{}
+	for name, asset := range nitroRoom.Assets {
+		roomLib.assets[name] = new(Asset).fromNitro(name, asset)
+		if asset.Source != "" {
+			sourceMap[name] = asset.Source
+		}
+	}
+	for dstName, srcName := range sourceMap {
+		roomLib.assets[dstName].Source = roomLib.assets[srcName]
+	}
+	// Bitmaps may be referenced without an asset definition.
+	for _, assetName := range roomLib.visualization.bitmaps() {
+		if _, ok := roomLib.assets[assetName]; !ok {
+			roomLib.assets[assetName] = &Asset{Name: assetName}
+		}
+	}
+
+	err = extractNitroSprites(archive, nitroRoom.Name, &nitroRoom.Spritesheet, roomLib.assets)
+	if err != nil {
+		return
+	}
+
+	lib = roomLib
+	return
+}
+
+// bitmaps gets the asset names of all texture bitmaps.
+func (data *RoomVisualizationData) bitmaps() (assetNames []string) {
+	for _, planeData := range []*PlaneVisualizationData{data.Floor, data.Wall, data.Landscape} {
+		for _, texture := range planeData.Textures {
+			assetNames = append(assetNames, texture.Bitmaps...)
+		}
+	}
+	return
+}
+
+func (lib *roomLibrary) Name() string {
+	return RoomContentLibrary
+}
+
+func (lib *roomLibrary) Visualization() *RoomVisualizationData {
+	return lib.visualization
+}
+
+func (lib *roomLibrary) Asset(name string) (asset *Asset, err error) {
+	asset, ok := lib.assets[name]
+	if !ok {
+		err = fmt.Errorf("asset %q not found in room library", name)
+	}
+	return
+}
+
+func (lib *roomLibrary) Assets() []string {
+	return maps.Keys(lib.assets)
+}
+
+func (lib *roomLibrary) AssetExists(name string) bool {
+	_, exists := lib.assets[name]
+	return exists
+}
@@ -0,0 +1,263 @@
+package res
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"xabbo.io/nx/raw/nitro"
+	x "xabbo.io/nx/raw/xml"
+)
+
+// A RoomPlaneType identifies the type of a room plane.
+type RoomPlaneType string
+
+const (
+	RoomPlaneFloor     RoomPlaneType = "floor"
+	RoomPlaneWall      RoomPlaneType = "wall"
+	RoomPlaneLandscape RoomPlaneType = "landscape"
+)
+
+// RoomVisualizationData defines the floor, wall and landscape planes of the room content library.
+type RoomVisualizationData struct {
+	Floor     *PlaneVisualizationData
+	Wall      *PlaneVisualizationData
+	Landscape *PlaneVisualizationData
+}
+
+// PlaneVisualizationData defines the planes of a plane type, along with the materials and textures they use.
+type PlaneVisualizationData struct {
+	Planes    map[string]*Plane         // Planes mapped by ID. The plane ID is the param of a floor, wallpaper or landscape furni.
+	Materials map[string]*PlaneMaterial // Materials mapped by ID.
+	Textures  map[string]*PlaneTexture  // Textures mapped by ID.
+}
+
+// A Plane defines the visualizations of a floor, wall or landscape pattern.
+type Plane struct {
+	Id             string
+	Visualizations map[int]*PlaneVisualization // Visualizations mapped by size.
+}
+
+type PlaneVisualization struct {
+	Size   int
+	Layers []PlaneLayer
+}
+
+// A PlaneLayer defines the material of a plane visualization layer.
+type PlaneLayer struct {
+	MaterialId string
+	Color      int // The color multiplied with the texture as 0xRRGGBB, or 0 if the layer is not colored.
+	Offset     int
+}
+
+// A PlaneMaterial defines a matrix of texture cells.
+type PlaneMaterial struct {
+	Id       string
+	Matrices []PlaneMaterialMatrix
+}
+
+type PlaneMaterialMatrix struct {
+	RepeatMode string
+	Align      string
+	Columns    []PlaneMaterialColumn
+}
+
+type PlaneMaterialColumn struct {
+	RepeatMode string
+	Width      int
+	TextureIds []string // The texture IDs of each cell in the column.
+}
+
+// A PlaneTexture defines the bitmap assets of a texture.
+type PlaneTexture struct {
+	Id      string
+	Bitmaps []string // The asset names of the texture's bitmaps.
+}
+
+// Unmarshals a room visualization XML document as raw bytes into a RoomVisualizationData.
+func (data *RoomVisualizationData) UnmarshalBytes(b []byte) (err error) {
+	var xData x.RoomVisualizationData
+	err = decodeXml(b, &xData)
+	if err != nil {
+		return
+	}
+	*data = RoomVisualizationData{
+		Floor:     new(PlaneVisualizationData).fromXml(&xData.FloorData, xData.FloorData.Floors),
+		Wall:      new(PlaneVisualizationData).fromXml(&xData.WallData, xData.WallData.Walls),
+		Landscape: new(PlaneVisualizationData).fromXml(&xData.LandscapeData, xData.LandscapeData.Landscapes),
+	}
+	return
+}
+
+func (data *RoomVisualizationData) fromNitro(v *nitro.RoomVisualization) *RoomVisualizationData {
+	*data = RoomVisualizationData{
+		Floor:     new(PlaneVisualizationData).fromNitro(&v.FloorData),
+		Wall:      new(PlaneVisualizationData).fromNitro(&v.WallData),
+		Landscape: new(PlaneVisualizationData).fromNitro(&v.LandscapeData),
+	}
+	return data
+}
+
+// PlaneData gets the plane visualization data of the specified plane type.
+func (data *RoomVisualizationData) PlaneData(planeType RoomPlaneType) *PlaneVisualizationData {
+	switch planeType {
+	case RoomPlaneFloor:
+		return data.Floor
+	case RoomPlaneWall:
+		return data.Wall
+	case RoomPlaneLandscape:
+		return data.Landscape
+	default:
+		return nil
+	}
+}
+
+// PlaneTexture resolves the texture of the plane with the specified type, ID and visualization size.
+// The texture is resolved from the first layer of the plane's visualization,
+// through the first cell of the layer's material, to the first bitmap of the cell's texture.
+// Returns the asset name of the bitmap and the layer used to select it.
+func (data *RoomVisualizationData) PlaneTexture(planeType RoomPlaneType, id string, size int) (assetName string, layer PlaneLayer, err error) {
+	planeData := data.PlaneData(planeType)
+	if planeData == nil {
+		err = fmt.Errorf("invalid plane type: %q", planeType)
+		return
+	}
+	plane, ok := planeData.Planes[id]
+	if !ok {
+		err = fmt.Errorf("%s %q not found", planeType, id)
+		return
+	}
+	vis, ok := plane.Visualizations[size]
+	if !ok {
+		err = fmt.Errorf("%s %q has no visualization for size %d", planeType, id, size)
+		return
+	}
+
+	for _, layer = range vis.Layers {
+		material, ok := planeData.Materials[layer.MaterialId]
+		if !ok {
+			continue
+		}
+		for _, matrix := range material.Matrices {
+			for _, column := range matrix.Columns {
+				for _, textureId := range column.TextureIds {
+					texture, ok := planeData.Textures[textureId]
+					if ok && len(texture.Bitmaps) > 0 {
+						assetName = texture.Bitmaps[0]
+						return
+					}
+				}
+			}
+		}
+	}
+
+	err = fmt.Errorf("no texture found for %s %q at size %d", planeType, id, size)
+	return
+}
+
+func (data *PlaneVisualizationData) fromXml(v *x.PlaneVisualizationData, planes []x.Plane) *PlaneVisualizationData {
+	*data = PlaneVisualizationData{
+		Planes:    make(map[string]*Plane, len(planes)),
+		Materials: make(map[string]*PlaneMaterial, len(v.Materials)),
+		Textures:  make(map[string]*PlaneTexture, len(v.Textures)),
+	}
+
+	for _, xPlane := range planes {
+		plane := &Plane{Id: xPlane.Id, Visualizations: make(map[int]*PlaneVisualization, len(xPlane.Visualizations))}
+		for _, xVis := range xPlane.Visualizations {
+			vis := &PlaneVisualization{Size: xVis.Size}
+			for _, xLayer := range xVis.Layers {
+				vis.Layers = append(vis.Layers, PlaneLayer{
+					MaterialId: xLayer.MaterialId,
+					Color:      parsePlaneColor(xLayer.Color),
+					Offset:     xLayer.Offset,
+				})
+			}
+			plane.Visualizations[vis.Size] = vis
+		}
+		data.Planes[plane.Id] = plane
+	}
+
+	for _, xMaterial := range v.Materials {
+		material := &PlaneMaterial{Id: xMaterial.Id}
+		for _, xMatrix := range xMaterial.Matrices {
+			matrix := PlaneMaterialMatrix{RepeatMode: xMatrix.RepeatMode, Align: xMatrix.Align}
+			for _, xColumn := range xMatrix.Columns {
+				column := PlaneMaterialColumn{RepeatMode: xColumn.RepeatMode, Width: xColumn.Width}
+				for _, xCell := range xColumn.Cells {
+					column.TextureIds = append(column.TextureIds, xCell.TextureId)
+				}
+				matrix.Columns = append(matrix.Columns, column)
+			}
+			material.Matrices = append(material.Matrices, matrix)
+		}
+		data.Materials[material.Id] = material
+	}
+
+	for _, xTexture := range v.Textures {
+		texture := &PlaneTexture{Id: xTexture.Id}
+		for _, bitmap := range xTexture.Bitmaps {
+			texture.Bitmaps = append(texture.Bitmaps, bitmap.AssetName)
+		}
+		data.Textures[texture.Id] = texture
+	}
+
+	return data
+}
+
+func (data *PlaneVisualizationData) fromNitro(v *nitro.PlaneVisualizationData) *PlaneVisualizationData {
+	*data = PlaneVisualizationData{
+		Planes:    make(map[string]*Plane, len(v.Planes)),
+		Materials: make(map[string]*PlaneMaterial, len(v.Materials)),
+		Textures:  make(map[string]*PlaneTexture, len(v.Textures)),
+	}
+
+	for _, nPlane := range v.Planes {
+		plane := &Plane{Id: nPlane.Id, Visualizations: make(map[int]*PlaneVisualization, len(nPlane.Visualizations))}
+		for _, nVis := range nPlane.Visualizations {
+			vis := &PlaneVisualization{Size: nVis.Size}
+			for _, nLayer := range nVis.Layers {
+				vis.Layers = append(vis.Layers, PlaneLayer(nLayer))
+			}
+			plane.Visualizations[vis.Size] = vis
+		}
+		data.Planes[plane.Id] = plane
+	}
+
+	for _, nMaterial := range v.Materials {
+		material := &PlaneMaterial{Id: nMaterial.Id}
+		for _, nMatrix := range nMaterial.Matrices {
+			matrix := PlaneMaterialMatrix{RepeatMode: nMatrix.RepeatMode, Align: nMatrix.Align}
+			for _, nColumn := range nMatrix.Columns {
+				column := PlaneMaterialColumn{RepeatMode: nColumn.RepeatMode, Width: nColumn.Width}
+				for _, cell := range nColumn.Cells {
+					column.TextureIds = append(column.TextureIds, cell.TextureId)
+				}
+				matrix.Columns = append(matrix.Columns, column)
+			}
+			material.Matrices = append(material.Matrices, matrix)
+		}
+		data.Materials[material.Id] = material
+	}
+
+	for _, nTexture := range v.Textures {
+		texture := &PlaneTexture{Id: nTexture.Id}
+		for _, bitmap := range nTexture.Bitmaps {
+			texture.Bitmaps = append(texture.Bitmaps, bitmap.AssetName)
+		}
+		data.Textures[texture.Id] = texture
+	}
+
+	return data
+}
+
+// parsePlaneColor parses a hex color in the form RRGGBB, 0xRRGGBB or #RRGGBB.
+// Returns 0 if the color is empty or invalid.
+func parsePlaneColor(s string) int {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(s), "0x"), "#")
+	color, err := strconv.ParseInt(s, 16, 32)
+	if err != nil {
+		return 0
+	}
+	return int(color)
+}
@@ -0,0 +1,118 @@
+package res
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"xabbo.io/nx/raw/nitro"
+)
+
+const testRoomVisualizationXml = `<?xml version="1.0" encoding="UTF-8"?>
+<visualizationData type="room">
+  <floorData>
+    <floors>
+      <floor id="101">
+        <visualization size="64"><visualizationLayer materialId="floor_101_64" color="0xFFCC99"/></visualization>
+        <visualization size="32"><visualizationLayer materialId="missing"/></visualization>
+      </floor>
+    </floors>
+    <textures>
+      <texture id="floor_tile_64"><bitmap assetName="floor_texture_64_1"/></texture>
+    </textures>
+    <materials>
+      <material id="floor_101_64">
+        <materialCellMatrix repeatMode="default" align="top">
+          <materialCellColumn repeatMode="default" width="64"><materialCell textureId="floor_tile_64"/></materialCellColumn>
+        </materialCellMatrix>
+      </material>
+    </materials>
+  </floorData>
+  <wallData>
+    <walls>
+      <wall id="201"><visualization size="64"><visualizationLayer materialId="wall_201_64"/></visualization></wall>
+    </walls>
+    <textures>
+      <texture id="wall_201"><bitmap assetName="wall_texture_64_201"/></texture>
+    </textures>
+    <materials>
+      <material id="wall_201_64">
+        <materialCellMatrix>
+          <materialCellColumn width="64"><materialCell textureId="wall_201"/></materialCellColumn>
+        </materialCellMatrix>
+      </material>
+    </materials>
+  </wallData>
+</visualizationData>`
+
+const testRoomVisualizationJson = `{
+  "floorData": {
+    "planes": [{"id": "101", "visualizations": [
+      {"size": 64, "layers": [{"materialId": "floor_101_64", "color": 16764057}]},
+      {"size": 32, "layers": [{"materialId": "missing"}]}
+    ]}],
+    "materials": [{"id": "floor_101_64", "matrices": [{"repeatMode": "default", "align": "top",
+      "columns": [{"repeatMode": "default", "width": 64, "cells": [{"textureId": "floor_tile_64"}]}]}]}],
+    "textures": [{"id": "floor_tile_64", "bitmaps": [{"assetName": "floor_texture_64_1"}]}]
+  },
+  "wallData": {
+    "planes": [{"id": "201", "visualizations": [{"size": 64, "layers": [{"materialId": "wall_201_64"}]}]}],
+    "materials": [{"id": "wall_201_64", "matrices": [{"columns": [{"width": 64, "cells": [{"textureId": "wall_201"}]}]}]}],
+    "textures": [{"id": "wall_201", "bitmaps": [{"assetName": "wall_texture_64_201"}]}]
+  }
+}`
+
+func TestRoomVisualizationPlaneTexture(t *testing.T) {
+	var data RoomVisualizationData
+	if err := data.UnmarshalBytes([]byte(testRoomVisualizationXml)); err != nil {
+		t.Fatal(err)
+	}
+
+	assetName, layer, err := data.PlaneTexture(RoomPlaneFloor, "101", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assetName != "floor_texture_64_1" || layer.Color != 0xffcc99 {
+		t.Fatalf("unexpected floor texture: %q %+v", assetName, layer)
+	}
+
+	assetName, _, err = data.PlaneTexture(RoomPlaneWall, "201", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assetName != "wall_texture_64_201" {
+		t.Fatalf("unexpected wall texture: %q", assetName)
+	}
+
+	for _, test := range []struct {
+		planeType RoomPlaneType
+		id        string
+		size      int
+	}{
+		{RoomPlaneFloor, "999", 64},
+		{RoomPlaneFloor, "101", 32},
+		{RoomPlaneFloor, "101", 1},
+		{RoomPlaneLandscape, "1.1", 64},
+	} {
+		if _, _, err := data.PlaneTexture(test.planeType, test.id, test.size); err == nil {
+			t.Fatalf("expected error for %s %q size %d", test.planeType, test.id, test.size)
+		}
+	}
+}
+
+func TestRoomVisualizationNitro(t *testing.T) {
+	var xmlData RoomVisualizationData
+	if err := xmlData.UnmarshalBytes([]byte(testRoomVisualizationXml)); err != nil {
+		t.Fatal(err)
+	}
+
+	var v nitro.RoomVisualization
+	if err := json.Unmarshal([]byte(testRoomVisualizationJson), &v); err != nil {
+		t.Fatal(err)
+	}
+	nitroData := new(RoomVisualizationData).fromNitro(&v)
+
+	if !reflect.DeepEqual(nitroData, &xmlData) {
+		t.Fatalf("Nitro room visualization does not match XML")
+	}
+}
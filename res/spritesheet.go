@@ -0,0 +1,97 @@
+package res
+
+import (
+	"crypto/sha1"
+	"image"
+	"image/draw"
+	"math"
+	"slices"
+
+	"golang.org/x/exp/maps"
+)
+
+// spritePadding is the number of transparent pixels between packed sprites.
+const spritePadding = 1
+
+// packSpritesheet packs images into a single spritesheet image.
+// Identical images are packed once and share the same region.
+// It returns the spritesheet and the region of each image within it.
+func packSpritesheet(images map[string]image.Image) (sheet *image.RGBA, regions map[string]image.Rectangle) {
+	type sprite struct {
+		names []string
+		img   image.Image
+		size  image.Point
+		pos   image.Point
+	}
+
+	names := maps.Keys(images)
+	slices.Sort(names)
+
+	var sprites []*sprite
+	spriteByHash := map[[sha1.Size]byte]*sprite{}
+	for _, name := range names {
+		img := images[name]
+		hash := imageHash(img)
+		if s, ok := spriteByHash[hash]; ok {
+			s.names = append(s.names, name)
+			continue
+		}
+		s := &sprite{names: []string{name}, img: img, size: img.Bounds().Size()}
+		spriteByHash[hash] = s
+		sprites = append(sprites, s)
+	}
+
+	// sort by height descending for shelf packing
+	slices.SortStableFunc(sprites, func(a, b *sprite) int {
+		return b.size.Y - a.size.Y
+	})
+
+	area, maxWidth := 0, 0
+	for _, s := range sprites {
+		area += (s.size.X + spritePadding) * (s.size.Y + spritePadding)
+		maxWidth = max(maxWidth, s.size.X)
+	}
+	sheetWidth := max(maxWidth, int(math.Ceil(math.Sqrt(float64(area)))))
+
+	var x, y, shelfHeight, width int
+	for _, s := range sprites {
+		if x > 0 && x+s.size.X > sheetWidth {
+			x = 0
+			y += shelfHeight + spritePadding
+			shelfHeight = 0
+		}
+		s.pos = image.Pt(x, y)
+		x += s.size.X + spritePadding
+		shelfHeight = max(shelfHeight, s.size.Y)
+		width = max(width, s.pos.X+s.size.X)
+	}
+
+	sheet = image.NewRGBA(image.Rect(0, 0, max(width, 1), max(y+shelfHeight, 1)))
+	regions = make(map[string]image.Rectangle, len(images))
+	for _, s := range sprites {
+		r := image.Rectangle{Min: s.pos, Max: s.pos.Add(s.size)}
+		draw.Src.Draw(sheet, r, s.img, s.img.Bounds().Min)
+		for _, name := range s.names {
+			regions[name] = r
+		}
+	}
+
+	return
+}
+
+// imageHash computes a hash of an image's dimensions and pixels.
+func imageHash(img image.Image) (hash [sha1.Size]byte) {
+	bounds := img.Bounds()
+	h := sha1.New()
+	h.Write([]byte(bounds.Size().String()))
+	var px [4]byte
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			px = [4]byte{byte(r >> 8), byte(g >> 8), byte(b >> 8), byte(a >> 8)}
+			h.Write(px[:])
+		}
+	}
+	h.Sum(hash[:0])
+	return
+}